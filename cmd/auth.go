@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kalverra/todoist-jira-sync/config"
+)
+
+// todoistTokenEnv is the .env entry the Todoist token is saved to.
+const todoistTokenEnv = "TODOIST_TOKEN"
+
+// todoistOAuth holds the Todoist OAuth endpoints, replaced in tests.
+type todoistOAuth struct {
+	authorizeURL string
+	tokenURL     string
+}
+
+var defaultTodoistOAuth = todoistOAuth{
+	authorizeURL: "https://todoist.com/oauth/authorize",
+	tokenURL:     "https://todoist.com/oauth/access_token",
+}
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Sign in to get API tokens",
+	// Signing in is how a missing token is fixed, so the config isn't validated.
+	PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+		var err error
+		cfg, err = config.Load(config.WithFlags(cmd.Flags()))
+		return err
+	},
+}
+
+var authTodoistCmd = &cobra.Command{
+	Use:   "todoist",
+	Short: "Sign in to Todoist in the browser and save the token to .env",
+	Long: `Opens the Todoist sign-in page in the browser and waits for Todoist to
+redirect back to a server on 127.0.0.1. The authorization code is exchanged
+for a token using PKCE, and the token is saved as TODOIST_TOKEN in the .env
+file in the working directory.
+
+Requires todoist_oauth_client_id, the client ID of a Todoist app.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		if cfg.TodoistOAuthClientID == "" {
+			return errors.New("todoist_oauth_client_id is required")
+		}
+		ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Minute)
+		defer cancel()
+
+		token, err := defaultTodoistOAuth.authorize(ctx, cfg.TodoistOAuthClientID, func(authURL string) error {
+			fmt.Fprintf(cmd.OutOrStdout(), "Opening %s\n", authURL)
+			return openBrowser(ctx, authURL)
+		})
+		if err != nil {
+			return err
+		}
+		if err := setDotEnv(".env", todoistTokenEnv, token); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Saved the Todoist token to .env as %s\n", todoistTokenEnv)
+		return nil
+	},
+}
+
+func init() {
+	authCmd.AddCommand(authTodoistCmd)
+	rootCmd.AddCommand(authCmd)
+}
+
+// authorize runs the OAuth authorization code flow with PKCE and returns the
+// access token. open is called with the authorization URL the user signs in at.
+func (o todoistOAuth) authorize(ctx context.Context, clientID string, open func(string) error) (string, error) {
+	verifier := randomURLString()
+	challenge := sha256.Sum256([]byte(verifier))
+	state := randomURLString()
+
+	ln, err := (&net.ListenConfig{}).Listen(ctx, "tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", fmt.Errorf("listen for oauth redirect: %w", err)
+	}
+	redirectURI := "http://" + ln.Addr().String() + "/callback"
+
+	codes := make(chan string, 1)
+	errs := make(chan error, 1)
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			switch {
+			case r.URL.Path != "/callback":
+				http.NotFound(w, r)
+				return
+			case q.Get("state") != state:
+				http.Error(w, "state mismatch", http.StatusBadRequest)
+				return
+			case q.Get("error") != "" || q.Get("code") == "":
+				http.Error(w, "authorization failed", http.StatusBadRequest)
+				errs <- fmt.Errorf("todoist authorization failed: %q", q.Get("error"))
+				return
+			}
+			_, _ = w.Write([]byte("Signed in to Todoist, you can close this tab.\n"))
+			codes <- q.Get("code")
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() { _ = srv.Serve(ln) }()
+	defer func() { _ = srv.Close() }()
+
+	authURL := o.authorizeURL + "?" + url.Values{
+		"client_id":             {clientID},
+		"scope":                 {"data:read_write"},
+		"state":                 {state},
+		"redirect_uri":          {redirectURI},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}.Encode()
+	if err := open(authURL); err != nil {
+		return "", fmt.Errorf("open browser: %w", err)
+	}
+
+	var code string
+	select {
+	case code = <-codes:
+	case err := <-errs:
+		return "", err
+	case <-ctx.Done():
+		return "", fmt.Errorf("wait for todoist authorization: %w", ctx.Err())
+	}
+	return o.exchange(ctx, clientID, code, verifier, redirectURI)
+}
+
+// exchange trades an authorization code for an access token.
+func (o todoistOAuth) exchange(ctx context.Context, clientID, code, verifier, redirectURI string) (string, error) {
+	form := url.Values{
+		"client_id":     {clientID},
+		"code":          {code},
+		"code_verifier": {verifier},
+		"grant_type":    {"authorization_code"},
+		"redirect_uri":  {redirectURI},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("exchange todoist authorization code: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("exchange todoist authorization code: %s", resp.Status)
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("decode todoist token response: %w", err)
+	}
+	if result.AccessToken == "" {
+		return "", errors.New("todoist token response has no access_token")
+	}
+	return result.AccessToken, nil
+}
+
+// randomURLString returns 32 random bytes as unpadded base64url, 43 characters,
+// the shortest PKCE code verifier allowed.
+func randomURLString() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// openBrowser opens target in the system browser.
+func openBrowser(ctx context.Context, target string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.CommandContext(ctx, "open", target)
+	case "windows":
+		cmd = exec.CommandContext(ctx, "rundll32", "url.dll,FileProtocolHandler", target)
+	default:
+		cmd = exec.CommandContext(ctx, "xdg-open", target)
+	}
+	return cmd.Start()
+}
+
+// setDotEnv sets key to value in the .env file at path, replacing an existing
+// entry for key and keeping the rest of the file.
+func setDotEnv(path, key, value string) error {
+	data, err := os.ReadFile(path) //nolint:gosec // path is the .env file in the working directory
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+	var lines []string
+	if len(data) > 0 {
+		lines = strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	}
+	entry := key + "=" + value
+	replaced := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), key+"=") {
+			lines[i] = entry
+			replaced = true
+		}
+	}
+	if !replaced {
+		lines = append(lines, entry)
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTodoistOAuthAuthorize(t *testing.T) {
+	t.Parallel()
+
+	challenges := make(chan string, 1)
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "client-id", r.PostForm.Get("client_id"))
+		assert.Equal(t, "the-code", r.PostForm.Get("code"))
+		assert.Equal(t, "authorization_code", r.PostForm.Get("grant_type"))
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		assert.Equal(t, <-challenges, base64.RawURLEncoding.EncodeToString(sum[:]), "verifier must match the challenge")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"access_token": "the-token", "token_type": "Bearer"})
+	}))
+	t.Cleanup(tokenSrv.Close)
+
+	oauth := todoistOAuth{authorizeURL: "https://todoist.example/oauth/authorize", tokenURL: tokenSrv.URL}
+	// The browser signs in and follows Todoist's redirect back to the local server.
+	browser := func(authURL string) error {
+		u, err := url.Parse(authURL)
+		require.NoError(t, err)
+		q := u.Query()
+		assert.Equal(t, "client-id", q.Get("client_id"))
+		assert.Equal(t, "S256", q.Get("code_challenge_method"))
+		challenges <- q.Get("code_challenge")
+
+		redirect := q.Get("redirect_uri") + "?" + url.Values{"code": {"the-code"}, "state": {q.Get("state")}}.Encode()
+		go func() {
+			resp, err := http.Get(redirect) //nolint:noctx // test browser
+			if assert.NoError(t, err) {
+				_ = resp.Body.Close()
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+			}
+		}()
+		return nil
+	}
+
+	token, err := oauth.authorize(t.Context(), "client-id", browser)
+	require.NoError(t, err)
+	assert.Equal(t, "the-token", token)
+}
+
+func TestSetDotEnv(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, setDotEnv(path, "TODOIST_TOKEN", "first"))
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "TODOIST_TOKEN=first\n", string(got))
+
+	require.NoError(t, os.WriteFile(path, []byte("JIRA_URL=example.atlassian.net\nTODOIST_TOKEN=first\n"), 0600))
+	require.NoError(t, setDotEnv(path, "TODOIST_TOKEN", "second"))
+	got, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "JIRA_URL=example.atlassian.net\nTODOIST_TOKEN=second\n", string(got))
+}
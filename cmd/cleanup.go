@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+)
+
+var cleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Clean up Todoist tasks linked to Jira issues that no longer exist",
+	Long: `With --orphans, finds linked Todoist tasks whose Jira issue was deleted or
+can no longer be seen, and labels them jira-orphaned, closes them, or deletes
+them depending on orphan_action. With --dry-run, they're only logged.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		if orphans, _ := cmd.Flags().GetBool("orphans"); !orphans {
+			return errors.New("nothing to clean up, pass --orphans")
+		}
+		engine, err := newEngine()
+		if err != nil {
+			return err
+		}
+		defer closeEngine(engine)
+
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		return engine.CleanupOrphanedTasks(cmd.Context(), dryRun)
+	},
+}
+
+func init() {
+	cleanupCmd.Flags().Bool("orphans", false, "Clean up tasks whose linked Jira issue no longer exists")
+	addDryRunFlag(cleanupCmd, "Log orphaned tasks without changing them")
+	rootCmd.AddCommand(cleanupCmd)
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kalverra/todoist-jira-sync/config"
+	"github.com/kalverra/todoist-jira-sync/jira"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and validate configuration",
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate configuration against the Jira project",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		jiraClient, err := jira.NewClient(cfg, logger)
+		if err != nil {
+			return err
+		}
+		if err := cfg.ValidateWithJira(cmd.Context(), jiraClient); err != nil {
+			return err
+		}
+		if err := cfg.ValidateComponents(cmd.Context(), jiraClient); err != nil {
+			return err
+		}
+		if cfg.AssigneeIsCurrentUser() {
+			user, err := jiraClient.GetCurrentUser(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("get current jira user for jira_assignee_filter: %w", err)
+			}
+			fmt.Printf("syncing issues assigned to %s\n", user.DisplayName)
+		}
+		fmt.Println("config is valid")
+		return nil
+	},
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Print a sample YAML config file",
+	Long: "Print a sample YAML config file documenting every option. Save it as " +
+		".todoist-jira-sync.yaml or ~/.config/todoist-jira-sync/config.yaml.",
+	// Printing the sample doesn't need a valid config.
+	PersistentPreRunE: func(*cobra.Command, []string) error { return nil },
+	Run: func(cmd *cobra.Command, _ []string) {
+		_, _ = cmd.OutOrStdout().Write(config.SampleYAML)
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configInitCmd)
+	configCmd.AddCommand(configValidateCmd)
+	rootCmd.AddCommand(configCmd)
+}
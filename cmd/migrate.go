@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/kalverra/todoist-jira-sync/syncer"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Convert legacy [PROJ-123] task prefixes to Jira links",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		engine, err := newEngine()
+		if err != nil {
+			return err
+		}
+		defer closeEngine(engine)
+
+		return syncer.MigrateContentPrefixLinks(cmd.Context(), engine)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+}
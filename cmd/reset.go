@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+)
+
+var resetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Clear the sync state so the next cycle re-syncs everything",
+	Long: `Clears the state file: field hashes used to skip unchanged items and the
+queue of failed actions waiting to be retried. The next sync cycle compares
+every linked task and issue in full, as if it were the first.
+
+Requires --yes.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+			return errors.New("reset clears all sync state, pass --yes to confirm")
+		}
+		engine, err := newEngine()
+		if err != nil {
+			return err
+		}
+		defer closeEngine(engine)
+
+		return engine.ResetState(cmd.Context())
+	},
+}
+
+func init() {
+	resetCmd.Flags().Bool("yes", false, "Confirm clearing the sync state")
+	rootCmd.AddCommand(resetCmd)
+}
@@ -3,7 +3,9 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
@@ -13,6 +15,9 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/kalverra/todoist-jira-sync/config"
+	"github.com/kalverra/todoist-jira-sync/jira"
+	"github.com/kalverra/todoist-jira-sync/syncer"
+	"github.com/kalverra/todoist-jira-sync/todoist"
 )
 
 var (
@@ -27,7 +32,7 @@ var rootCmd = &cobra.Command{
 		`statuses, and due dates between Todoist and Jira Cloud.`,
 	PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
 		var err error
-		cfg, err = config.Load(config.WithFlags(cmd.PersistentFlags()))
+		cfg, err = config.Load(config.WithFlags(cmd.Flags()))
 		if err != nil {
 			return err
 		}
@@ -63,12 +68,49 @@ var rootCmd = &cobra.Command{
 		logger.Info().
 			Str("todoist_project", cfg.TodoistProject).
 			Str("jira_url", cfg.JiraURL).
+			Str("jira_auth_type", cfg.JiraAuthType).
 			Str("jira_email", cfg.JiraEmail).
 			Str("jira_project", cfg.JiraProject).
 			Strs("jira_issue_types", cfg.JiraIssueTypes).
+			Str("default_issue_type", cfg.DefaultIssueType).
+			Str("todoist_due_date_field", cfg.TodoistDueDateField).
+			Str("orphan_action", cfg.OrphanAction).
+			Str("sync_direction", cfg.SyncDirection).
+			Strs("jira_components", cfg.JiraComponents).
+			Strs("jira_fix_versions", cfg.JiraFixVersions).
+			Strs("exclude_labels", cfg.ExcludeLabels).
+			Strs("include_labels", cfg.IncludeLabels).
+			Bool("skip_recurring_tasks", cfg.SkipRecurringTasks).
+			Strs("exclude_jira_keys", cfg.ExcludeJiraKeys).
+			Str("jira_assignee_filter", cfg.JiraAssigneeFilter).
+			Int("jira_board", cfg.JiraBoard).
+			Strs("jira_sprint_states", cfg.SprintStates()).
+			Bool("sync_backlog", cfg.SyncBacklog).
+			Bool("use_sprint_end_as_due_date", cfg.UseSprintEndAsDueDate).
 			Str("interval", cfg.Interval.String()).
+			Str("completed_lookback", cfg.CompletedLookback.String()).
 			Str("log_level", cfg.LogLevel).
 			Str("log_file_path", cfg.LogFilePath).
+			Str("state_file_path", cfg.StateFilePath).
+			Str("summary_log_file", cfg.SummaryLogFile).
+			Bool("field_level_sync", cfg.FieldLevelSync).
+			Bool("verbose", cfg.Verbose).
+			Bool("sync_issue_links", cfg.SyncIssueLinks).
+			Bool("sync_attachments", cfg.SyncAttachments).
+			Bool("sync_attachment_content", cfg.SyncAttachmentContent).
+			Int64("max_attachment_bytes", cfg.MaxAttachmentBytes).
+			Bool("sync_fix_versions", cfg.SyncFixVersions).
+			Bool("sync_watchers", cfg.SyncWatchers).
+			Bool("comment_attribute_users", cfg.CommentAttributeUsers).
+			Bool("sync_duration", cfg.SyncDuration).
+			Int("max_retry", cfg.MaxRetry).
+			Int("sync_concurrency", cfg.SyncConcurrency).
+			Int("todoist_max_retries", cfg.TodoistMaxRetries).
+			Int("jira_max_retries", cfg.JiraMaxRetries).
+			Str("todoist_request_timeout", cfg.TodoistRequestTimeout.String()).
+			Str("jira_request_timeout", cfg.JiraRequestTimeout.String()).
+			Str("sync_timeout_per_cycle", cfg.SyncTimeoutPerCycle.String()).
+			Int("max_sync_items", cfg.MaxSyncItems).
 			Msg("config")
 
 		return nil
@@ -77,6 +119,11 @@ var rootCmd = &cobra.Command{
 
 func init() {
 	flags := rootCmd.PersistentFlags()
+	flags.String(
+		"config",
+		"",
+		"Config file (.yaml, .toml, or .env) to read instead of searching for one (env: TODOIST_JIRA_SYNC_CONFIG)",
+	)
 	flags.String("todoist-token", "", "Todoist API token (env: TODOIST_TOKEN)")
 	flags.String("todoist-project", config.DefaultTodoistProject, "Todoist project name to sync (env: TODOIST_PROJECT)")
 	flags.String("jira-url", "", "Jira Cloud base URL (env: JIRA_URL)")
@@ -88,9 +135,122 @@ func init() {
 		config.DefaultJiraIssueTypes,
 		"Jira issue types to sync, e.g. Story,Task,Bug (env: JIRA_ISSUE_TYPES)",
 	)
+	flags.String(
+		"todoist-due-date-field",
+		config.TodoistDueDateFieldDue,
+		"Todoist date synced with the Jira due date: due or deadline (env: TODOIST_DUE_DATE_FIELD)",
+	)
+	flags.String(
+		"default-issue-type",
+		config.DefaultJiraIssueType,
+		"Issue type for Jira issues created from Todoist tasks (env: DEFAULT_ISSUE_TYPE)",
+	)
+	flags.StringSlice(
+		"jira-components",
+		nil,
+		"Only sync Jira issues in these components, e.g. Backend,Infra (env: JIRA_COMPONENTS)",
+	)
+	flags.StringSlice(
+		"jira-fix-versions",
+		nil,
+		"Only sync Jira issues targeting these fix versions, e.g. v2.1.0 (env: JIRA_FIX_VERSIONS)",
+	)
+	flags.StringSlice(
+		"exclude-labels",
+		nil,
+		"Skip Todoist tasks with any of these labels, linked or not (env: EXCLUDE_LABELS)",
+	)
+	flags.StringSlice(
+		"include-labels",
+		nil,
+		"Only create Jira issues from Todoist tasks with one of these labels as well as jira-sync (env: INCLUDE_LABELS)",
+	)
+	flags.StringSlice(
+		"exclude-jira-keys",
+		nil,
+		"Never sync these Jira issues, e.g. PROJ-1,PROJ-2 (env: EXCLUDE_JIRA_KEYS)",
+	)
+	flags.String(
+		"jira-assignee-filter",
+		config.DefaultJiraAssigneeFilter,
+		"Sync Jira issues assigned to currentUser(), EMPTY for unassigned, or an email or account ID "+
+			"(env: JIRA_ASSIGNEE_FILTER)",
+	)
+	flags.Int("jira-board", 0, "Only sync Jira issues on this board ID, 0 for the whole project (env: JIRA_BOARD)")
+	flags.StringSlice(
+		"jira-sprint-states",
+		config.DefaultJiraSprintStates,
+		"Only create Todoist tasks for issues in sprints with these states, e.g. active,future (env: JIRA_SPRINT_STATES)",
+	)
+	flags.Bool(
+		"require-active-sprint",
+		false,
+		"Only create Todoist tasks for issues in an active sprint, overriding --jira-sprint-states "+
+			"(env: REQUIRE_ACTIVE_SPRINT)",
+	)
+	flags.Bool(
+		"sync-backlog",
+		false,
+		"Create Todoist tasks for Jira issues whether or not they're in a sprint (env: SYNC_BACKLOG)",
+	)
 	flags.Duration("interval", config.DefaultInterval, "Polling interval for watch mode (env: SYNC_INTERVAL)")
+	flags.Duration(
+		"completed-lookback",
+		config.DefaultCompletedLookback,
+		"How far back to look for completed Todoist tasks, 0 to disable (env: COMPLETED_LOOKBACK)",
+	)
 	flags.String("log-level", config.DefaultLogLevel, "Log level: trace, debug, info, warn, error (env: LOG_LEVEL)")
 	flags.String("log-file-path", config.DefaultLogFilePath, "Log file path (env: LOG_FILE_PATH)")
+	flags.String(
+		"summary-log-file",
+		"",
+		"Append a JSON line with each sync cycle's summary to this file (env: SUMMARY_LOG_FILE)",
+	)
+	flags.Bool("verbose", false, "List linked pairs that needed no changes in the sync summary (env: VERBOSE)")
+	flags.String("state-file-path", config.DefaultStateFilePath, "Sync state file path (env: STATE_FILE_PATH)")
+	flags.Bool("field-level-sync", false, "Only sync fields that changed since the last cycle (env: FIELD_LEVEL_SYNC)")
+	flags.Bool(
+		"validate-status-map-on-start",
+		false,
+		"Check status_map against Jira before every sync cycle (env: VALIDATE_STATUS_MAP_ON_START)",
+	)
+	flags.Bool(
+		"sync-issue-links",
+		false,
+		"Order Todoist tasks so Jira issues come after the issues blocking them (env: SYNC_ISSUE_LINKS)",
+	)
+	flags.Bool(
+		"sync-duration",
+		false,
+		"Sync Todoist task duration with the Jira original time estimate (env: SYNC_DURATION)",
+	)
+	flags.Int("max-sync-items", 0, "Max new tasks/issues created per cycle, 0 for unlimited (env: MAX_SYNC_ITEMS)")
+	flags.Int("max-retry", config.DefaultMaxRetry, "Times to retry a failed sync action in later cycles (env: MAX_RETRY)")
+	flags.Int(
+		"todoist-max-retries",
+		config.DefaultAPIMaxRetries,
+		"Times to retry a Todoist request after a 503 or 504 (env: TODOIST_MAX_RETRIES)",
+	)
+	flags.Int(
+		"jira-max-retries",
+		config.DefaultAPIMaxRetries,
+		"Times to retry a Jira request after a 503 or 504 (env: JIRA_MAX_RETRIES)",
+	)
+	flags.Duration(
+		"todoist-request-timeout",
+		config.DefaultRequestTimeout,
+		"Max time for a single Todoist request (env: TODOIST_REQUEST_TIMEOUT)",
+	)
+	flags.Duration(
+		"jira-request-timeout",
+		config.DefaultRequestTimeout,
+		"Max time for a single Jira request (env: JIRA_REQUEST_TIMEOUT)",
+	)
+	flags.Duration(
+		"sync-timeout-per-cycle",
+		0,
+		"Max time for a whole sync cycle, 0 for no limit (env: SYNC_TIMEOUT_PER_CYCLE)",
+	)
 }
 
 // Execute runs the root command.
@@ -99,3 +259,119 @@ func Execute() {
 		os.Exit(1)
 	}
 }
+
+// closeEngine closes engine, logging any error. Meant to be deferred.
+func closeEngine(engine *syncer.Engine) {
+	if err := engine.Close(); err != nil {
+		logger.Error().Err(err).Msg("failed to close sync engine")
+	}
+}
+
+// addTargetFlags adds the --issue and --task flags for syncing a single item.
+func addTargetFlags(cmd *cobra.Command) {
+	cmd.Flags().String("issue", "", "Sync only this Jira issue, e.g. PROJ-123")
+	cmd.Flags().String("task", "", "Sync only this Todoist task ID")
+}
+
+// addDryRunFlag adds the --dry-run flag to a command that honors it. It isn't
+// a persistent flag, so commands that would write anyway don't accept it.
+func addDryRunFlag(cmd *cobra.Command, usage string) {
+	cmd.Flags().Bool("dry-run", false, usage)
+}
+
+// addSummaryFlag adds the --summary-file flag for writing the sync summary to a file.
+func addSummaryFlag(cmd *cobra.Command) {
+	cmd.Flags().String("summary-file", "", "Append the sync summary to this file instead of printing it")
+}
+
+// openSummaryOutput returns where the sync summary is written: the file named
+// by --summary-file, or stdout. The returned func closes the file.
+func openSummaryOutput(cmd *cobra.Command) (io.Writer, func(), error) {
+	path, _ := cmd.Flags().GetString("summary-file")
+	if path == "" {
+		return os.Stdout, func() {}, nil
+	}
+	//nolint:gosec // The user picks the summary file
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open summary file: %w", err)
+	}
+	return f, func() {
+		if err := f.Close(); err != nil {
+			logger.Error().Err(err).Str("path", path).Msg("failed to close summary file")
+		}
+	}, nil
+}
+
+// runTargeted syncs the single item named by --issue or --task.
+// It reports false if neither flag is set.
+func runTargeted(ctx context.Context, cmd *cobra.Command, engine *syncer.Engine) (bool, error) {
+	issueKey, _ := cmd.Flags().GetString("issue")
+	taskID, _ := cmd.Flags().GetString("task")
+	switch {
+	case issueKey == "" && taskID == "":
+		return false, nil
+	case issueKey != "" && taskID != "":
+		return true, errors.New("--issue and --task can't be used together")
+	}
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		return true, errors.New("--dry-run can't be used with --issue or --task")
+	}
+
+	if issueKey != "" {
+		return true, engine.SyncIssue(ctx, issueKey)
+	}
+	return true, engine.SyncTask(ctx, taskID)
+}
+
+// runCycle runs a single sync cycle, or previews it if --dry-run is set.
+func runCycle(ctx context.Context, cmd *cobra.Command, engine *syncer.Engine) error {
+	if cfg.SyncTimeoutPerCycle > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.SyncTimeoutPerCycle)
+		defer cancel()
+	}
+	start := time.Now()
+	var err error
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		err = engine.DryRun(ctx)
+	} else {
+		err = engine.Run(ctx)
+	}
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		elapsed := time.Since(start)
+		logger.Error().
+			Err(err).
+			Dur("elapsed", elapsed).
+			Dur("sync_timeout_per_cycle", cfg.SyncTimeoutPerCycle).
+			Msg("sync cycle timed out")
+		// Wrap ctx.Err() too, so callers can tell a timeout apart with errors.Is
+		// however the engine wrapped it.
+		return fmt.Errorf("sync cycle timed out after %s (%w): %w", elapsed.Truncate(time.Millisecond), ctx.Err(), err)
+	}
+	return err
+}
+
+// newEngine builds a sync engine from the loaded config.
+func newEngine() (*syncer.Engine, error) {
+	todoistClient := todoist.NewClient(
+		cfg.TodoistToken, logger,
+		todoist.WithMaxRetries(cfg.TodoistMaxRetries),
+		todoist.WithTimeout(cfg.TodoistRequestTimeout),
+	)
+	jiraClient, err := jira.NewClient(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	state, err := syncer.NewFileStateStore(cfg.StateFilePath)
+	if err != nil {
+		return nil, err
+	}
+	return syncer.NewEngine(
+		syncer.WithTodoistClient(todoistClient),
+		syncer.WithJiraClient(jiraClient),
+		syncer.WithConfig(cfg),
+		syncer.WithLogger(logger),
+		syncer.WithStateStore(state),
+	)
+}
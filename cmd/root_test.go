@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenSummaryOutput(t *testing.T) {
+	t.Parallel()
+
+	cmd := &cobra.Command{}
+	addSummaryFlag(cmd)
+	out, closeOut, err := openSummaryOutput(cmd)
+	require.NoError(t, err)
+	closeOut()
+	assert.Equal(t, os.Stdout, out, "defaults to stdout")
+
+	path := filepath.Join(t.TempDir(), "summary.txt")
+	require.NoError(t, cmd.Flags().Set("summary-file", path))
+	for _, line := range []string{"first", "second"} {
+		out, closeOut, err := openSummaryOutput(cmd)
+		require.NoError(t, err)
+		_, err = fmt.Fprintln(out, line)
+		require.NoError(t, err)
+		closeOut()
+	}
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "first\nsecond\n", string(got), "appends to the file")
+}
+
+func TestDryRunFlag(t *testing.T) {
+	t.Parallel()
+
+	honored := []string{"sync", "watch", "cleanup"}
+	for _, cmd := range rootCmd.Commands() {
+		flag := cmd.Flags().Lookup("dry-run")
+		assert.Equal(t, slices.Contains(honored, cmd.Name()), flag != nil, cmd.Name())
+	}
+}
@@ -0,0 +1,303 @@
+package cmd
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kalverra/todoist-jira-sync/config"
+)
+
+// maxWebhookBody caps the size of a webhook request body.
+const maxWebhookBody = 1 << 20
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve webhooks that trigger syncs, e.g. from Jira automations",
+	Long: `Listens on webhook_addr for:
+
+  POST /webhook/jira  syncs the issue in a Jira webhook payload
+  POST /webhook/sync  runs a full sync cycle
+
+Both respond 202 Accepted and sync in the background, one sync at a time.
+Requests received during a sync are combined into the next one, and a full
+sync cycle covers any issues waiting with it.
+
+Requests must have an X-Hub-Signature-256 header with the HMAC-SHA256 of the
+body using webhook_secret. Without webhook_secret, serve only starts with
+--insecure, accepting unsigned requests.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		if insecure, _ := cmd.Flags().GetBool("insecure"); !insecure && cfg.WebhookSecret == "" {
+			return errors.New("webhook_secret isn't set, set it or pass --insecure to accept unsigned webhook requests")
+		}
+		engine, err := newEngine()
+		if err != nil {
+			return err
+		}
+		defer closeEngine(engine)
+
+		ctx, stop := signal.NotifyContext(
+			cmd.Context(), syscall.SIGINT, syscall.SIGTERM,
+		)
+		defer stop()
+
+		if cfg.WebhookSecret == "" {
+			logger.Warn().Msg("webhook_secret isn't set, accepting unsigned webhook requests")
+		}
+
+		// Syncs get their own context so a shutdown signal lets in-flight
+		// syncs finish instead of aborting them halfway through.
+		syncCtx, cancelSyncs := context.WithCancel(context.WithoutCancel(ctx))
+		defer cancelSyncs()
+		hooks := newWebhookHandler(syncCtx, engine, cfg.WebhookSecret)
+		srv := &http.Server{
+			Addr:              cfg.WebhookAddr,
+			Handler:           hooks,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		serveErr := make(chan error, 1)
+		go func() {
+			serveErr <- srv.ListenAndServe()
+		}()
+		logger.Info().Str("addr", cfg.WebhookAddr).Msg("serving webhooks")
+
+		select {
+		case err := <-serveErr:
+			return fmt.Errorf("serve webhooks: %w", err)
+		case <-ctx.Done():
+		}
+
+		logger.Info().Msg("shutting down webhook server")
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cfg.ShutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			logger.Error().Err(err).Msg("failed to shut down webhook server")
+		}
+		if err := hooks.wait(shutdownCtx); err != nil {
+			logger.Warn().
+				Dur("shutdown_timeout", cfg.ShutdownTimeout).
+				Msg("syncs didn't finish before shutdown timeout, cancelling them")
+			cancelSyncs()
+		}
+		return nil
+	},
+}
+
+// webhookSyncer is the part of syncer.Engine used by webhookHandler.
+type webhookSyncer interface {
+	Run(ctx context.Context) error
+	SyncIssue(ctx context.Context, jiraKey string) error
+}
+
+// jiraWebhookPayload is the part of a Jira webhook body we care about.
+type jiraWebhookPayload struct {
+	Issue struct {
+		Key string `json:"key"`
+	} `json:"issue"`
+}
+
+// webhookHandler serves the webhook endpoints. Syncs run in the background
+// after the request is accepted, one at a time since the engine isn't safe
+// for concurrent use. Requests received while a sync runs are combined into a
+// single pending batch, so a burst of webhooks doesn't queue up syncs.
+type webhookHandler struct {
+	mux    *http.ServeMux
+	ctx    context.Context //nolint:containedctx // Syncs outlive the request that triggered them
+	syncer webhookSyncer
+	secret []byte
+
+	mu       sync.Mutex
+	running  bool
+	pending  syncBatch
+	inFlight sync.WaitGroup
+}
+
+// syncBatch is the sync work requested by webhooks: a full sync cycle, or
+// the issues to sync.
+type syncBatch struct {
+	full bool
+	keys []string
+}
+
+// add merges other into b. A full sync cycle syncs every issue, so it
+// replaces the issue keys.
+func (b *syncBatch) add(other syncBatch) {
+	b.full = b.full || other.full
+	if b.full {
+		b.keys = nil
+		return
+	}
+	for _, key := range other.keys {
+		if !slices.Contains(b.keys, key) {
+			b.keys = append(b.keys, key)
+		}
+	}
+}
+
+func (b *syncBatch) empty() bool {
+	return !b.full && len(b.keys) == 0
+}
+
+// newWebhookHandler returns a handler that runs syncs with ctx. An empty
+// secret accepts unsigned requests.
+func newWebhookHandler(ctx context.Context, s webhookSyncer, secret string) *webhookHandler {
+	h := &webhookHandler{
+		mux:    http.NewServeMux(),
+		ctx:    ctx,
+		syncer: s,
+		secret: []byte(secret),
+	}
+	h.mux.HandleFunc("POST /webhook/jira", h.handleJira)
+	h.mux.HandleFunc("POST /webhook/sync", h.handleSync)
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *webhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *webhookHandler) handleJira(w http.ResponseWriter, r *http.Request) {
+	body, ok := h.readBody(w, r)
+	if !ok {
+		return
+	}
+	var payload jiraWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Issue.Key == "" {
+		http.Error(w, "body must be a Jira webhook payload with issue.key", http.StatusBadRequest)
+		return
+	}
+
+	key := payload.Issue.Key
+	logger.Info().Str("issue_key", key).Msg("jira webhook received, syncing issue")
+	h.queue(syncBatch{keys: []string{key}})
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *webhookHandler) handleSync(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.readBody(w, r); !ok {
+		return
+	}
+	logger.Info().Msg("sync webhook received, running sync cycle")
+	h.queue(syncBatch{full: true})
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// readBody reads the request body and checks its signature, writing an
+// error response and reporting false if either fails.
+func (h *webhookHandler) readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return nil, false
+	}
+	if len(h.secret) > 0 && !validSignature(h.secret, body, r.Header.Get("X-Hub-Signature-256")) {
+		logger.Warn().Str("path", r.URL.Path).Msg("rejected webhook request with invalid signature")
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return nil, false
+	}
+	return body, true
+}
+
+// queue adds batch to the pending sync work, starting a background sync
+// unless one is already running, in which case it picks up the work next.
+func (h *webhookHandler) queue(batch syncBatch) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pending.add(batch)
+	if h.running {
+		return
+	}
+	h.running = true
+	h.inFlight.Go(h.drain)
+}
+
+// drain runs the pending sync work until none is left.
+func (h *webhookHandler) drain() {
+	for {
+		h.mu.Lock()
+		batch := h.pending
+		h.pending = syncBatch{}
+		if batch.empty() {
+			h.running = false
+			h.mu.Unlock()
+			return
+		}
+		h.mu.Unlock()
+
+		if batch.full {
+			if err := h.syncer.Run(h.ctx); err != nil {
+				logger.Error().Err(err).Msg("webhook sync failed")
+			}
+			continue
+		}
+		for _, key := range batch.keys {
+			if err := h.syncer.SyncIssue(h.ctx, key); err != nil {
+				logger.Error().Err(err).Str("issue_key", key).Msg("webhook sync failed")
+			}
+		}
+	}
+}
+
+// wait waits for background syncs to finish, or for ctx to be done.
+func (h *webhookHandler) wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		h.inFlight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// validSignature reports whether header is "sha256=" followed by the hex
+// HMAC-SHA256 of body, as sent in X-Hub-Signature-256.
+func validSignature(secret, body []byte, header string) bool {
+	sig, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+func init() {
+	flags := serveCmd.Flags()
+	flags.String("webhook-addr", config.DefaultWebhookAddr, "Address to serve webhooks on (env: WEBHOOK_ADDR)")
+	flags.String(
+		"webhook-secret",
+		"",
+		"Secret for the X-Hub-Signature-256 HMAC on webhook requests (env: WEBHOOK_SECRET)",
+	)
+	flags.Bool("insecure", false, "Accept unsigned webhook requests when webhook_secret isn't set")
+	flags.Duration(
+		"shutdown-timeout",
+		config.DefaultShutdownTimeout,
+		"Time to let in-flight syncs finish after SIGINT or SIGTERM (env: SHUTDOWN_TIMEOUT)",
+	)
+	rootCmd.AddCommand(serveCmd)
+}
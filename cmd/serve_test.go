@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeWebhookSyncer struct {
+	mu      sync.Mutex
+	calls   []string
+	release chan struct{} // if set, syncs block until it's closed
+}
+
+func (f *fakeWebhookSyncer) Run(context.Context) error {
+	f.record("run")
+	return nil
+}
+
+func (f *fakeWebhookSyncer) SyncIssue(_ context.Context, jiraKey string) error {
+	f.record("issue " + jiraKey)
+	return nil
+}
+
+func (f *fakeWebhookSyncer) record(call string) {
+	f.mu.Lock()
+	f.calls = append(f.calls, call)
+	f.mu.Unlock()
+	if f.release != nil {
+		<-f.release
+	}
+}
+
+func (f *fakeWebhookSyncer) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.calls)
+}
+
+func sign(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestWebhookHandler(t *testing.T) {
+	t.Parallel()
+
+	const (
+		secret = "s3cret"
+		issue  = `{"webhookEvent":"jira:issue_updated","issue":{"key":"PROJ-123"}}`
+	)
+	tests := []struct {
+		name      string
+		secret    string
+		method    string
+		path      string
+		body      string
+		signature string
+		wantCode  int
+		wantCalls []string
+	}{
+		{
+			name:      "jira issue",
+			secret:    secret,
+			path:      "/webhook/jira",
+			body:      issue,
+			signature: sign(secret, issue),
+			wantCode:  http.StatusAccepted,
+			wantCalls: []string{"issue PROJ-123"},
+		},
+		{
+			name:      "full sync",
+			secret:    secret,
+			path:      "/webhook/sync",
+			signature: sign(secret, ""),
+			wantCode:  http.StatusAccepted,
+			wantCalls: []string{"run"},
+		},
+		{
+			name:      "unsigned without secret",
+			path:      "/webhook/jira",
+			body:      issue,
+			wantCode:  http.StatusAccepted,
+			wantCalls: []string{"issue PROJ-123"},
+		},
+		{
+			name:     "missing signature",
+			secret:   secret,
+			path:     "/webhook/jira",
+			body:     issue,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:      "wrong secret",
+			secret:    secret,
+			path:      "/webhook/sync",
+			signature: sign("other", ""),
+			wantCode:  http.StatusUnauthorized,
+		},
+		{
+			name:      "no issue key",
+			secret:    secret,
+			path:      "/webhook/jira",
+			body:      `{"issue":{}}`,
+			signature: sign(secret, `{"issue":{}}`),
+			wantCode:  http.StatusBadRequest,
+		},
+		{
+			name:     "wrong method",
+			method:   http.MethodGet,
+			path:     "/webhook/sync",
+			wantCode: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fake := &fakeWebhookSyncer{}
+			h := newWebhookHandler(context.Background(), fake, tt.secret)
+			method := tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, tt.path, strings.NewReader(tt.body))
+			if tt.signature != "" {
+				req.Header.Set("X-Hub-Signature-256", tt.signature)
+			}
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+			require.NoError(t, h.wait(context.Background()))
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, tt.wantCalls, fake.calls)
+		})
+	}
+}
+
+func TestWebhookHandlerCoalescesSyncs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		requests  []string
+		wantCalls []string
+	}{
+		{
+			name:      "issues",
+			requests:  []string{"PROJ-2", "PROJ-3", "PROJ-2"},
+			wantCalls: []string{"issue PROJ-1", "issue PROJ-2", "issue PROJ-3"},
+		},
+		{
+			name:      "full sync covers issues",
+			requests:  []string{"PROJ-2", "sync", "PROJ-3"},
+			wantCalls: []string{"issue PROJ-1", "run"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fake := &fakeWebhookSyncer{release: make(chan struct{})}
+			h := newWebhookHandler(context.Background(), fake, "")
+			send := func(keyOrSync string) {
+				req := httptest.NewRequest(http.MethodPost, "/webhook/sync", nil)
+				if keyOrSync != "sync" {
+					body := `{"issue":{"key":"` + keyOrSync + `"}}`
+					req = httptest.NewRequest(http.MethodPost, "/webhook/jira", strings.NewReader(body))
+				}
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, req)
+				require.Equal(t, http.StatusAccepted, rec.Code)
+			}
+
+			send("PROJ-1")
+			require.Eventually(t, func() bool { return fake.callCount() == 1 }, time.Second, time.Millisecond)
+			for _, request := range tt.requests {
+				send(request)
+			}
+			close(fake.release)
+			require.NoError(t, h.wait(context.Background()))
+			assert.Equal(t, tt.wantCalls, fake.calls)
+		})
+	}
+}
@@ -1,30 +1,33 @@
 package cmd
 
-import (
-	"github.com/spf13/cobra"
-
-	"github.com/kalverra/todoist-jira-sync/jira"
-	"github.com/kalverra/todoist-jira-sync/syncer"
-	"github.com/kalverra/todoist-jira-sync/todoist"
-)
+import "github.com/spf13/cobra"
 
 var syncCmd = &cobra.Command{
 	Use:   "sync",
 	Short: "Run a single sync cycle between Todoist and Jira",
 	RunE: func(cmd *cobra.Command, _ []string) error {
-		todoistClient := todoist.NewClient(cfg.TodoistToken, logger)
-		jiraClient, err := jira.NewClient(cfg, logger)
+		engine, err := newEngine()
+		if err != nil {
+			return err
+		}
+		defer closeEngine(engine)
+		out, closeOut, err := openSummaryOutput(cmd)
 		if err != nil {
 			return err
 		}
-		engine := syncer.NewEngine(
-			todoistClient, jiraClient, cfg, logger,
-		)
+		defer closeOut()
+		engine.SetSummaryOutput(out)
 
-		return engine.Run(cmd.Context())
+		if targeted, err := runTargeted(cmd.Context(), cmd, engine); targeted {
+			return err
+		}
+		return runCycle(cmd.Context(), cmd, engine)
 	},
 }
 
 func init() {
+	addDryRunFlag(syncCmd, "Preview sync changes without writing to Todoist or Jira")
+	addTargetFlags(syncCmd)
+	addSummaryFlag(syncCmd)
 	rootCmd.AddCommand(syncCmd)
 }
@@ -1,60 +1,299 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
 
-	"github.com/kalverra/todoist-jira-sync/jira"
+	"github.com/kalverra/todoist-jira-sync/config"
 	"github.com/kalverra/todoist-jira-sync/syncer"
-	"github.com/kalverra/todoist-jira-sync/todoist"
 )
 
 var watchCmd = &cobra.Command{
 	Use:   "watch",
 	Short: "Continuously sync Todoist and Jira on a polling interval",
 	RunE: func(cmd *cobra.Command, _ []string) error {
-		todoistClient := todoist.NewClient(cfg.TodoistToken, logger)
-		jiraClient, err := jira.NewClient(cfg, logger)
+		engine, err := newEngine()
 		if err != nil {
 			return err
 		}
-		engine := syncer.NewEngine(
-			todoistClient, jiraClient, cfg, logger,
-		)
+		defer closeEngine(engine)
+		out, closeOut, err := openSummaryOutput(cmd)
+		if err != nil {
+			return err
+		}
+		defer closeOut()
+		engine.SetSummaryOutput(out)
 
 		ctx, stop := signal.NotifyContext(
 			cmd.Context(), syscall.SIGINT, syscall.SIGTERM,
 		)
 		defer stop()
 
+		reload := make(chan os.Signal, 1)
+		signal.Notify(reload, syscall.SIGHUP)
+		defer signal.Stop(reload)
+
 		logger.Info().
 			Dur("interval", cfg.Interval).
 			Msg("starting watch mode")
 
-		if err := engine.Run(ctx); err != nil {
-			logger.Error().Err(err).Msg("sync cycle failed")
+		if _, err := runTargeted(ctx, cmd, engine); err != nil {
+			logger.Error().Err(err).Msg("single item sync failed")
+		}
+
+		if err := initialDelay(ctx); err != nil {
+			logger.Info().Msg("shutting down watch mode")
+			return nil
+		}
+
+		return runWithBackoff(ctx, cmd, engine, out, reload)
+	},
+}
+
+// backoff tracks the wait between watch mode cycles. It starts at the
+// interval, doubles after every failed cycle up to a max, and resets to the
+// interval after a successful one.
+type backoff struct {
+	interval time.Duration
+	max      time.Duration
+	current  time.Duration
+}
+
+// newBackoff returns a backoff starting at interval. A max of 0 means 10 * interval.
+func newBackoff(interval, maxBackoff time.Duration) *backoff {
+	if maxBackoff <= 0 {
+		maxBackoff = 10 * interval
+	}
+	return &backoff{interval: interval, max: max(maxBackoff, interval), current: interval}
+}
+
+// next returns the wait before the next cycle, given the result of the last one.
+// A cycle that ran past Config.SyncTimeoutPerCycle doesn't grow the wait, since
+// waiting longer doesn't make the next cycle any faster.
+func (b *backoff) next(err error) time.Duration {
+	switch {
+	case err == nil:
+		b.current = b.interval
+	case !errors.Is(err, context.DeadlineExceeded):
+		b.current = min(b.current*2, b.max)
+	}
+	return b.current
+}
+
+// runWithBackoff runs sync cycles until ctx is done, waiting Config.Interval
+// between them, or longer after failures. A signal on reload reloads the config,
+// replacing engine, which is closed on return. Every engine prints its summary to summaryOut.
+func runWithBackoff(
+	ctx context.Context,
+	cmd *cobra.Command,
+	engine *syncer.Engine,
+	summaryOut io.Writer,
+	reload <-chan os.Signal,
+) error {
+	defer func() { closeEngine(engine) }()
+
+	// Cycles get their own context so a shutdown signal lets the in-flight
+	// cycle finish instead of aborting it halfway through.
+	cycleCtx, cancelCycles := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelCycles()
+
+	var (
+		inFlight          sync.WaitGroup
+		results           = make(chan error, 1)
+		running           bool
+		pendingReload     bool
+		consecutiveErrors int
+		cycles            int
+		wait              = newBackoff(cfg.Interval, cfg.WatchMaxBackoff)
+	)
+	startCycle := func() {
+		running = true
+		inFlight.Go(func() {
+			results <- runCycle(cycleCtx, cmd, engine)
+		})
+	}
+	applyReload := func() bool {
+		reloaded, err := reloadConfig(cmd)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to reload config, keeping current config")
+			return false
 		}
+		reloaded.SetSummaryOutput(summaryOut)
+		closeEngine(engine)
+		engine = reloaded
+		wait = newBackoff(cfg.Interval, cfg.WatchMaxBackoff)
+		logger.Info().Msgf("config reloaded, new interval: %s", cfg.Interval)
+		return true
+	}
 
-		ticker := time.NewTicker(cfg.Interval)
-		defer ticker.Stop()
+	startCycle()
+	timer := time.NewTimer(cfg.Interval)
+	timer.Stop()
+	defer timer.Stop()
 
-		for {
-			select {
-			case <-ctx.Done():
-				logger.Info().Msg("shutting down watch mode")
+	for {
+		select {
+		case <-ctx.Done():
+			return waitForCycle(ctx, &inFlight, cancelCycles)
+		case err := <-results:
+			running = false
+			next := wait.next(err)
+			if err != nil {
+				consecutiveErrors++
+				logger.Error().
+					Err(err).
+					Int("consecutive_errors", consecutiveErrors).
+					Dur("backoff", next).
+					Msg("sync cycle failed")
+				if cfg.WatchMaxErrors > 0 && consecutiveErrors >= cfg.WatchMaxErrors {
+					logger.Error().
+						Err(err).
+						Int("consecutive_errors", consecutiveErrors).
+						Int("max_errors", cfg.WatchMaxErrors).
+						Msg("max consecutive errors reached, exiting")
+					return fmt.Errorf("%d consecutive sync cycles failed, last error: %w", consecutiveErrors, err)
+				}
+			} else {
+				consecutiveErrors = 0
+			}
+			cycles++
+			if cfg.WatchMaxCycles > 0 && cycles >= cfg.WatchMaxCycles {
+				logger.Info().Int("cycles", cycles).Msg("max cycles reached, exiting")
 				return nil
-			case <-ticker.C:
-				if err := engine.Run(ctx); err != nil {
-					logger.Error().Err(err).Msg("sync cycle failed")
+			}
+			if pendingReload {
+				pendingReload = false
+				if applyReload() {
+					next = cfg.Interval
 				}
 			}
+			timer.Reset(next)
+		case <-timer.C:
+			startCycle()
+		case <-reload:
+			// Let the in-flight cycle finish before swapping the engine out from under it.
+			if running {
+				pendingReload = true
+				continue
+			}
+			if applyReload() {
+				timer.Reset(cfg.Interval)
+			}
 		}
-	},
+	}
+}
+
+// waitForCycle waits up to Config.ShutdownTimeout for an in-flight sync cycle
+// to finish, then cancels it.
+func waitForCycle(ctx context.Context, inFlight *sync.WaitGroup, cancelCycles context.CancelFunc) error {
+	logger.Info().Msg("shutting down watch mode")
+	done := make(chan struct{})
+	go func() {
+		inFlight.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(cfg.ShutdownTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return nil
+	case <-timer.C:
+		logger.Warn().
+			Dur("shutdown_timeout", cfg.ShutdownTimeout).
+			Msg("sync cycle didn't finish before shutdown timeout, cancelling it")
+		cancelCycles()
+		return ctx.Err()
+	}
+}
+
+// reloadConfig loads and validates the config again, then builds an engine
+// with fresh clients from it. The current config is kept if anything fails.
+func reloadConfig(cmd *cobra.Command) (*syncer.Engine, error) {
+	newCfg, err := config.Load(config.WithFlags(cmd.Flags()))
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	if err := newCfg.Validate(); err != nil {
+		return nil, fmt.Errorf("validate config: %w", err)
+	}
+
+	oldCfg := cfg
+	cfg = newCfg
+	engine, err := newEngine()
+	if err != nil {
+		cfg = oldCfg
+		return nil, err
+	}
+	return engine, nil
+}
+
+// initialDelay waits Config.WatchInitialDelay plus a random jitter before the first cycle,
+// so instances started together don't all hit the APIs at once.
+func initialDelay(ctx context.Context) error {
+	delay := cfg.WatchInitialDelay
+	if cfg.WatchJitter > 0 {
+		delay += rand.N(cfg.WatchJitter) //nolint:gosec // Jitter doesn't need a secure random source
+	}
+	if delay <= 0 {
+		return nil
+	}
+
+	logger.Info().Dur("delay", delay).Msg("waiting before first sync cycle")
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
 
 func init() {
+	addDryRunFlag(watchCmd, "Preview sync changes without writing to Todoist or Jira")
+	addTargetFlags(watchCmd)
+	addSummaryFlag(watchCmd)
+	flags := watchCmd.Flags()
+	flags.Duration(
+		"initial-delay",
+		0,
+		"Wait this long before the first sync cycle (env: WATCH_INITIAL_DELAY)",
+	)
+	flags.Duration(
+		"initial-delay-jitter",
+		0,
+		"Add a random delay in [0, jitter) to --initial-delay, to stagger instances (env: WATCH_JITTER)",
+	)
+	flags.Duration(
+		"shutdown-timeout",
+		config.DefaultShutdownTimeout,
+		"Time to let an in-flight sync cycle finish after SIGINT or SIGTERM (env: SHUTDOWN_TIMEOUT)",
+	)
+	flags.Duration(
+		"max-backoff",
+		0,
+		"Max wait between cycles after failures, 0 for 10 * --interval (env: WATCH_MAX_BACKOFF)",
+	)
+	flags.Int(
+		"max-errors",
+		0,
+		"Exit after this many consecutive failed sync cycles, 0 for unlimited (env: WATCH_MAX_ERRORS)",
+	)
+	flags.Int(
+		"max-cycles",
+		0,
+		"Exit after this many sync cycles, 0 for unlimited (env: WATCH_MAX_CYCLES)",
+	)
 	rootCmd.AddCommand(watchCmd)
 }
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kalverra/todoist-jira-sync/config"
+	"github.com/kalverra/todoist-jira-sync/jira"
+	"github.com/kalverra/todoist-jira-sync/syncer"
+	"github.com/kalverra/todoist-jira-sync/todoist"
+)
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	errCycle := errors.New("cycle failed")
+	b := newBackoff(time.Minute, 5*time.Minute)
+	assert.Equal(t, time.Minute, b.next(nil))
+	assert.Equal(t, 2*time.Minute, b.next(errCycle))
+	assert.Equal(t, 4*time.Minute, b.next(errCycle))
+	assert.Equal(t, 5*time.Minute, b.next(errCycle))
+	assert.Equal(t, 5*time.Minute, b.next(errCycle))
+	assert.Equal(t, time.Minute, b.next(nil))
+
+	b = newBackoff(time.Minute, 0)
+	for range 10 {
+		b.next(errCycle)
+	}
+	assert.Equal(t, 10*time.Minute, b.current, "default max is 10 * interval")
+
+	b = newBackoff(time.Minute, time.Second)
+	assert.Equal(t, time.Minute, b.next(errCycle), "max is never below interval")
+
+	b = newBackoff(time.Minute, 5*time.Minute)
+	timedOut := fmt.Errorf("sync cycle timed out: %w", context.DeadlineExceeded)
+	assert.Equal(t, time.Minute, b.next(timedOut), "timeouts don't back off")
+	assert.Equal(t, 2*time.Minute, b.next(errCycle))
+	assert.Equal(t, 2*time.Minute, b.next(timedOut), "timeouts keep the current backoff")
+}
+
+func TestRunCycleTimeout(t *testing.T) { //nolint:paralleltest // replaces the package config and logger
+	// The fake APIs answer no request, so the cycle runs until it times out.
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(srv.Close)
+
+	var logs bytes.Buffer
+	prevCfg, prevLogger := cfg, logger
+	t.Cleanup(func() { cfg, logger = prevCfg, prevLogger })
+	cfg = &config.Config{TodoistProject: "Work", JiraProject: "PROJ", SyncTimeoutPerCycle: 50 * time.Millisecond}
+	logger = zerolog.New(&logs)
+
+	jiraClient, err := jira.NewClient(cfg, zerolog.Nop(), jira.WithBaseURL(srv.URL), jira.WithMaxRetries(0))
+	require.NoError(t, err)
+	state, err := syncer.NewFileStateStore(filepath.Join(t.TempDir(), "state.json"))
+	require.NoError(t, err)
+	engine, err := syncer.NewEngine(
+		syncer.WithTodoistClient(
+			todoist.NewClient("token", zerolog.Nop(), todoist.WithBaseURL(srv.URL), todoist.WithMaxRetries(0)),
+		),
+		syncer.WithJiraClient(jiraClient),
+		syncer.WithConfig(cfg),
+		syncer.WithStateStore(state),
+	)
+	require.NoError(t, err)
+
+	err = runCycle(context.Background(), &cobra.Command{}, engine)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "sync cycle timed out after")
+	assert.Contains(t, logs.String(), `"message":"sync cycle timed out"`)
+	assert.Contains(t, logs.String(), `"sync_timeout_per_cycle":50`)
+}
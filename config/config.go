@@ -2,8 +2,17 @@
 package config
 
 import (
+	"context"
+	_ "embed"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/pflag"
@@ -12,17 +21,87 @@ import (
 
 // Config holds all configuration needed to sync Todoist and Jira.
 type Config struct {
-	TodoistToken   string            `mapstructure:"todoist_token"`
-	TodoistProject string            `mapstructure:"todoist_project"`
-	JiraURL        string            `mapstructure:"jira_url"`
-	JiraEmail      string            `mapstructure:"jira_email"`
-	JiraToken      string            `mapstructure:"jira_token"`
-	JiraProject    string            `mapstructure:"jira_project"`
-	JiraIssueTypes []string          `mapstructure:"jira_issue_types"` // issue type names to sync (e.g. Story, Task, Bug); set via flag/env or default
-	Interval       time.Duration     `mapstructure:"interval"`
-	LogLevel       string            `mapstructure:"log_level"`
-	LogFilePath    string            `mapstructure:"log_file_path"`
-	StatusMap      map[string]string `mapstructure:"status_map"`
+	TodoistToken       string            `mapstructure:"todoist_token"`
+	TodoistProject     string            `mapstructure:"todoist_project"`
+	JiraURL            string            `mapstructure:"jira_url"`
+	JiraEmail          string            `mapstructure:"jira_email"`
+	JiraToken          string            `mapstructure:"jira_token"`
+	JiraAuthType       string            `mapstructure:"jira_auth_type"` // basic (Cloud email and API token) or pat (Data Center personal access token)
+	JiraProject        string            `mapstructure:"jira_project"`
+	JiraIssueTypes     []string          `mapstructure:"jira_issue_types"`      // issue type names to sync (e.g. Story, Task, Bug); set via flag/env or default
+	JiraComponents     []string          `mapstructure:"jira_components"`       // only sync issues in these components; empty syncs all
+	JiraFixVersions    []string          `mapstructure:"jira_fix_versions"`     // only sync issues targeting these fix versions; empty syncs all
+	JiraSearchPageSize int               `mapstructure:"jira_search_page_size"` // issues fetched per Jira search request
+	JiraBoard          int               `mapstructure:"jira_board"`            // only sync issues on this board ID; 0 syncs the whole project
+	ExcludeJiraKeys    []string          `mapstructure:"exclude_jira_keys"`     // never sync these issues, e.g. umbrella epics
+	JiraAssigneeFilter string            `mapstructure:"jira_assignee_filter"`  // sync issues assigned to: currentUser(), EMPTY, or an email or account ID
+	JiraSprintStates   []string          `mapstructure:"jira_sprint_states"`    // only create Todoist tasks for issues in a sprint with one of these states
+	JiraRequiredLabel  string            `mapstructure:"jira_required_label"`   // only sync issues with this label; empty syncs all
+	Interval           time.Duration     `mapstructure:"interval"`
+	CompletedLookback  time.Duration     `mapstructure:"completed_lookback"` // how far back to look for completed Todoist tasks; 0 disables completion sync
+	LogLevel           string            `mapstructure:"log_level"`
+	LogFilePath        string            `mapstructure:"log_file_path"`
+	StatusMap          map[string]string `mapstructure:"status_map"`
+	StateFilePath      string            `mapstructure:"state_file_path"`
+	SummaryLogFile     string            `mapstructure:"summary_log_file"` // append a JSON line per sync cycle summary; empty disables
+	FieldLevelSync     bool              `mapstructure:"field_level_sync"` // only sync fields whose value changed since the last cycle
+	Verbose            bool              `mapstructure:"verbose"`          // list linked pairs that needed no changes in the sync summary
+	MaxRetry           int               `mapstructure:"max_retry"`        // times a failed sync action is retried in later cycles; 0 disables retries
+	MaxSyncItems       int               `mapstructure:"max_sync_items"`   // max new tasks/issues created per cycle; 0 is unlimited
+
+	TodoistDueDateField string            `mapstructure:"todoist_due_date_field"` // todoist date synced with jira duedate: due or deadline
+	ExcludeLabels       []string          `mapstructure:"exclude_labels"`         // skip todoist tasks with any of these labels
+	IncludeLabels       []string          `mapstructure:"include_labels"`         // only create jira issues from tasks with one of these labels; empty allows all
+	RecurringTaskLabel  string            `mapstructure:"recurring_task_label"`   // recurring tasks with this label get Jira issues despite SkipRecurringTasks
+	DefaultIssueType    string            `mapstructure:"default_issue_type"`     // issue type for Jira issues created from Todoist tasks
+	SectionIssueTypeMap map[string]string `mapstructure:"section_issue_type_map"` // todoist section -> jira issue type, overrides DefaultIssueType
+	ComponentSectionMap map[string]string `mapstructure:"component_section_map"`  // jira component -> todoist section, overrides StatusMap
+	OrphanAction        string            `mapstructure:"orphan_action"`          // what cleanup --orphans does to tasks linked to deleted issues
+	SyncDirection       string            `mapstructure:"sync_direction"`         // bidirectional, jira-to-todoist, or todoist-to-jira
+
+	RequireActiveSprint      bool `mapstructure:"require_active_sprint"`        // shorthand for JiraSprintStates: [active]
+	SyncBacklog              bool `mapstructure:"sync_backlog"`                 // create Todoist tasks for issues regardless of sprint, e.g. for Kanban projects
+	UseSprintEndAsDueDate    bool `mapstructure:"use_sprint_end_as_due_date"`   // due Todoist tasks at the end of the active sprint if the issue has no due date
+	ValidateStatusMapOnStart bool `mapstructure:"validate_status_map_on_start"` // check status_map against Jira before every sync cycle
+	SyncDuration             bool `mapstructure:"sync_duration"`                // sync Todoist task duration with Jira original estimate
+	SyncIssueLinks           bool `mapstructure:"sync_issue_links"`             // order Todoist tasks so Jira issues come after the issues blocking them
+	SyncAttachments          bool `mapstructure:"sync_attachments"`             // comment links to Jira attachments on Todoist tasks
+	SyncAttachmentContent    bool `mapstructure:"sync_attachment_content"`      // upload Jira attachments to Todoist instead of linking them
+	SyncFixVersions          bool `mapstructure:"sync_fix_versions"`            // label Todoist tasks with the Jira issue's fix versions
+	SyncWatchers             bool `mapstructure:"sync_watchers"`                // comment the Jira issue's watchers on Todoist tasks
+	CommentAttributeUsers    bool `mapstructure:"comment_attribute_users"`      // name the Todoist commenter in comments synced to Jira
+	SkipRecurringTasks       bool `mapstructure:"skip_recurring_tasks"`         // don't create Jira issues from recurring Todoist tasks
+
+	MaxAttachmentBytes int64 `mapstructure:"max_attachment_bytes"` // larger attachments are linked rather than uploaded
+
+	TodoistMaxRetries int `mapstructure:"todoist_max_retries"` // times a Todoist request is retried after a 503 or 504
+	JiraMaxRetries    int `mapstructure:"jira_max_retries"`    // times a Jira request is retried after a 503 or 504
+
+	TodoistRequestTimeout time.Duration `mapstructure:"todoist_request_timeout"` // max time for a single Todoist request
+	JiraRequestTimeout    time.Duration `mapstructure:"jira_request_timeout"`    // max time for a single Jira request
+	SyncTimeoutPerCycle   time.Duration `mapstructure:"sync_timeout_per_cycle"`  // max time for a whole sync cycle; 0 is no limit
+
+	WatchInitialDelay time.Duration `mapstructure:"watch_initial_delay"` // wait before the first watch mode sync cycle
+	WatchJitter       time.Duration `mapstructure:"watch_jitter"`        // random extra initial delay in [0, WatchJitter)
+	WatchMaxBackoff   time.Duration `mapstructure:"watch_max_backoff"`   // cap on the wait after failed cycles; 0 is 10 * Interval
+	WatchMaxErrors    int           `mapstructure:"watch_max_errors"`    // exit watch mode after this many consecutive failed cycles; 0 is unlimited
+	WatchMaxCycles    int           `mapstructure:"watch_max_cycles"`    // exit watch mode after this many cycles; 0 is unlimited
+	ShutdownTimeout   time.Duration `mapstructure:"shutdown_timeout"`    // time to let an in-flight cycle finish on shutdown
+
+	WebhookAddr   string `mapstructure:"webhook_addr"`   // address the serve command listens on
+	WebhookSecret string `mapstructure:"webhook_secret"` // HMAC secret checked against X-Hub-Signature-256; serve requires it unless --insecure
+
+	TodoistOAuthClientID string `mapstructure:"todoist_oauth_client_id"` // Todoist app client ID used by the auth todoist command
+
+	CommentFromJiraPrefix    string `mapstructure:"comment_from_jira_prefix"`    // prepended to Jira comments synced to Todoist; %s is the author's name
+	CommentFromTodoistPrefix string `mapstructure:"comment_from_todoist_prefix"` // prepended to Todoist comments synced to Jira
+	FixVersionLabelPrefix    string `mapstructure:"fix_version_label_prefix"`    // prepended to fix version names to make Todoist labels
+
+	ProjectPairs    []ProjectPair `mapstructure:"project_pairs"`    // Todoist and Jira projects to sync; empty syncs TodoistProject with JiraProject
+	SyncConcurrency int           `mapstructure:"sync_concurrency"` // project pairs synced at once
+
+	reverseStatusOnce sync.Once
+	reverseStatusMap  map[string]string // todoist section -> jira status, built from StatusMap
 }
 
 const (
@@ -30,12 +109,68 @@ const (
 	DefaultTodoistProject = "Work"
 	// DefaultJiraProject key to sync.
 	DefaultJiraProject = "DX"
+	// TodoistDueDateFieldDue syncs the Todoist due date with the Jira due date.
+	TodoistDueDateFieldDue = "due"
+	// TodoistDueDateFieldDeadline syncs the Todoist deadline with the Jira due date.
+	TodoistDueDateFieldDeadline = "deadline"
+	// SyncDirectionBidirectional syncs changes both ways.
+	SyncDirectionBidirectional = "bidirectional"
+	// SyncDirectionJiraToTodoist only writes to Todoist, e.g. with a read-only Jira token.
+	SyncDirectionJiraToTodoist = "jira-to-todoist"
+	// SyncDirectionTodoistToJira only writes to Jira.
+	SyncDirectionTodoistToJira = "todoist-to-jira"
+	// JiraAuthTypeBasic authenticates to Jira Cloud with JiraEmail and an API token.
+	JiraAuthTypeBasic = "basic"
+	// JiraAuthTypePAT authenticates to Jira Data Center with a personal access token as a Bearer token.
+	JiraAuthTypePAT = "pat"
+	// OrphanActionDelete deletes Todoist tasks linked to deleted Jira issues.
+	OrphanActionDelete = "delete"
+	// OrphanActionClose completes Todoist tasks linked to deleted Jira issues.
+	OrphanActionClose = "close"
+	// OrphanActionLabel labels Todoist tasks linked to deleted Jira issues.
+	OrphanActionLabel = "label"
+	// DefaultRecurringTaskLabel opts recurring Todoist tasks in to getting Jira issues.
+	DefaultRecurringTaskLabel = "jira-sync-recurring"
+	// DefaultJiraIssueType is the issue type for Jira issues created from Todoist tasks.
+	DefaultJiraIssueType = "Story"
+	// DefaultJiraAssigneeFilter syncs issues assigned to the Jira user whose token is used.
+	DefaultJiraAssigneeFilter = "currentUser()"
+	// JiraAssigneeEmpty as JiraAssigneeFilter syncs unassigned issues.
+	JiraAssigneeEmpty = "EMPTY"
 	// DefaultInterval polling interval.
 	DefaultInterval = 5 * time.Minute
+	// DefaultCompletedLookback window for completed Todoist tasks.
+	DefaultCompletedLookback = 72 * time.Hour
+	// MaxCompletedLookback is the largest completed task window the Todoist API reliably supports.
+	MaxCompletedLookback = 90 * 24 * time.Hour
 	// DefaultLogLevel log level.
 	DefaultLogLevel = "info"
 	// DefaultLogFilePath log file path.
 	DefaultLogFilePath = "./todoist-jira-sync.log.jsonl"
+	// DefaultStateFilePath sync state file path.
+	DefaultStateFilePath = "./todoist-jira-sync.state.json"
+	// DefaultMaxRetry times a failed sync action is retried.
+	DefaultMaxRetry = 3
+	// DefaultSyncConcurrency project pairs synced at once.
+	DefaultSyncConcurrency = 1
+	// DefaultAPIMaxRetries times a Todoist or Jira request is retried after a 503 or 504.
+	DefaultAPIMaxRetries = 3
+	// DefaultShutdownTimeout time to let an in-flight sync cycle finish on shutdown.
+	DefaultShutdownTimeout = 60 * time.Second
+	// DefaultWebhookAddr address the serve command listens on.
+	DefaultWebhookAddr = ":8080"
+	// DefaultRequestTimeout max time for a single Todoist or Jira request.
+	DefaultRequestTimeout = 30 * time.Second
+	// DefaultJiraSearchPageSize issues fetched per Jira search request.
+	DefaultJiraSearchPageSize = 100
+	// DefaultMaxAttachmentBytes largest Jira attachment uploaded to Todoist.
+	DefaultMaxAttachmentBytes int64 = 10 << 20
+	// DefaultFixVersionLabelPrefix prefix of Todoist labels for Jira fix versions.
+	DefaultFixVersionLabelPrefix = "v:"
+	// DefaultCommentFromJiraPrefix prefix for Jira comments synced to Todoist.
+	DefaultCommentFromJiraPrefix = "`[From Jira %s]`\n"
+	// DefaultCommentFromTodoistPrefix prefix for Todoist comments synced to Jira.
+	DefaultCommentFromTodoistPrefix = "[From Todoist] "
 )
 
 var (
@@ -50,61 +185,287 @@ var (
 		"Closed":      "Closed",
 		"Blocked":     "Blocked",
 	}
+	// DefaultJiraSprintStates are the sprint states whose issues get Todoist tasks.
+	DefaultJiraSprintStates = []string{"active"}
 	// DefaultJiraIssueTypes Jira issue types to sync.
 	DefaultJiraIssueTypes = []string{"Story", "Task", "Bug", "Sub-task"}
 )
 
+const (
+	appName     = "todoist-jira-sync"
+	envFileName = ".env"
+)
+
+// SampleYAML is an example YAML config file documenting every option.
+//
+//go:embed sample.yaml
+var SampleYAML []byte
+
+// ErrConfigFileNotFound is returned by Load when an explicitly requested config file does not exist.
+var ErrConfigFileNotFound = errors.New("config file not found")
+
 // LoadOption is a function that can be used to load configuration.
 type LoadOption func(*viper.Viper) error
 
 // WithFlags loads configuration from command line flags.
+// Flag names are bound to their config keys, e.g. --todoist-project sets todoist_project.
 func WithFlags(flags *pflag.FlagSet) LoadOption {
 	return func(v *viper.Viper) error {
-		if err := v.BindPFlags(flags); err != nil {
-			return err
-		}
-		return nil
+		var errs []error
+		flags.VisitAll(func(f *pflag.Flag) {
+			key, ok := flagKeys[f.Name]
+			if !ok {
+				key = strings.ReplaceAll(f.Name, "-", "_")
+			}
+			if err := v.BindPFlag(key, f); err != nil {
+				errs = append(errs, fmt.Errorf("bind flag %s: %w", f.Name, err))
+			}
+		})
+		return errors.Join(errs...)
 	}
 }
 
-// Load loads configuration from environment variables and configuration files.
+// flagKeys maps flags to config keys for flags whose name doesn't match the key.
+var flagKeys = map[string]string{
+	"initial-delay":        "watch_initial_delay",
+	"initial-delay-jitter": "watch_jitter",
+	"max-errors":           "watch_max_errors",
+	"max-cycles":           "watch_max_cycles",
+	"max-backoff":          "watch_max_backoff",
+}
+
+// envAliases lists the environment variables read for keys whose name differs
+// from the upper-cased key. The first variable that is set wins.
+var envAliases = map[string][]string{
+	"interval":      {"SYNC_INTERVAL", "INTERVAL"},
+	"todoist_token": {"TODOIST_TOKEN", "TODOIST_API_TOKEN"},
+	"config":        {"TODOIST_JIRA_SYNC_CONFIG"},
+	"jira_token":    {"JIRA_TOKEN", "JIRA_API_TOKEN"},
+}
+
+// Load loads configuration from all sources. Each key resolves with this precedence:
+//
+//  1. command line flags that were explicitly set (see WithFlags)
+//  2. environment variables, e.g. TODOIST_PROJECT for todoist_project
+//  3. the YAML config file (see ConfigSearchPaths)
+//  4. the .env file in the working directory
+//
+// If the config key is set, by --config or TODOIST_JIRA_SYNC_CONFIG, only that
+// file is read in place of 3 and 4.
+//  5. compile-time defaults, or the flag's default if it has none
 func Load(opts ...LoadOption) (*Config, error) {
 	v := viper.New()
 
+	// Defaults
 	v.SetDefault("todoist_project", DefaultTodoistProject)
 	v.SetDefault("jira_project", DefaultJiraProject)
 	v.SetDefault("jira_issue_types", DefaultJiraIssueTypes)
+	v.SetDefault("default_issue_type", DefaultJiraIssueType)
+	v.SetDefault("todoist_due_date_field", TodoistDueDateFieldDue)
+	v.SetDefault("orphan_action", OrphanActionLabel)
+	v.SetDefault("sync_direction", SyncDirectionBidirectional)
+	v.SetDefault("skip_recurring_tasks", true)
+	v.SetDefault("recurring_task_label", DefaultRecurringTaskLabel)
+	v.SetDefault("jira_auth_type", JiraAuthTypeBasic)
+	v.SetDefault("jira_sprint_states", DefaultJiraSprintStates)
+	v.SetDefault("jira_board", 0)
+	v.SetDefault("jira_assignee_filter", DefaultJiraAssigneeFilter)
+	v.SetDefault("require_active_sprint", false)
+	v.SetDefault("sync_backlog", false)
+	v.SetDefault("use_sprint_end_as_due_date", false)
 	v.SetDefault("interval", DefaultInterval)
+	v.SetDefault("shutdown_timeout", DefaultShutdownTimeout)
+	v.SetDefault("webhook_addr", DefaultWebhookAddr)
+	v.SetDefault("completed_lookback", DefaultCompletedLookback)
 	v.SetDefault("log_level", DefaultLogLevel)
 	v.SetDefault("status_map", DefaultStatusMap)
 	v.SetDefault("log_file_path", DefaultLogFilePath)
+	v.SetDefault("state_file_path", DefaultStateFilePath)
+	v.SetDefault("field_level_sync", false)
+	v.SetDefault("verbose", false)
+	v.SetDefault("max_retry", DefaultMaxRetry)
+	v.SetDefault("sync_concurrency", DefaultSyncConcurrency)
+	v.SetDefault("todoist_max_retries", DefaultAPIMaxRetries)
+	v.SetDefault("jira_max_retries", DefaultAPIMaxRetries)
+	v.SetDefault("todoist_request_timeout", DefaultRequestTimeout)
+	v.SetDefault("jira_request_timeout", DefaultRequestTimeout)
+	v.SetDefault("sync_timeout_per_cycle", 0)
+	v.SetDefault("jira_search_page_size", DefaultJiraSearchPageSize)
+	v.SetDefault("max_sync_items", 0)
+	v.SetDefault("validate_status_map_on_start", false)
+	v.SetDefault("sync_issue_links", false)
+	v.SetDefault("sync_attachments", false)
+	v.SetDefault("sync_attachment_content", false)
+	v.SetDefault("max_attachment_bytes", DefaultMaxAttachmentBytes)
+	v.SetDefault("sync_fix_versions", false)
+	v.SetDefault("fix_version_label_prefix", DefaultFixVersionLabelPrefix)
+	v.SetDefault("sync_duration", false)
+	v.SetDefault("comment_from_jira_prefix", DefaultCommentFromJiraPrefix)
+	v.SetDefault("comment_from_todoist_prefix", DefaultCommentFromTodoistPrefix)
+	v.SetDefault("comment_attribute_users", true)
 
-	v.SetConfigName(".env")
-	v.SetConfigType("env")
-	v.AddConfigPath(".")
-
+	// Environment variables
 	v.AutomaticEnv()
+	for key, envs := range envAliases {
+		if err := v.BindEnv(append([]string{key}, envs...)...); err != nil {
+			return nil, fmt.Errorf("bind env for %s: %w", key, err)
+		}
+	}
 
+	// Flags
 	for _, opt := range opts {
 		if err := opt(v); err != nil {
 			return nil, err
 		}
 	}
 
-	if err := v.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			return nil, err
+	// Config files, read last so an explicit --config path can be resolved from the layers above
+	if err := readConfigFiles(v, v.GetString("config")); err != nil {
+		return nil, err
+	}
+
+	// status_map is a string when set by STATUS_MAP or in the .env file. Viper
+	// can't unmarshal that, and would merge a map with the default one, so it's
+	// parsed separately.
+	var statusMap map[string]string
+	if s, ok := v.Get("status_map").(string); ok {
+		var err error
+		if statusMap, err = ParseStatusMap(s); err != nil {
+			return nil, fmt.Errorf("parse status_map: %w", err)
 		}
-		fmt.Println("no config file found")
+		v.Set("status_map", DefaultStatusMap)
 	}
 
 	cfg := &Config{}
 	if err := v.Unmarshal(cfg); err != nil {
 		return nil, err
 	}
+	if statusMap != nil {
+		cfg.StatusMap = statusMap
+	}
+	cfg.splitStringSlices()
 	return cfg, nil
 }
 
+// ParseStringSlice splits a comma-separated list, trimming spaces around each
+// item and dropping empty ones. An empty string returns nil.
+func ParseStringSlice(s string) []string {
+	var items []string
+	for item := range strings.SplitSeq(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ParseStatusMap parses a status map written as comma-separated JIRA=TODOIST
+// pairs, e.g. "To Do=Backlog,In Progress=Doing". Spaces around names are
+// trimmed, and a Todoist section name may contain "=". An empty string returns
+// an empty map.
+func ParseStatusMap(s string) (map[string]string, error) {
+	statusMap := make(map[string]string)
+	for entry := range strings.SplitSeq(s, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		jiraStatus, section, ok := strings.Cut(entry, "=")
+		jiraStatus, section = strings.TrimSpace(jiraStatus), strings.TrimSpace(section)
+		if !ok || jiraStatus == "" || section == "" {
+			return nil, fmt.Errorf("status map entry %q must be JIRA_STATUS=TODOIST_SECTION", entry)
+		}
+		statusMap[jiraStatus] = section
+	}
+	return statusMap, nil
+}
+
+// splitStringSlices runs every item of the list fields through ParseStringSlice,
+// so comma-separated values like JIRA_ISSUE_TYPES=" Story , Task " are split
+// and trimmed however viper decoded them.
+func (c *Config) splitStringSlices() {
+	for _, list := range []*[]string{
+		&c.JiraIssueTypes,
+		&c.JiraComponents,
+		&c.JiraFixVersions,
+		&c.ExcludeJiraKeys,
+		&c.JiraSprintStates,
+		&c.ExcludeLabels,
+		&c.IncludeLabels,
+	} {
+		if len(*list) == 0 {
+			continue
+		}
+		var items []string
+		for _, item := range *list {
+			items = append(items, ParseStringSlice(item)...)
+		}
+		*list = items
+	}
+}
+
+// readConfigFiles reads the .env file from the working directory, then merges
+// the first YAML config file found in ConfigSearchPaths over it.
+// If explicitPath is set, only that file is read, in the format given by its extension.
+func readConfigFiles(v *viper.Viper, explicitPath string) error {
+	if explicitPath != "" {
+		if _, err := os.Stat(explicitPath); err != nil {
+			return fmt.Errorf("%w: %s", ErrConfigFileNotFound, explicitPath)
+		}
+		v.SetConfigFile(explicitPath)
+		if err := v.ReadInConfig(); err != nil {
+			return fmt.Errorf("read config file %s: %w", explicitPath, err)
+		}
+		return nil
+	}
+
+	found := false
+	if _, err := os.Stat(envFileName); err == nil {
+		v.SetConfigFile(envFileName)
+		if err := v.MergeInConfig(); err != nil {
+			return fmt.Errorf("read %s: %w", envFileName, err)
+		}
+		found = true
+	}
+
+	for _, path := range ConfigSearchPaths() {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		v.SetConfigFile(path)
+		if err := v.MergeInConfig(); err != nil {
+			return fmt.Errorf("read config file %s: %w", path, err)
+		}
+		found = true
+		break
+	}
+
+	if !found {
+		fmt.Println("no config file found")
+	}
+	return nil
+}
+
+// ConfigSearchPaths returns the YAML config file locations, in order of preference.
+func ConfigSearchPaths() []string {
+	paths := []string{"." + appName + ".yaml"}
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, ".config", appName, "config.yaml"))
+	}
+	return paths
+}
+
+// ProjectPair syncs one Todoist project with one Jira project. Zero fields fall
+// back to the top-level config.
+type ProjectPair struct {
+	TodoistProject     string            `mapstructure:"todoist_project"`
+	JiraProject        string            `mapstructure:"jira_project"`
+	StatusMap          map[string]string `mapstructure:"status_map"` // replaces the top-level status_map for this pair
+	JiraIssueTypes     []string          `mapstructure:"jira_issue_types"`
+	JiraAssigneeFilter string            `mapstructure:"jira_assignee_filter"`
+	JiraBoard          int               `mapstructure:"jira_board"`
+	JiraRequiredLabel  string            `mapstructure:"jira_required_label"`
+}
+
 // Validate validates the configuration.
 func (c *Config) Validate() error {
 	if c.TodoistToken == "" {
@@ -120,8 +481,14 @@ func (c *Config) Validate() error {
 	if !strings.HasPrefix(c.JiraURL, "http://") && !strings.HasPrefix(c.JiraURL, "https://") {
 		c.JiraURL = "https://" + c.JiraURL
 	}
-	if c.JiraEmail == "" {
-		return fmt.Errorf("jira_email is required")
+	switch c.JiraAuthType {
+	case "", JiraAuthTypeBasic:
+		if c.JiraEmail == "" {
+			return fmt.Errorf("jira_email is required")
+		}
+	case JiraAuthTypePAT:
+	default:
+		return fmt.Errorf("jira_auth_type must be %q or %q, got %q", JiraAuthTypeBasic, JiraAuthTypePAT, c.JiraAuthType)
 	}
 	if c.JiraToken == "" {
 		return fmt.Errorf("jira_token is required")
@@ -129,41 +496,387 @@ func (c *Config) Validate() error {
 	if c.JiraProject == "" {
 		return fmt.Errorf("jira_project is required")
 	}
+	if c.WatchInitialDelay < 0 || c.WatchJitter < 0 {
+		return fmt.Errorf(
+			"watch_initial_delay and watch_jitter must not be negative, got %s and %s",
+			c.WatchInitialDelay, c.WatchJitter,
+		)
+	}
+	if c.JiraBoard < 0 {
+		return fmt.Errorf("jira_board must not be negative, got %d", c.JiraBoard)
+	}
+	for i, pair := range c.ProjectPairs {
+		if pair.JiraBoard < 0 {
+			return fmt.Errorf("project_pairs[%d].jira_board must not be negative, got %d", i, pair.JiraBoard)
+		}
+	}
+	if c.SyncConcurrency < 0 {
+		return fmt.Errorf("sync_concurrency must not be negative, got %d", c.SyncConcurrency)
+	}
+	if c.SyncTimeoutPerCycle < 0 {
+		return fmt.Errorf("sync_timeout_per_cycle must not be negative, got %s", c.SyncTimeoutPerCycle)
+	}
+	if c.WatchMaxBackoff < 0 {
+		return fmt.Errorf("watch_max_backoff must not be negative, got %s", c.WatchMaxBackoff)
+	}
+	if c.WatchMaxErrors < 0 {
+		return fmt.Errorf("watch_max_errors must not be negative, got %d", c.WatchMaxErrors)
+	}
+	if c.WatchMaxCycles < 0 {
+		return fmt.Errorf("watch_max_cycles must not be negative, got %d", c.WatchMaxCycles)
+	}
+	switch c.TodoistDueDateField {
+	case "", TodoistDueDateFieldDue, TodoistDueDateFieldDeadline:
+	default:
+		return fmt.Errorf(
+			"todoist_due_date_field must be %q or %q, got %q",
+			TodoistDueDateFieldDue, TodoistDueDateFieldDeadline, c.TodoistDueDateField,
+		)
+	}
+	switch c.SyncDirection {
+	case "", SyncDirectionBidirectional, SyncDirectionJiraToTodoist, SyncDirectionTodoistToJira:
+	default:
+		return fmt.Errorf(
+			"sync_direction must be %q, %q, or %q, got %q",
+			SyncDirectionBidirectional, SyncDirectionJiraToTodoist, SyncDirectionTodoistToJira, c.SyncDirection,
+		)
+	}
+	switch c.OrphanAction {
+	case "", OrphanActionDelete, OrphanActionClose, OrphanActionLabel:
+	default:
+		return fmt.Errorf(
+			"orphan_action must be %q, %q, or %q, got %q",
+			OrphanActionDelete, OrphanActionClose, OrphanActionLabel, c.OrphanAction,
+		)
+	}
+	if c.CompletedLookback < 0 || c.CompletedLookback > MaxCompletedLookback {
+		return fmt.Errorf("completed_lookback must be between 0 and %s, got %s", MaxCompletedLookback, c.CompletedLookback)
+	}
+	if ambiguous := c.ambiguousStatuses(); len(ambiguous) > 0 {
+		return fmt.Errorf(
+			"status_map is ambiguous, these Todoist statuses are mapped from multiple Jira statuses "+
+				"and none of them share its name: %s",
+			strings.Join(ambiguous, "; "),
+		)
+	}
+	return nil
+}
+
+// ambiguousStatuses lists Todoist statuses that several Jira statuses map to
+// without one of them having the same name, so there's no obvious Jira status
+// to transition to.
+func (c *Config) ambiguousStatuses() []string {
+	jiraStatuses := make(map[string][]string)
+	for jiraStatus, todoistStatus := range c.StatusMap {
+		jiraStatuses[todoistStatus] = append(jiraStatuses[todoistStatus], jiraStatus)
+	}
+	var ambiguous []string
+	for todoistStatus, candidates := range jiraStatuses {
+		if len(candidates) < 2 || slices.Contains(candidates, todoistStatus) {
+			continue
+		}
+		sort.Strings(candidates)
+		ambiguous = append(ambiguous, fmt.Sprintf("%q <- %s", todoistStatus, strings.Join(candidates, ", ")))
+	}
+	sort.Strings(ambiguous)
+	return ambiguous
+}
+
+// JiraStatusLister lists the workflow statuses available in a Jira project.
+// It is implemented by *jira.Client.
+type JiraStatusLister interface {
+	GetProjectStatuses(ctx context.Context, projectKey string) ([]string, error)
+}
+
+// ValidateWithJira checks that every Jira status that Todoist sections map to
+// exists in the Jira project, so transitions don't silently fail.
+func (c *Config) ValidateWithJira(ctx context.Context, client JiraStatusLister) error {
+	statuses, err := client.GetProjectStatuses(ctx, c.JiraProject)
+	if err != nil {
+		return fmt.Errorf("get jira statuses for project %s: %w", c.JiraProject, err)
+	}
+
+	var missing []string
+	for _, section := range c.StatusMap {
+		target := c.TodoistToJiraStatus(section)
+		found := slices.ContainsFunc(statuses, func(s string) bool {
+			return strings.EqualFold(s, target)
+		})
+		if !found && !slices.Contains(missing, target) {
+			missing = append(missing, target)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf(
+			"status_map targets Jira statuses not in project %s: %s (available: %s)",
+			c.JiraProject, strings.Join(missing, ", "), strings.Join(statuses, ", "),
+		)
+	}
+	return nil
+}
+
+// JiraComponentLister lists the components of a Jira project.
+// It is implemented by *jira.Client.
+type JiraComponentLister interface {
+	GetComponentNames(ctx context.Context, projectKey string) ([]string, error)
+}
+
+// ValidateComponents checks that every JiraComponents entry exists in the Jira
+// project, since a misspelled component silently filters out every issue.
+func (c *Config) ValidateComponents(ctx context.Context, client JiraComponentLister) error {
+	if len(c.JiraComponents) == 0 {
+		return nil
+	}
+	components, err := client.GetComponentNames(ctx, c.JiraProject)
+	if err != nil {
+		return fmt.Errorf("get jira components for project %s: %w", c.JiraProject, err)
+	}
+
+	var unknown []string
+	for _, component := range c.JiraComponents {
+		found := slices.ContainsFunc(components, func(name string) bool {
+			return strings.EqualFold(name, component)
+		})
+		if !found {
+			unknown = append(unknown, component)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf(
+			"jira_components not in project %s: %s (available: %s)",
+			c.JiraProject, strings.Join(unknown, ", "), strings.Join(components, ", "),
+		)
+	}
 	return nil
 }
 
 // JiraToTodoistStatus returns the Todoist status/section name for a Jira status.
+// Jira statuses are matched case-insensitively, since config files may lowercase map keys.
 func (c *Config) JiraToTodoistStatus(sectionName string) string {
 	if status, ok := c.StatusMap[sectionName]; ok {
 		return status
 	}
+	for jiraStatus, status := range c.StatusMap {
+		if strings.EqualFold(jiraStatus, sectionName) {
+			return status
+		}
+	}
 	return sectionName
 }
 
+// SyncsToJira reports whether SyncDirection allows writing to Jira.
+func (c *Config) SyncsToJira() bool {
+	return c.SyncDirection != SyncDirectionJiraToTodoist
+}
+
+// SyncsToTodoist reports whether SyncDirection allows writing to Todoist.
+func (c *Config) SyncsToTodoist() bool {
+	return c.SyncDirection != SyncDirectionTodoistToJira
+}
+
+// SyncTodoistDeadline reports whether the Todoist deadline, rather than the
+// due date, is synced with the Jira due date.
+func (c *Config) SyncTodoistDeadline() bool {
+	return c.TodoistDueDateField == TodoistDueDateFieldDeadline
+}
+
+// SprintStates returns the sprint states whose issues get Todoist tasks,
+// honoring RequireActiveSprint. Empty JiraSprintStates means DefaultJiraSprintStates.
+func (c *Config) SprintStates() []string {
+	if c.RequireActiveSprint || len(c.JiraSprintStates) == 0 {
+		return DefaultJiraSprintStates
+	}
+	return c.JiraSprintStates
+}
+
+// IssueTypeForSection returns the Jira issue type for issues created from tasks in a Todoist section.
+// Sections are matched case-insensitively, since config files may lowercase map keys.
+func (c *Config) IssueTypeForSection(sectionName string) string {
+	if issueType, ok := c.SectionIssueTypeMap[sectionName]; ok {
+		return issueType
+	}
+	for section, issueType := range c.SectionIssueTypeMap {
+		if strings.EqualFold(section, sectionName) {
+			return issueType
+		}
+	}
+	return c.DefaultIssueType
+}
+
+// ExcludesJiraKey reports whether the Jira issue key is in ExcludeJiraKeys.
+func (c *Config) ExcludesJiraKey(key string) bool {
+	return key != "" && slices.ContainsFunc(c.ExcludeJiraKeys, func(excluded string) bool {
+		return strings.EqualFold(excluded, key)
+	})
+}
+
+// ExcludesTask reports whether a Todoist task with these labels has one of ExcludeLabels.
+// Labels are matched case-insensitively.
+func (c *Config) ExcludesTask(labels []string) bool {
+	return hasAnyLabel(labels, c.ExcludeLabels)
+}
+
+// IncludesTask reports whether a Todoist task with these labels may be synced to a new Jira issue:
+// it must have one of IncludeLabels, if any are set, and none of ExcludeLabels, which take precedence.
+func (c *Config) IncludesTask(labels []string) bool {
+	if c.ExcludesTask(labels) {
+		return false
+	}
+	return len(c.IncludeLabels) == 0 || hasAnyLabel(labels, c.IncludeLabels)
+}
+
+// SkipsRecurringTask reports whether a recurring Todoist task with these labels is kept from
+// getting a new Jira issue: with SkipRecurringTasks, unless it has RecurringTaskLabel.
+func (c *Config) SkipsRecurringTask(labels []string) bool {
+	return c.SkipRecurringTasks && (c.RecurringTaskLabel == "" || !hasAnyLabel(labels, []string{c.RecurringTaskLabel}))
+}
+
+func hasAnyLabel(labels, want []string) bool {
+	return slices.ContainsFunc(labels, func(label string) bool {
+		return slices.ContainsFunc(want, func(w string) bool {
+			return strings.EqualFold(label, w)
+		})
+	})
+}
+
 // TodoistToJiraStatus returns the Jira status name for a Todoist status.
+// When several Jira statuses map to the same Todoist status, the one with the
+// same name wins, then the alphabetically first.
+// The reverse lookup is built once, so StatusMap must not change after the first call.
 func (c *Config) TodoistToJiraStatus(todoistStatus string) string {
-	for section, status := range c.StatusMap {
-		if status == todoistStatus {
-			return section
+	c.reverseStatusOnce.Do(func() {
+		c.reverseStatusMap = make(map[string]string, len(c.StatusMap))
+		for jiraStatus, section := range c.StatusMap {
+			existing, ok := c.reverseStatusMap[section]
+			switch {
+			case !ok:
+				c.reverseStatusMap[section] = jiraStatus
+			case existing == section:
+			case jiraStatus == section || jiraStatus < existing:
+				c.reverseStatusMap[section] = jiraStatus
+			}
 		}
+	})
+	if status, ok := c.reverseStatusMap[todoistStatus]; ok {
+		return status
 	}
 	return todoistStatus
 }
 
+// FormatCommentFromJira returns the prefix for a Jira comment synced to Todoist.
+// The author's name is substituted for the first %s, if the prefix contains it.
+// The prefix isn't a format string, so other % verbs are left as is.
+func (c *Config) FormatCommentFromJira(author string) string {
+	return strings.Replace(c.CommentFromJiraPrefix, "%s", author, 1)
+}
+
+// FormatCommentFromTodoist returns the prefix for a Todoist comment synced to
+// Jira. With CommentAttributeUsers, the author's name is substituted for %s or
+// added before the first "]", e.g. "[From Todoist: Alice] ".
+func (c *Config) FormatCommentFromTodoist(author string) string {
+	prefix := c.CommentFromTodoistPrefix
+	if !c.CommentAttributeUsers || author == "" {
+		return strings.Replace(prefix, "%s", "", 1)
+	}
+	if strings.Contains(prefix, "%s") {
+		return strings.Replace(prefix, "%s", author, 1)
+	}
+	before, after, ok := strings.Cut(prefix, "]")
+	if !ok {
+		return prefix
+	}
+	return before + ": " + author + "]" + after
+}
+
+// AssigneeIsCurrentUser reports whether JiraAssigneeFilter selects the Jira user whose token is used.
+func (c *Config) AssigneeIsCurrentUser() bool {
+	filter := strings.TrimSpace(c.JiraAssigneeFilter)
+	return filter == "" || strings.EqualFold(filter, DefaultJiraAssigneeFilter)
+}
+
+// Merge returns a new Config with the non-zero fields of override replacing
+// those of c. Non-nil slices in override replace c's, even empty ones, and the
+// entries of maps in override are added to c's. Neither config is modified.
+func (c *Config) Merge(override *Config) *Config {
+	merged := &Config{}
+	dst := reflect.ValueOf(merged).Elem()
+	for _, src := range []*Config{c, override} {
+		if src == nil {
+			continue
+		}
+		v := reflect.ValueOf(src).Elem()
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				mergeField(dst.Field(i), v.Field(i))
+			}
+		}
+	}
+	return merged
+}
+
+// mergeField merges src into dst following the rules of Merge. Slices and maps
+// are copied so the merged config shares no memory with its sources.
+func mergeField(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Slice:
+		if !src.IsNil() {
+			dst.Set(reflect.AppendSlice(reflect.MakeSlice(src.Type(), 0, src.Len()), src))
+		}
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(src.Type(), src.Len()))
+		}
+		for iter := src.MapRange(); iter.Next(); {
+			dst.SetMapIndex(iter.Key(), iter.Value())
+		}
+	default:
+		if !src.IsZero() {
+			dst.Set(src)
+		}
+	}
+}
+
 // JiraIssueTypesJQL returns a JQL fragment for filtering by configured issue types.
 // e.g. `issuetype IN (Story, Task, Bug)`. Returns empty string if no types are configured.
+//
+// Deprecated: Use jira.JQLBuilder.IssueTypes.
 func (c *Config) JiraIssueTypesJQL() string {
-	if len(c.JiraIssueTypes) == 0 {
+	return inJQL("issuetype", c.JiraIssueTypes)
+}
+
+// JiraComponentsJQL returns a JQL fragment for filtering by configured components.
+// e.g. `component IN (Backend, "Developer Experience")`. Returns empty string if no components are configured.
+//
+// Deprecated: Use jira.JQLBuilder.Components.
+func (c *Config) JiraComponentsJQL() string {
+	return inJQL("component", c.JiraComponents)
+}
+
+// JiraFixVersionsJQL returns a JQL fragment for filtering by configured fix versions.
+// e.g. `fixVersion IN (v2.1.0, "Release 3")`. Returns empty string if no versions are configured.
+//
+// Deprecated: Use jira.JQLBuilder.FixVersions.
+func (c *Config) JiraFixVersionsJQL() string {
+	return inJQL("fixVersion", c.JiraFixVersions)
+}
+
+// inJQL builds a `field IN (...)` JQL fragment, quoting values that contain spaces or commas.
+func inJQL(field string, values []string) string {
+	if len(values) == 0 {
 		return ""
 	}
-	quoted := make([]string, len(c.JiraIssueTypes))
-	for i, t := range c.JiraIssueTypes {
-		t = strings.TrimSpace(t)
-		if strings.ContainsRune(t, ' ') || strings.ContainsRune(t, ',') {
-			quoted[i] = `"` + strings.ReplaceAll(t, `"`, `\"`) + `"`
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		v = strings.TrimSpace(v)
+		if strings.ContainsRune(v, ' ') || strings.ContainsRune(v, ',') {
+			quoted[i] = `"` + strings.ReplaceAll(v, `"`, `\"`) + `"`
 		} else {
-			quoted[i] = t
+			quoted[i] = v
 		}
 	}
-	return "issuetype IN (" + strings.Join(quoted, ", ") + ")"
+	return field + " IN (" + strings.Join(quoted, ", ") + ")"
 }
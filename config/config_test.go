@@ -0,0 +1,655 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJQLFragments(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  *Config
+		want []string // issue types, components, fix versions
+	}{
+		{
+			name: "empty",
+			cfg:  &Config{},
+			want: []string{"", "", ""},
+		},
+		{
+			name: "simple values",
+			cfg: &Config{
+				JiraIssueTypes:  []string{"Story", "Bug"},
+				JiraComponents:  []string{"Backend"},
+				JiraFixVersions: []string{"v2.1.0", "v2.2.0"},
+			},
+			want: []string{
+				"issuetype IN (Story, Bug)",
+				"component IN (Backend)",
+				"fixVersion IN (v2.1.0, v2.2.0)",
+			},
+		},
+		{
+			name: "values with spaces, commas, and quotes",
+			cfg: &Config{
+				JiraIssueTypes:  []string{" Sub-task "},
+				JiraComponents:  []string{"Developer Experience", "A,B"},
+				JiraFixVersions: []string{`Release "3"`},
+			},
+			want: []string{
+				"issuetype IN (Sub-task)",
+				`component IN ("Developer Experience", "A,B")`,
+				`fixVersion IN ("Release \"3\"")`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want[0], tt.cfg.JiraIssueTypesJQL())
+			assert.Equal(t, tt.want[1], tt.cfg.JiraComponentsJQL())
+			assert.Equal(t, tt.want[2], tt.cfg.JiraFixVersionsJQL())
+		})
+	}
+}
+
+func TestAssigneeIsCurrentUser(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, (&Config{}).AssigneeIsCurrentUser())
+	assert.True(t, (&Config{JiraAssigneeFilter: "currentuser()"}).AssigneeIsCurrentUser())
+	assert.False(t, (&Config{JiraAssigneeFilter: JiraAssigneeEmpty}).AssigneeIsCurrentUser())
+	assert.False(t, (&Config{JiraAssigneeFilter: "automation@example.com"}).AssigneeIsCurrentUser())
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	base := func() *Config {
+		return &Config{
+			TodoistProject: "Work",
+			JiraProject:    "PROJ",
+			JiraBoard:      3,
+			Interval:       5 * time.Minute,
+			SyncBacklog:    true,
+			JiraIssueTypes: []string{"Story"},
+			ExcludeLabels:  []string{"personal"},
+			StatusMap:      map[string]string{"To Do": "Backlog", "Done": "Closed"},
+		}
+	}
+	tests := []struct {
+		name     string
+		override *Config
+		want     func(*Config)
+	}{
+		{
+			name: "nil override",
+		},
+		{
+			name:     "zero override keeps base",
+			override: &Config{},
+		},
+		{
+			name: "scalars replace",
+			override: &Config{
+				JiraProject:  "OPS",
+				JiraBoard:    7,
+				Interval:     time.Minute,
+				SyncDuration: true,
+			},
+			want: func(c *Config) {
+				c.JiraProject = "OPS"
+				c.JiraBoard = 7
+				c.Interval = time.Minute
+				c.SyncDuration = true
+			},
+		},
+		{
+			name:     "slices replace",
+			override: &Config{JiraIssueTypes: []string{"Bug", "Task"}},
+			want: func(c *Config) {
+				c.JiraIssueTypes = []string{"Bug", "Task"}
+			},
+		},
+		{
+			name:     "empty slice replaces",
+			override: &Config{ExcludeLabels: []string{}},
+			want: func(c *Config) {
+				c.ExcludeLabels = []string{}
+			},
+		},
+		{
+			name:     "maps merge",
+			override: &Config{StatusMap: map[string]string{"Done": "Finished", "Blocked": "Waiting"}},
+			want: func(c *Config) {
+				c.StatusMap = map[string]string{"To Do": "Backlog", "Done": "Finished", "Blocked": "Waiting"}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			want := base()
+			if tt.want != nil {
+				tt.want(want)
+			}
+			cfg := base()
+			assert.Equal(t, want, cfg.Merge(tt.override))
+			assert.Equal(t, base(), cfg, "base should be unchanged")
+		})
+	}
+}
+
+func TestMergeCopies(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{
+		JiraIssueTypes: []string{"Story"},
+		StatusMap:      map[string]string{"Done": "Closed"},
+	}
+	assert.Equal(t, "Done", cfg.TodoistToJiraStatus("Closed"))
+	override := &Config{ExcludeLabels: []string{"personal"}, StatusMap: map[string]string{"In Progress": "Doing"}}
+
+	merged := cfg.Merge(override)
+	merged.JiraIssueTypes[0] = "Bug"
+	merged.ExcludeLabels[0] = "work"
+	merged.StatusMap["Done"] = "Finished"
+
+	assert.Equal(t, []string{"Story"}, cfg.JiraIssueTypes)
+	assert.Equal(t, []string{"personal"}, override.ExcludeLabels)
+	assert.Equal(t, map[string]string{"Done": "Closed"}, cfg.StatusMap)
+	assert.Equal(t, map[string]string{"In Progress": "Doing"}, override.StatusMap)
+	assert.Equal(t, "In Progress", merged.TodoistToJiraStatus("Doing"), "merged config should build its own status lookup")
+}
+
+func validConfig() *Config {
+	return &Config{
+		TodoistToken:   "todoist-token",
+		TodoistProject: "Work",
+		JiraURL:        "example.atlassian.net",
+		JiraEmail:      "me@example.com",
+		JiraToken:      "jira-token",
+		JiraProject:    "PROJ",
+	}
+}
+
+func TestValidateCompletedLookback(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		lookback time.Duration
+		wantErr  bool
+	}{
+		{name: "zero disables completion sync", lookback: 0},
+		{name: "default", lookback: DefaultCompletedLookback},
+		{name: "max", lookback: MaxCompletedLookback},
+		{name: "too long", lookback: MaxCompletedLookback + time.Hour, wantErr: true},
+		{name: "negative", lookback: -time.Hour, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := validConfig()
+			cfg.CompletedLookback = tt.lookback
+			err := cfg.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateTodoistDueDateField(t *testing.T) {
+	t.Parallel()
+
+	for _, field := range []string{"", TodoistDueDateFieldDue, TodoistDueDateFieldDeadline} {
+		cfg := validConfig()
+		cfg.TodoistDueDateField = field
+		assert.NoError(t, cfg.Validate(), field)
+	}
+
+	cfg := validConfig()
+	cfg.TodoistDueDateField = "reminder"
+	assert.ErrorContains(t, cfg.Validate(), "todoist_due_date_field")
+}
+
+func TestValidateSyncDirection(t *testing.T) {
+	t.Parallel()
+
+	for _, direction := range []string{
+		"", SyncDirectionBidirectional, SyncDirectionJiraToTodoist, SyncDirectionTodoistToJira,
+	} {
+		cfg := validConfig()
+		cfg.SyncDirection = direction
+		assert.NoError(t, cfg.Validate(), direction)
+	}
+
+	cfg := validConfig()
+	cfg.SyncDirection = "sideways"
+	assert.ErrorContains(t, cfg.Validate(), "sync_direction")
+}
+
+func TestFormatCommentFromTodoist(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		prefix     string
+		attribute  bool
+		author     string
+		wantPrefix string
+	}{
+		{prefix: DefaultCommentFromTodoistPrefix, attribute: true, author: "Alice", wantPrefix: "[From Todoist: Alice] "},
+		{prefix: DefaultCommentFromTodoistPrefix, attribute: true, wantPrefix: "[From Todoist] "},
+		{prefix: DefaultCommentFromTodoistPrefix, author: "Alice", wantPrefix: "[From Todoist] "},
+		{prefix: "(%s via Todoist) ", attribute: true, author: "Alice", wantPrefix: "(Alice via Todoist) "},
+		{prefix: "Todoist: ", attribute: true, author: "Alice", wantPrefix: "Todoist: "},
+		{prefix: "100% %s [%d] ", attribute: true, author: "Alice", wantPrefix: "100% Alice [%d] "},
+	}
+	for _, tt := range tests {
+		cfg := &Config{CommentFromTodoistPrefix: tt.prefix, CommentAttributeUsers: tt.attribute}
+		assert.Equal(t, tt.wantPrefix, cfg.FormatCommentFromTodoist(tt.author), tt.prefix)
+	}
+}
+
+func TestFormatCommentFromJira(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		prefix     string
+		wantPrefix string
+	}{
+		{prefix: DefaultCommentFromJiraPrefix, wantPrefix: "`[From Jira Alice]`\n"},
+		{prefix: "From Jira: ", wantPrefix: "From Jira: "},
+		{prefix: "%s (100%) ", wantPrefix: "Alice (100%) "},
+		{prefix: "%s and %s ", wantPrefix: "Alice and %s "},
+		{prefix: "[%d] ", wantPrefix: "[%d] "},
+	}
+	for _, tt := range tests {
+		cfg := &Config{CommentFromJiraPrefix: tt.prefix}
+		assert.Equal(t, tt.wantPrefix, cfg.FormatCommentFromJira("Alice"), tt.prefix)
+	}
+}
+
+func TestValidateJiraAuthType(t *testing.T) {
+	t.Parallel()
+
+	cfg := validConfig()
+	cfg.JiraEmail = ""
+	assert.ErrorContains(t, cfg.Validate(), "jira_email", "basic auth needs an email")
+
+	cfg = validConfig()
+	cfg.JiraAuthType = JiraAuthTypePAT
+	cfg.JiraEmail = ""
+	assert.NoError(t, cfg.Validate())
+
+	cfg = validConfig()
+	cfg.JiraAuthType = "oauth"
+	assert.ErrorContains(t, cfg.Validate(), "jira_auth_type")
+}
+
+func TestTodoistToJiraStatus(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{StatusMap: DefaultStatusMap}
+	assert.Equal(t, "To Do", cfg.TodoistToJiraStatus("To Do"))
+	assert.Equal(t, "Closed", cfg.TodoistToJiraStatus("Closed"))
+	assert.Equal(t, "In Progress", cfg.TodoistToJiraStatus("In Progress"))
+	assert.Equal(t, "Unmapped", cfg.TodoistToJiraStatus("Unmapped"))
+
+	cfg = &Config{StatusMap: map[string]string{"Resolved": "Done", "Closed": "Done"}}
+	assert.Equal(t, "Closed", cfg.TodoistToJiraStatus("Done"))
+}
+
+func TestIssueTypeForSection(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{
+		DefaultIssueType:    DefaultJiraIssueType,
+		SectionIssueTypeMap: map[string]string{"bugs": "Bug", "Chores": "Task"},
+	}
+	assert.Equal(t, "Bug", cfg.IssueTypeForSection("Bugs"))
+	assert.Equal(t, "Task", cfg.IssueTypeForSection("Chores"))
+	assert.Equal(t, "Story", cfg.IssueTypeForSection("In Progress"))
+	assert.Equal(t, "Story", cfg.IssueTypeForSection(""))
+}
+
+func TestTaskLabelFilters(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{}
+	assert.False(t, cfg.ExcludesTask([]string{"jira-sync"}))
+	assert.True(t, cfg.IncludesTask([]string{"jira-sync"}))
+
+	cfg = &Config{ExcludeLabels: []string{"personal"}, IncludeLabels: []string{"Work"}}
+	assert.True(t, cfg.IncludesTask([]string{"jira-sync", "work"}))
+	assert.False(t, cfg.IncludesTask([]string{"jira-sync"}), "needs an include label")
+	assert.True(t, cfg.ExcludesTask([]string{"jira-sync", "Personal"}))
+	assert.False(t, cfg.IncludesTask([]string{"jira-sync", "work", "personal"}), "exclude wins")
+
+	cfg = &Config{SkipRecurringTasks: true, RecurringTaskLabel: DefaultRecurringTaskLabel}
+	assert.True(t, cfg.SkipsRecurringTask([]string{"jira-sync"}))
+	assert.False(t, cfg.SkipsRecurringTask([]string{"jira-sync", "Jira-Sync-Recurring"}))
+	cfg.SkipRecurringTasks = false
+	assert.False(t, cfg.SkipsRecurringTask([]string{"jira-sync"}))
+}
+
+func TestExcludesJiraKey(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{ExcludeJiraKeys: []string{"PROJ-1"}}
+	assert.True(t, cfg.ExcludesJiraKey("PROJ-1"))
+	assert.True(t, cfg.ExcludesJiraKey("proj-1"))
+	assert.False(t, cfg.ExcludesJiraKey("PROJ-10"))
+	assert.False(t, cfg.ExcludesJiraKey(""))
+}
+
+func TestValidateStatusMap(t *testing.T) {
+	t.Parallel()
+
+	cfg := validConfig()
+	cfg.StatusMap = DefaultStatusMap
+	assert.NoError(t, cfg.Validate())
+
+	cfg = validConfig()
+	cfg.StatusMap = map[string]string{"Resolved": "Done", "Closed": "Done", "Open": "To Do"}
+	err := cfg.Validate()
+	assert.ErrorContains(t, err, `"Done" <- Closed, Resolved`)
+}
+
+type fakeStatusLister []string
+
+func (f fakeStatusLister) GetProjectStatuses(context.Context, string) ([]string, error) {
+	return f, nil
+}
+
+func TestValidateWithJira(t *testing.T) {
+	t.Parallel()
+
+	cfg := validConfig()
+	cfg.StatusMap = map[string]string{"To Do": "To Do", "In Progress": "Doing", "Done": "Closed"}
+
+	err := cfg.ValidateWithJira(context.Background(), fakeStatusLister{"To Do", "In Progress", "Done"})
+	assert.NoError(t, err)
+
+	err = cfg.ValidateWithJira(context.Background(), fakeStatusLister{"to do", "Done"})
+	assert.ErrorContains(t, err, "In Progress")
+}
+
+type fakeComponentLister []string
+
+func (f fakeComponentLister) GetComponentNames(context.Context, string) ([]string, error) {
+	return f, nil
+}
+
+func TestValidateComponents(t *testing.T) {
+	t.Parallel()
+
+	cfg := validConfig()
+	assert.NoError(t, cfg.ValidateComponents(context.Background(), fakeComponentLister{}))
+
+	cfg.JiraComponents = []string{"backend", "Frontend"}
+	err := cfg.ValidateComponents(context.Background(), fakeComponentLister{"Backend", "Frontend"})
+	assert.NoError(t, err)
+
+	err = cfg.ValidateComponents(context.Background(), fakeComponentLister{"Backend", "Docs"})
+	assert.ErrorContains(t, err, "jira_components not in project PROJ: Frontend")
+}
+
+func TestLoadYAML(t *testing.T) { //nolint:paralleltest // changes working directory
+	fixture, err := os.ReadFile(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".todoist-jira-sync.yaml"), fixture, 0600))
+	t.Chdir(dir)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, "todoist-token", cfg.TodoistToken)
+	assert.Equal(t, "Personal", cfg.TodoistProject)
+	assert.Equal(t, "https://example.atlassian.net", cfg.JiraURL)
+	assert.Equal(t, "me@example.com", cfg.JiraEmail)
+	assert.Equal(t, "jira-token", cfg.JiraToken)
+	assert.Equal(t, "PROJ", cfg.JiraProject)
+	assert.Equal(t, []string{"Story", "Bug"}, cfg.JiraIssueTypes)
+	assert.Equal(t, []string{"Backend", "Developer Experience"}, cfg.JiraComponents)
+	assert.Equal(t, []string{"v2.1.0"}, cfg.JiraFixVersions)
+	assert.Equal(t, 50, cfg.JiraSearchPageSize)
+	assert.Len(t, cfg.StatusMap, 3)
+	assert.Equal(t, "Doing", cfg.JiraToTodoistStatus("In Progress"))
+	assert.Equal(t, "Backlog", cfg.JiraToTodoistStatus("To Do"))
+	assert.Equal(t, 10*time.Minute, cfg.Interval)
+	assert.Equal(t, 168*time.Hour, cfg.CompletedLookback)
+	assert.Equal(t, 25, cfg.MaxSyncItems)
+	assert.Equal(t, 5, cfg.MaxRetry)
+	assert.True(t, cfg.FieldLevelSync)
+	assert.Equal(t, "[Jira %s] ", cfg.CommentFromJiraPrefix)
+	assert.Equal(t, "[Todoist] ", cfg.CommentFromTodoistPrefix)
+	assert.Equal(t, "debug", cfg.LogLevel)
+	assert.Equal(t, "/tmp/sync.log.jsonl", cfg.LogFilePath)
+	assert.Equal(t, "/tmp/sync.state.json", cfg.StateFilePath)
+	require.Len(t, cfg.ProjectPairs, 1)
+	pair := cfg.ProjectPairs[0]
+	assert.Equal(t, "Ops", pair.TodoistProject)
+	assert.Equal(t, "OPS", pair.JiraProject)
+	assert.Equal(t, 12, pair.JiraBoard)
+	assert.Equal(t, "oncall", pair.JiraRequiredLabel)
+	assert.Len(t, pair.StatusMap, 1)
+	assert.Empty(t, pair.JiraIssueTypes, "unset pair fields should stay empty")
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestLoadPrecedence(t *testing.T) { //nolint:paralleltest // changes working directory and environment
+	tests := []struct {
+		name    string
+		dotEnv  bool
+		yaml    bool
+		env     bool
+		flag    bool
+		want    string
+		wantMax int
+	}{
+		{name: "defaults", want: DefaultTodoistProject, wantMax: DefaultMaxRetry},
+		{name: ".env over defaults", dotEnv: true, want: "from-dotenv", wantMax: 1},
+		{name: "yaml over .env", dotEnv: true, yaml: true, want: "from-yaml", wantMax: 2},
+		{name: "env over yaml", dotEnv: true, yaml: true, env: true, want: "from-env", wantMax: 3},
+		{name: "flag over env", dotEnv: true, yaml: true, env: true, flag: true, want: "from-flag", wantMax: 4},
+		{name: "unset flag doesn't override env", env: true, want: "from-env", wantMax: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Chdir(dir)
+			t.Setenv("HOME", dir)
+			t.Setenv("TODOIST_PROJECT", "")
+			t.Setenv("MAX_RETRY", "")
+
+			if tt.dotEnv {
+				require.NoError(t, os.WriteFile(
+					filepath.Join(dir, ".env"), []byte("TODOIST_PROJECT=from-dotenv\nMAX_RETRY=1\n"), 0600,
+				))
+			}
+			if tt.yaml {
+				require.NoError(t, os.WriteFile(
+					filepath.Join(dir, ".todoist-jira-sync.yaml"), []byte("todoist_project: from-yaml\nmax_retry: 2\n"), 0600,
+				))
+			}
+			if tt.env {
+				t.Setenv("TODOIST_PROJECT", "from-env")
+				t.Setenv("MAX_RETRY", "3")
+			}
+
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			flags.String("todoist-project", DefaultTodoistProject, "")
+			flags.Int("max-retry", DefaultMaxRetry, "")
+			if tt.flag {
+				require.NoError(t, flags.Parse([]string{"--todoist-project=from-flag", "--max-retry=4"}))
+			}
+
+			cfg, err := Load(WithFlags(flags))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.TodoistProject)
+			assert.Equal(t, tt.wantMax, cfg.MaxRetry)
+		})
+	}
+}
+
+func TestLoadEnvAliases(t *testing.T) { //nolint:paralleltest // changes working directory and environment
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("SYNC_INTERVAL", "2m")
+	t.Setenv("JIRA_API_TOKEN", "jira-token")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, cfg.Interval)
+	assert.Equal(t, "jira-token", cfg.JiraToken)
+}
+
+func TestParseStringSlice(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{in: "Story,Task,Bug", want: []string{"Story", "Task", "Bug"}},
+		{in: " Story , Task ", want: []string{"Story", "Task"}},
+		{in: "Story,,Task,", want: []string{"Story", "Task"}},
+		{in: "Story", want: []string{"Story"}},
+		{in: "", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, ParseStringSlice(tt.in))
+		})
+	}
+}
+
+func TestLoadStringSlicesFromEnv(t *testing.T) { //nolint:paralleltest // changes working directory and environment
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("JIRA_ISSUE_TYPES", " Story , Task ")
+	t.Setenv("JIRA_SPRINT_STATES", "active,future")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Story", "Task"}, cfg.JiraIssueTypes)
+	assert.Equal(t, []string{"active", "future"}, cfg.JiraSprintStates)
+}
+
+func TestParseStatusMap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		in      string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "multiple entries",
+			in:   "Todo=To Do,InProgress=In Progress",
+			want: map[string]string{"Todo": "To Do", "InProgress": "In Progress"},
+		},
+		{
+			name: "spaces",
+			in:   " To Do = Backlog , In Review=Waiting on review ,",
+			want: map[string]string{"To Do": "Backlog", "In Review": "Waiting on review"},
+		},
+		{
+			name: "equals sign in section",
+			in:   "Done=A=B",
+			want: map[string]string{"Done": "A=B"},
+		},
+		{
+			name: "empty",
+			in:   "",
+			want: map[string]string{},
+		},
+		{name: "missing equals sign", in: "Done", wantErr: true},
+		{name: "missing status", in: "=Closed", wantErr: true},
+		{name: "missing section", in: "Done= ", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseStatusMap(tt.in)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoadStatusMapFromEnv(t *testing.T) { //nolint:paralleltest // changes working directory and environment
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("STATUS_MAP", "To Do=Backlog,Done=Closed")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"To Do": "Backlog", "Done": "Closed"}, cfg.StatusMap)
+
+	t.Setenv("STATUS_MAP", "Done")
+	_, err = Load()
+	require.Error(t, err)
+}
+
+func TestLoadExplicitConfigFile(t *testing.T) { //nolint:paralleltest // changes environment
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	path := filepath.Join(dir, "custom.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("todoist_project: from-explicit\n"), 0600))
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("config", "", "")
+	require.NoError(t, flags.Parse([]string{"--config=" + path}))
+	cfg, err := Load(WithFlags(flags))
+	require.NoError(t, err)
+	assert.Equal(t, "from-explicit", cfg.TodoistProject)
+
+	t.Setenv("TODOIST_JIRA_SYNC_CONFIG", filepath.Join(dir, "missing.yaml"))
+	_, err = Load()
+	require.ErrorIs(t, err, ErrConfigFileNotFound)
+	assert.ErrorContains(t, err, "missing.yaml")
+}
+
+func TestLoadWatchDelayFlags(t *testing.T) { //nolint:paralleltest // changes working directory and environment
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("HOME", dir)
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.Duration("initial-delay", 0, "")
+	flags.Duration("initial-delay-jitter", 0, "")
+	require.NoError(t, flags.Parse([]string{"--initial-delay=30s", "--initial-delay-jitter=10s"}))
+
+	cfg, err := Load(WithFlags(flags))
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, cfg.WatchInitialDelay)
+	assert.Equal(t, 10*time.Second, cfg.WatchJitter)
+}
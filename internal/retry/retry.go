@@ -0,0 +1,54 @@
+// Package retry configures the retries shared by the Todoist and Jira clients.
+package retry
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+	"resty.dev/v3"
+)
+
+const (
+	initialWait = 500 * time.Millisecond
+	maxWait     = 30 * time.Second
+)
+
+// Configure makes r retry requests that got a 503 or 504 response up to
+// maxRetries times, with exponential backoff. Each retry is logged as
+// "retrying <api> request".
+func Configure(r *resty.Client, maxRetries int, logger zerolog.Logger, api string) {
+	r.SetRetryCount(max(maxRetries, 0)).
+		SetRetryWaitTime(initialWait).
+		SetRetryMaxWaitTime(maxWait).
+		SetRetryDefaultConditions(false).
+		AddRetryConditions(retryable).
+		SetRetryStrategy(func(resp *resty.Response, _ error) (time.Duration, error) {
+			wait := Wait(resp.Request.Attempt)
+			logger.Warn().
+				Str("method", resp.Request.Method).
+				Str("url", resp.Request.URL).
+				Int("status", resp.StatusCode()).
+				Int("attempt", resp.Request.Attempt).
+				Str("wait", wait.String()).
+				Msg("retrying " + api + " request")
+			return wait, nil
+		})
+}
+
+// retryable reports whether a response is a temporary server error worth retrying.
+func retryable(resp *resty.Response, _ error) bool {
+	if resp == nil {
+		return false
+	}
+	return resp.StatusCode() == http.StatusServiceUnavailable || resp.StatusCode() == http.StatusGatewayTimeout
+}
+
+// Wait returns the exponential backoff before retrying after the given attempt.
+func Wait(attempt int) time.Duration {
+	wait := initialWait
+	for i := 1; i < attempt && wait < maxWait; i++ {
+		wait *= 2
+	}
+	return min(wait, maxWait)
+}
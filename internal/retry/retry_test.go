@@ -0,0 +1,18 @@
+package retry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWait(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 500*time.Millisecond, Wait(1))
+	assert.Equal(t, time.Second, Wait(2))
+	assert.Equal(t, 16*time.Second, Wait(6))
+	assert.Equal(t, 30*time.Second, Wait(7))
+	assert.Equal(t, 30*time.Second, Wait(100))
+}
@@ -0,0 +1,45 @@
+package testserver
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"github.com/kalverra/todoist-jira-sync/config"
+	"github.com/kalverra/todoist-jira-sync/jira"
+	"github.com/kalverra/todoist-jira-sync/todoist"
+)
+
+// JiraClient returns a client for srv. Requests aren't retried.
+func JiraClient(tb testing.TB, srv *Jira) *jira.Client {
+	tb.Helper()
+
+	cfg := &config.Config{
+		JiraURL:   srv.URL,
+		JiraEmail: "test@example.com",
+		JiraToken: "test-token",
+	}
+	client, err := jira.NewClient(cfg, zerolog.Nop(),
+		jira.WithBaseURL(srv.URL),
+		jira.WithAgileBaseURL(srv.URL),
+		jira.WithHTTPClient(srv.Client()),
+		jira.WithMaxRetries(0),
+	)
+	if err != nil {
+		tb.Fatalf("new jira client: %v", err)
+	}
+	return client
+}
+
+// TodoistClient returns a client for srv. Requests aren't retried.
+func TodoistClient(tb testing.TB, srv *Todoist) *todoist.Client {
+	tb.Helper()
+
+	return todoist.NewClient(
+		"test-token",
+		zerolog.Nop(),
+		todoist.WithBaseURL(srv.URL),
+		todoist.WithHTTPClient(srv.Client()),
+		todoist.WithMaxRetries(0),
+	)
+}
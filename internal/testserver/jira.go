@@ -0,0 +1,410 @@
+package testserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kalverra/todoist-jira-sync/jira"
+)
+
+// Statuses of the fake Jira workflow.
+const (
+	JiraStatusToDo       = "To Do"
+	JiraStatusInProgress = "In Progress"
+	JiraStatusInReview   = "In Review"
+	JiraStatusDone       = "Done"
+)
+
+// jiraStatuses are the workflow statuses in order, with their IDs.
+var jiraStatuses = []jira.Status{
+	{ID: "1", Name: JiraStatusToDo},
+	{ID: "3", Name: JiraStatusInProgress},
+	{ID: "4", Name: JiraStatusInReview},
+	{ID: "5", Name: JiraStatusDone},
+}
+
+// jiraWorkflow maps each status to the statuses it can transition to.
+// Issues can be closed from any status and reopened once done.
+var jiraWorkflow = map[string][]string{
+	JiraStatusToDo:       {JiraStatusInProgress, JiraStatusDone},
+	JiraStatusInProgress: {JiraStatusToDo, JiraStatusInReview, JiraStatusDone},
+	JiraStatusInReview:   {JiraStatusInProgress, JiraStatusDone},
+	JiraStatusDone:       {JiraStatusToDo},
+}
+
+// JiraUser is the user the fake Jira API authenticates every request as.
+var JiraUser = jira.User{AccountID: "test-account-id", DisplayName: "Test User"}
+
+// Jira is an in-memory fake of the Jira Cloud REST API v3 endpoints used by
+// jira.Client. New issues start in To Do and move through jiraWorkflow.
+// Searches only understand the JQL project, key, and issue type clauses and
+// return a single page.
+type Jira struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	nextID   int
+	issueNum map[string]int // last issue number per project key
+	issues   []*jira.Issue  // in creation order
+	watchers map[string][]jira.User
+}
+
+// NewJira starts a fake Jira API that is closed when tb finishes.
+// Use JiraClient to get a client for it.
+func NewJira(tb testing.TB) *Jira {
+	tb.Helper()
+
+	s := &Jira{issueNum: make(map[string]int), watchers: make(map[string][]jira.User)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /issue", s.createIssue)
+	mux.HandleFunc("GET /issue/{key}", s.getIssue)
+	mux.HandleFunc("PUT /issue/{key}", s.updateIssue)
+	mux.HandleFunc("DELETE /issue/{key}", s.deleteIssue)
+	mux.HandleFunc("GET /issue/{key}/transitions", s.getTransitions)
+	mux.HandleFunc("POST /issue/{key}/transitions", s.doTransition)
+	mux.HandleFunc("GET /issue/{key}/comment", s.getComments)
+	mux.HandleFunc("POST /issue/{key}/comment", s.addComment)
+	mux.HandleFunc("GET /issue/{key}/watchers", s.getWatchers)
+	mux.HandleFunc("GET /search/jql", s.search)
+	mux.HandleFunc("GET /myself", s.getMyself)
+	mux.HandleFunc("GET /user", s.getUser)
+	s.Server = httptest.NewServer(mux)
+	tb.Cleanup(s.Close)
+	return s
+}
+
+// AddIssue adds an issue with the given fields and returns it. Project.Key is
+// required; the status defaults to To Do.
+func (s *Jira) AddIssue(fields jira.IssueFields) jira.Issue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return cloneIssue(s.addIssue(fields))
+}
+
+// Issue returns the issue with the given key.
+func (s *Jira) Issue(key string) (jira.Issue, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue := s.issue(key)
+	if issue == nil {
+		return jira.Issue{}, false
+	}
+	return cloneIssue(issue), true
+}
+
+// SetWatchers replaces the users watching an issue.
+func (s *Jira) SetWatchers(key string, watchers ...jira.User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.watchers[key] = watchers
+}
+
+// SetIssueLinks replaces an issue's links and marks it updated.
+func (s *Jira) SetIssueLinks(key string, links ...jira.IssueLink) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue := s.issue(key)
+	issue.Fields.IssueLinks = links
+	issue.Fields.Updated = jiraNow()
+}
+
+func (s *Jira) createIssue(w http.ResponseWriter, r *http.Request) {
+	var req jira.Issue
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.Fields == nil || req.Fields.Project == nil || req.Fields.Project.Key == "" || req.Fields.Summary == "" {
+		http.Error(w, "fields.project.key and fields.summary are required", http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue := s.addIssue(*req.Fields)
+	writeJSON(w, http.StatusCreated, jira.CreateIssueResponse{ID: issue.ID, Key: issue.Key, Self: issue.Self})
+}
+
+func (s *Jira) getIssue(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue, ok := s.findIssue(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, issue)
+}
+
+func (s *Jira) updateIssue(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Fields jira.UpdateFields `json:"fields"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue, ok := s.findIssue(w, r)
+	if !ok {
+		return
+	}
+	fields, update := issue.Fields, req.Fields
+	if update.Summary != "" {
+		fields.Summary = update.Summary
+	}
+	if update.Description != nil {
+		fields.Description = update.Description
+	}
+	if update.DueDate != "" {
+		fields.Duedate = update.DueDate
+	}
+	if update.Priority != nil {
+		fields.Priority = update.Priority
+	}
+	if update.Labels != nil {
+		fields.Labels = update.Labels
+	}
+	if update.TimeTracking != nil {
+		fields.TimeTracking = update.TimeTracking
+	}
+	fields.Updated = jiraNow()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Jira) deleteIssue(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue, ok := s.findIssue(w, r)
+	if !ok {
+		return
+	}
+	s.issues = slices.DeleteFunc(s.issues, func(i *jira.Issue) bool { return i == issue })
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Jira) getTransitions(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue, ok := s.findIssue(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, jira.TransitionsResponse{Transitions: transitionsFrom(issue.Fields.Status.Name)})
+}
+
+func (s *Jira) doTransition(w http.ResponseWriter, r *http.Request) {
+	var req jira.TransitionRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue, ok := s.findIssue(w, r)
+	if !ok {
+		return
+	}
+	i := slices.IndexFunc(transitionsFrom(issue.Fields.Status.Name), func(t jira.Transition) bool {
+		return t.ID == req.Transition.ID
+	})
+	if i < 0 {
+		http.Error(w, fmt.Sprintf("transition %q isn't valid for this issue", req.Transition.ID), http.StatusBadRequest)
+		return
+	}
+	to := transitionsFrom(issue.Fields.Status.Name)[i].To
+	issue.Fields.Status = &to
+	issue.Fields.Resolution = nil
+	if to.Name == JiraStatusDone {
+		issue.Fields.Resolution = &jira.Resolution{ID: "10000", Name: "Done"}
+	}
+	issue.Fields.Updated = jiraNow()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Jira) getComments(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue, ok := s.findIssue(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, issue.Fields.Comment)
+}
+
+func (s *Jira) getWatchers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue, ok := s.findIssue(w, r)
+	if !ok {
+		return
+	}
+	watchers := s.watchers[issue.Key]
+	writeJSON(w, http.StatusOK, jira.WatcherList{WatchCount: len(watchers), Watchers: watchers})
+}
+
+func (s *Jira) addComment(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Body json.RawMessage `json:"body"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue, ok := s.findIssue(w, r)
+	if !ok {
+		return
+	}
+	author := JiraUser
+	comment := jira.Comment{ID: s.newID(), Author: &author, Body: req.Body, Created: jiraNow()}
+	comment.Updated = comment.Created
+	page := issue.Fields.Comment
+	page.Comments = append(page.Comments, comment)
+	page.Total = len(page.Comments)
+	page.MaxResults = max(page.MaxResults, page.Total)
+	issue.Fields.Updated = comment.Created
+	writeJSON(w, http.StatusCreated, comment)
+}
+
+// jqlProject matches the project clause of a JQL query.
+var jqlProject = regexp.MustCompile(`(?i)\bproject\s*=\s*"?([^"\s)]+)"?`)
+
+// jqlIn matches the key and issue type IN clauses of a JQL query.
+var jqlIn = regexp.MustCompile(`(?i)\b(key|issuetype)\s+IN\s+\(([^)]*)\)`)
+
+func (s *Jira) search(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jql := r.URL.Query().Get("jql")
+	var project string
+	if m := jqlProject.FindStringSubmatch(jql); m != nil {
+		project = m[1]
+	}
+	in := make(map[string][]string)
+	for _, m := range jqlIn.FindAllStringSubmatch(jql, -1) {
+		for v := range strings.SplitSeq(m[2], ",") {
+			in[strings.ToLower(m[1])] = append(in[strings.ToLower(m[1])], strings.Trim(strings.TrimSpace(v), `"`))
+		}
+	}
+	matches := func(field, value string) bool {
+		values, ok := in[field]
+		return !ok || slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, value) })
+	}
+	issues := []jira.Issue{}
+	for _, issue := range s.issues {
+		var issueType string
+		if issue.Fields.IssueType != nil {
+			issueType = issue.Fields.IssueType.Name
+		}
+		if (project == "" || strings.EqualFold(issue.Fields.Project.Key, project)) &&
+			matches("key", issue.Key) && matches("issuetype", issueType) {
+			issues = append(issues, cloneIssue(issue))
+		}
+	}
+	// Every query the client builds orders by updated, newest first.
+	slices.SortStableFunc(issues, func(a, b jira.Issue) int {
+		return strings.Compare(b.Fields.Updated, a.Fields.Updated)
+	})
+	writeJSON(w, http.StatusOK, jira.SearchResponse{
+		Issues:     issues,
+		MaxResults: len(issues),
+		Total:      len(issues),
+		IsLast:     true,
+	})
+}
+
+func (s *Jira) getMyself(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, JiraUser)
+}
+
+func (s *Jira) getUser(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("accountId") != JiraUser.AccountID {
+		http.Error(w, "user not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, JiraUser)
+}
+
+// findIssue returns the issue named by the key path value, writing a 404 if there's none.
+func (s *Jira) findIssue(w http.ResponseWriter, r *http.Request) (*jira.Issue, bool) {
+	issue := s.issue(r.PathValue("key"))
+	if issue == nil {
+		http.Error(w, "issue does not exist", http.StatusNotFound)
+		return nil, false
+	}
+	return issue, true
+}
+
+// issue returns the issue with the given key or ID.
+func (s *Jira) issue(keyOrID string) *jira.Issue {
+	for _, issue := range s.issues {
+		if issue.Key == keyOrID || issue.ID == keyOrID {
+			return issue
+		}
+	}
+	return nil
+}
+
+func (s *Jira) addIssue(fields jira.IssueFields) *jira.Issue {
+	project := fields.Project.Key
+	s.issueNum[project]++
+	id := s.newID()
+	if fields.Status == nil {
+		status := jiraStatuses[0]
+		fields.Status = &status
+	}
+	if fields.Comment == nil {
+		fields.Comment = &jira.CommentPage{Comments: []jira.Comment{}}
+	}
+	if fields.Updated == "" {
+		fields.Updated = jiraNow()
+	}
+	issue := &jira.Issue{
+		ID:     id,
+		Key:    project + "-" + strconv.Itoa(s.issueNum[project]),
+		Self:   s.URL + "/issue/" + id,
+		Fields: &fields,
+	}
+	s.issues = append(s.issues, issue)
+	return issue
+}
+
+func (s *Jira) newID() string {
+	s.nextID++
+	return strconv.Itoa(10000 + s.nextID)
+}
+
+// transitionsFrom returns the transitions available from status, each named
+// after the status it leads to.
+func transitionsFrom(status string) []jira.Transition {
+	var transitions []jira.Transition
+	for _, to := range jiraStatuses {
+		if slices.Contains(jiraWorkflow[status], to.Name) {
+			transitions = append(transitions, jira.Transition{ID: to.ID + "1", Name: to.Name, To: to})
+		}
+	}
+	return transitions
+}
+
+// cloneIssue copies issue deep enough that the caller can't modify the stored
+// issue's fields or comments.
+func cloneIssue(issue *jira.Issue) jira.Issue {
+	clone := *issue
+	fields := *issue.Fields
+	comments := *fields.Comment
+	comments.Comments = slices.Clone(comments.Comments)
+	fields.Comment = &comments
+	fields.Labels = slices.Clone(fields.Labels)
+	clone.Fields = &fields
+	return clone
+}
+
+func jiraNow() string {
+	return time.Now().UTC().Format(jira.TimeFormat)
+}
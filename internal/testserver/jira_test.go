@@ -0,0 +1,61 @@
+package testserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kalverra/todoist-jira-sync/jira"
+)
+
+func TestJira(t *testing.T) {
+	t.Parallel()
+
+	srv := NewJira(t)
+	client := JiraClient(t, srv)
+	ctx := t.Context()
+	srv.AddIssue(jira.IssueFields{Project: &jira.Project{Key: "OTHER"}, Summary: "other project"})
+
+	created, err := client.CreateIssue(ctx, &jira.Issue{Fields: &jira.IssueFields{
+		Project:     &jira.Project{Key: "PROJ"},
+		Summary:     "Write tests",
+		Description: jira.TextToADF("for the fake"),
+		IssueType:   &jira.IssueType{Name: "Task"},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, "PROJ-1", created.Key)
+
+	require.NoError(t, client.UpdateIssue(ctx, created.Key, jira.UpdateFields{DueDate: "2026-03-04"}))
+	issue, err := client.GetIssue(ctx, created.Key, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Write tests", issue.Fields.Summary)
+	assert.Equal(t, "for the fake", jira.ADFToText(issue.Fields.Description))
+	assert.Equal(t, "2026-03-04", issue.Fields.Duedate)
+	assert.Equal(t, JiraStatusToDo, issue.Fields.Status.Name)
+
+	require.Error(t, client.DoTransition(ctx, created.Key, JiraStatusInReview), "can't skip In Progress")
+	require.NoError(t, client.DoTransition(ctx, created.Key, JiraStatusInProgress))
+	require.NoError(t, client.DoTransition(ctx, created.Key, JiraStatusDone))
+	got, ok := srv.Issue(created.Key)
+	require.True(t, ok)
+	assert.Equal(t, JiraStatusDone, got.Fields.Status.Name)
+	assert.NotNil(t, got.Fields.Resolution, "done issues should be resolved")
+
+	_, err = client.AddComment(ctx, created.Key, jira.TextToADF("hello"))
+	require.NoError(t, err)
+	issues, err := client.SearchIssuesPaginated(ctx, jira.NewJQLBuilder().Project("PROJ").Build(), nil)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.Len(t, issues[0].Fields.Comment.Comments, 1)
+	assert.Equal(t, "hello", jira.ADFToText(issues[0].Fields.Comment.Comments[0].Body))
+	assert.Equal(t, JiraUser.DisplayName, issues[0].Fields.Comment.Comments[0].Author.DisplayName)
+
+	me, err := client.GetCurrentUser(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, JiraUser, *me)
+
+	require.NoError(t, client.DeleteIssue(ctx, created.Key))
+	_, err = client.GetIssue(ctx, created.Key, nil)
+	require.ErrorIs(t, err, jira.ErrNotFound)
+}
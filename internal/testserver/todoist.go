@@ -0,0 +1,465 @@
+// Package testserver provides in-memory fakes of the Todoist and Jira APIs,
+// so tests can exercise the real clients without credentials.
+package testserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kalverra/todoist-jira-sync/todoist"
+)
+
+// TodoistUser is the user the fake Todoist API authenticates every request as.
+// Comments created through the API are posted by them.
+var TodoistUser = todoist.User{ID: "test-user-id", FullName: "Test User"}
+
+// Todoist is an in-memory fake of the Todoist API v1 endpoints used by
+// todoist.Client. List endpoints return everything in a single page.
+type Todoist struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	nextID   int
+	projects []todoist.Project
+	sections []todoist.Section
+	tasks    []*todoist.Task // active and completed, in creation order
+	comments map[string][]todoist.Comment
+
+	collaborators map[string][]todoist.Collaborator // by project ID
+}
+
+// NewTodoist starts a fake Todoist API that is closed when tb finishes.
+// Use TodoistClient to get a client for it.
+func NewTodoist(tb testing.TB) *Todoist {
+	tb.Helper()
+
+	s := &Todoist{
+		comments:      make(map[string][]todoist.Comment),
+		collaborators: make(map[string][]todoist.Collaborator),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /projects", s.getProjects)
+	mux.HandleFunc("GET /projects/{id}/collaborators", s.getCollaborators)
+	mux.HandleFunc("GET /sections", s.getSections)
+	mux.HandleFunc("POST /sections", s.createSection)
+	mux.HandleFunc("GET /tasks", s.getTasks)
+	mux.HandleFunc("POST /tasks", s.createTask)
+	mux.HandleFunc("GET /tasks/completed/by_completion_date", s.getCompletedTasks)
+	mux.HandleFunc("GET /tasks/{id}", s.getTask)
+	mux.HandleFunc("POST /tasks/{id}", s.updateTask)
+	mux.HandleFunc("DELETE /tasks/{id}", s.deleteTask)
+	mux.HandleFunc("POST /tasks/{id}/close", s.closeTask)
+	mux.HandleFunc("POST /tasks/{id}/reopen", s.reopenTask)
+	mux.HandleFunc("POST /tasks/{id}/move", s.moveTask)
+	mux.HandleFunc("GET /comments", s.getComments)
+	mux.HandleFunc("POST /comments", s.createComment)
+	mux.HandleFunc("POST /comments/{id}", s.updateComment)
+	mux.HandleFunc("GET /user", s.getUser)
+	s.Server = httptest.NewServer(mux)
+	tb.Cleanup(s.Close)
+	return s
+}
+
+// AddProject adds a project and returns it.
+func (s *Todoist) AddProject(name string) todoist.Project {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	project := todoist.Project{ID: s.newID(), Name: name, CreatedAt: now(), UpdatedAt: now()}
+	s.projects = append(s.projects, project)
+	return project
+}
+
+// AddCollaborator shares a project with a user.
+func (s *Todoist) AddCollaborator(projectID string, collaborator todoist.Collaborator) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.collaborators[projectID] = append(s.collaborators[projectID], collaborator)
+}
+
+// AddSection adds a section to a project and returns it.
+func (s *Todoist) AddSection(projectID, name string) todoist.Section {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addSection(projectID, name)
+}
+
+// AddTask adds task as is, filling in its ID and timestamps if they're empty.
+func (s *Todoist) AddTask(task todoist.Task) todoist.Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if task.ID == "" {
+		task.ID = s.newID()
+	}
+	if task.AddedAt == "" {
+		task.AddedAt = now()
+	}
+	if task.UpdatedAt == "" {
+		task.UpdatedAt = task.AddedAt
+	}
+	s.tasks = append(s.tasks, &task)
+	return task
+}
+
+// Task returns the task with the given ID, whether it's active or completed.
+func (s *Todoist) Task(id string) (todoist.Task, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task := s.task(id)
+	if task == nil {
+		return todoist.Task{}, false
+	}
+	return *task, true
+}
+
+// AddComment adds comment to a task as is, filling in its ID and posting time if they're empty.
+func (s *Todoist) AddComment(taskID string, comment todoist.Comment) todoist.Comment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if comment.ID == "" {
+		comment.ID = s.newID()
+	}
+	if comment.PostedAt == "" {
+		comment.PostedAt = now()
+	}
+	s.comments[taskID] = append(s.comments[taskID], comment)
+	return comment
+}
+
+// Comments returns the comments on a task.
+func (s *Todoist) Comments(taskID string) []todoist.Comment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.comments[taskID])
+}
+
+func (s *Todoist) getProjects(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeResults(w, s.projects)
+}
+
+func (s *Todoist) getCollaborators(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeResults(w, s.collaborators[r.PathValue("id")])
+}
+
+func (s *Todoist) getSections(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	projectID := r.URL.Query().Get("project_id")
+	var sections []todoist.Section
+	for _, section := range s.sections {
+		if projectID == "" || section.ProjectID == projectID {
+			sections = append(sections, section)
+		}
+	}
+	writeResults(w, sections)
+}
+
+func (s *Todoist) createSection(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ProjectID string `json:"project_id"`
+		Name      string `json:"name"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, s.addSection(req.ProjectID, req.Name))
+}
+
+func (s *Todoist) getTasks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	projectID := r.URL.Query().Get("project_id")
+	label := r.URL.Query().Get("label")
+	var tasks []todoist.Task
+	for _, task := range s.tasks {
+		if !task.Checked && (projectID == "" || task.ProjectID == projectID) &&
+			(label == "" || slices.Contains(task.Labels, label)) {
+			tasks = append(tasks, *task)
+		}
+	}
+	writeResults(w, tasks)
+}
+
+func (s *Todoist) createTask(w http.ResponseWriter, r *http.Request) {
+	var req todoist.CreateTaskRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task := &todoist.Task{
+		ID:          s.newID(),
+		ProjectID:   req.ProjectID,
+		SectionID:   req.SectionID,
+		Content:     req.Content,
+		Description: req.Description,
+		Labels:      req.Labels,
+		Priority:    max(req.Priority, 1),
+		ChildOrder:  req.ChildOrder,
+		AddedAt:     now(),
+	}
+	task.UpdatedAt = task.AddedAt
+	if req.DueDate != "" {
+		task.Due = &todoist.Due{Date: req.DueDate, String: req.DueDate}
+	}
+	if req.DeadlineDate != "" {
+		task.Deadline = &todoist.Deadline{Date: req.DeadlineDate}
+	}
+	if req.Duration > 0 {
+		task.Duration = &todoist.Duration{Amount: req.Duration, Unit: req.DurationUnit}
+	}
+	s.tasks = append(s.tasks, task)
+	writeJSON(w, http.StatusOK, task)
+}
+
+func (s *Todoist) getCompletedTasks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	query := r.URL.Query()
+	projectID := query.Get("project_id")
+	since, _ := time.Parse(time.RFC3339, query.Get("since"))
+	until, _ := time.Parse(time.RFC3339, query.Get("until"))
+	items := []todoist.Task{}
+	for _, task := range s.tasks {
+		if !task.Checked || (projectID != "" && task.ProjectID != projectID) {
+			continue
+		}
+		completed, err := task.CompletedAtTime()
+		if err != nil ||
+			(!since.IsZero() && completed.Before(since)) ||
+			(!until.IsZero() && completed.After(until)) {
+			continue
+		}
+		items = append(items, *task)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": items, "next_cursor": nil})
+}
+
+func (s *Todoist) getTask(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, ok := s.findTask(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, task)
+}
+
+func (s *Todoist) updateTask(w http.ResponseWriter, r *http.Request) {
+	var req todoist.UpdateTaskRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, ok := s.findTask(w, r)
+	if !ok {
+		return
+	}
+	if req.Content != nil {
+		task.Content = *req.Content
+	}
+	if req.Description != nil {
+		task.Description = *req.Description
+	}
+	if req.DueDate != nil {
+		task.Due = &todoist.Due{Date: *req.DueDate, String: *req.DueDate}
+	}
+	if req.DeadlineDate != nil {
+		task.Deadline = &todoist.Deadline{Date: *req.DeadlineDate}
+	}
+	if req.Priority != nil {
+		task.Priority = *req.Priority
+	}
+	if req.Labels != nil {
+		task.Labels = req.Labels
+	}
+	if req.Duration != nil && req.DurationUnit != nil {
+		task.Duration = &todoist.Duration{Amount: *req.Duration, Unit: *req.DurationUnit}
+	}
+	if req.ChildOrder != nil {
+		task.ChildOrder = *req.ChildOrder
+	}
+	task.UpdatedAt = now()
+	writeJSON(w, http.StatusOK, task)
+}
+
+func (s *Todoist) deleteTask(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.findTask(w, r); !ok {
+		return
+	}
+	id := r.PathValue("id")
+	s.tasks = slices.DeleteFunc(s.tasks, func(task *todoist.Task) bool { return task.ID == id })
+	delete(s.comments, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Todoist) closeTask(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, ok := s.findTask(w, r)
+	if !ok {
+		return
+	}
+	task.Checked = true
+	task.CompletedAt = now()
+	task.UpdatedAt = task.CompletedAt
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Todoist) reopenTask(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, ok := s.findTask(w, r)
+	if !ok {
+		return
+	}
+	task.Checked = false
+	task.CompletedAt = ""
+	task.UpdatedAt = now()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Todoist) moveTask(w http.ResponseWriter, r *http.Request) {
+	var req todoist.MoveTaskRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, ok := s.findTask(w, r)
+	if !ok {
+		return
+	}
+	if req.ProjectID != "" {
+		task.ProjectID = req.ProjectID
+	}
+	if req.SectionID != "" {
+		task.SectionID = req.SectionID
+	}
+	if req.ParentID != "" {
+		task.ParentID = req.ParentID
+	}
+	task.UpdatedAt = now()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Todoist) getComments(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeResults(w, s.comments[r.URL.Query().Get("task_id")])
+}
+
+func (s *Todoist) createComment(w http.ResponseWriter, r *http.Request) {
+	var req todoist.CreateCommentRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.task(req.TaskID) == nil {
+		http.Error(w, "task not found", http.StatusNotFound)
+		return
+	}
+	comment := todoist.Comment{ID: s.newID(), PostedUID: TodoistUser.ID, Content: req.Content, PostedAt: now()}
+	s.comments[req.TaskID] = append(s.comments[req.TaskID], comment)
+	writeJSON(w, http.StatusOK, comment)
+}
+
+func (s *Todoist) updateComment(w http.ResponseWriter, r *http.Request) {
+	var req todoist.UpdateCommentRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for taskID, comments := range s.comments {
+		for i := range comments {
+			if comments[i].ID == r.PathValue("id") {
+				s.comments[taskID][i].Content = req.Content
+				writeJSON(w, http.StatusOK, s.comments[taskID][i])
+				return
+			}
+		}
+	}
+	http.Error(w, "comment not found", http.StatusNotFound)
+}
+
+func (s *Todoist) getUser(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, TodoistUser)
+}
+
+// findTask returns the task named by the id path value, writing a 404 if there's none.
+func (s *Todoist) findTask(w http.ResponseWriter, r *http.Request) (*todoist.Task, bool) {
+	task := s.task(r.PathValue("id"))
+	if task == nil {
+		http.Error(w, "task not found", http.StatusNotFound)
+		return nil, false
+	}
+	return task, true
+}
+
+func (s *Todoist) task(id string) *todoist.Task {
+	for _, task := range s.tasks {
+		if task.ID == id {
+			return task
+		}
+	}
+	return nil
+}
+
+func (s *Todoist) addSection(projectID, name string) todoist.Section {
+	section := todoist.Section{
+		ID:           s.newID(),
+		ProjectID:    projectID,
+		Name:         name,
+		SectionOrder: len(s.sections) + 1,
+		AddedAt:      now(),
+	}
+	s.sections = append(s.sections, section)
+	return section
+}
+
+func (s *Todoist) newID() string {
+	s.nextID++
+	return strconv.Itoa(s.nextID)
+}
+
+// writeResults writes a single page of a Todoist list response.
+func writeResults[T any](w http.ResponseWriter, results []T) {
+	if results == nil {
+		results = []T{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"results": results, "next_cursor": nil})
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339Nano)
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
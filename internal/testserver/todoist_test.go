@@ -0,0 +1,78 @@
+package testserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kalverra/todoist-jira-sync/todoist"
+)
+
+func TestTodoist(t *testing.T) {
+	t.Parallel()
+
+	srv := NewTodoist(t)
+	client := TodoistClient(t, srv)
+	ctx := t.Context()
+	project := srv.AddProject("Work")
+
+	projects, err := client.GetProjects(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []todoist.Project{project}, projects)
+
+	section, err := client.CreateSection(ctx, project.ID, "In Progress")
+	require.NoError(t, err)
+	sections, err := client.GetSections(ctx, project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []todoist.Section{*section}, sections)
+
+	task, err := client.CreateTask(ctx, todoist.CreateTaskRequest{
+		Content:   "Write tests",
+		ProjectID: project.ID,
+		DueDate:   "2026-03-04",
+		Labels:    []string{"jira-sync"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, task.Priority, "priority should default to 1")
+	assert.Equal(t, "2026-03-04", task.DueDate())
+
+	content := "Write more tests"
+	updated, err := client.UpdateTask(ctx, task.ID, todoist.UpdateTaskRequest{
+		Content:   &content,
+		SectionID: &section.ID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, content, updated.Content)
+	got, ok := srv.Task(task.ID)
+	require.True(t, ok)
+	assert.Equal(t, section.ID, got.SectionID)
+
+	_, err = client.CreateComment(ctx, todoist.CreateCommentRequest{TaskID: task.ID, Content: "hello"})
+	require.NoError(t, err)
+	comments, err := client.GetComments(ctx, task.ID)
+	require.NoError(t, err)
+	require.Len(t, comments, 1)
+	assert.Equal(t, "hello", comments[0].Content)
+
+	require.NoError(t, client.CloseTask(ctx, task.ID))
+	active, err := client.GetTasks(ctx, project.ID)
+	require.NoError(t, err)
+	assert.Empty(t, active)
+	since := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	until := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	completed, err := client.GetCompletedTasks(ctx, project.ID, since, until)
+	require.NoError(t, err)
+	require.Len(t, completed, 1)
+	assert.Equal(t, task.ID, completed[0].ID)
+
+	require.NoError(t, client.ReopenTask(ctx, task.ID))
+	active, err = client.GetTasks(ctx, project.ID)
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+
+	require.NoError(t, client.DeleteTask(ctx, task.ID))
+	_, err = client.GetTask(ctx, task.ID)
+	require.Error(t, err, "deleted task should be gone")
+}
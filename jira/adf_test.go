@@ -2,6 +2,7 @@ package jira
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -104,3 +105,42 @@ func TestRoundTrip(t *testing.T) {
 	got := ADFToText(adf)
 	assert.Equal(t, text, got)
 }
+
+// nestedADF returns a document with depth nested nodes around a text node.
+func nestedADF(depth int) string {
+	return `{"type":"doc","content":[` +
+		strings.Repeat(`{"type":"paragraph","content":[`, depth) +
+		`{"type":"text","text":"deep"}` +
+		strings.Repeat(`]}`, depth) +
+		`]}`
+}
+
+func FuzzADFToText(f *testing.F) {
+	for _, seed := range []string{
+		`{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"hello"}]}]}`,
+		`{"type":"doc","version":1,"content":[{"type":"paragraph"},{"type":"heading","content":[{"text":"x"}]}]}`,
+		`{"type":"doc","version":1,"content":null}`,
+		`{"type":"doc","content":[{"content":null},{"type":"text"},{}]}`,
+		`{"content":[{"content":[{"content":[{"type":"text","text":"no types"}]}]}]}`,
+		`{"type":"doc","content":[{"type":"text","text":"\u0000\ud800\uffff"}]}`,
+		`{"type":"doc","content":[{"type":"text","text":"` + "\xff\xfe" + `"}]}`,
+		`{"type":"doc","content":[` + strings.Repeat(`{},`, 1000) + `{}]}`,
+		nestedADF(100),
+		nestedADF(20_000),
+		`{"type":"doc","content":[{"type":"paragraph","content":[`,
+		`{"type":"doc","content":{"type":"text"}}`,
+		`[]`,
+		`null`,
+		`"text"`,
+		``,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, doc []byte) {
+		text := ADFToText(doc)
+		// Decoding can at most triple a byte, when an invalid UTF-8 byte
+		// becomes a 3 byte replacement character.
+		assert.LessOrEqual(t, len(text), 3*len(doc))
+	})
+}
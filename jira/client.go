@@ -4,13 +4,20 @@ package jira
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/rs/zerolog"
 	"resty.dev/v3"
 
 	"github.com/kalverra/todoist-jira-sync/config"
+	"github.com/kalverra/todoist-jira-sync/internal/retry"
 )
 
 const (
@@ -18,22 +25,103 @@ const (
 	SprintInfoField = "customfield_10020"
 	// EpicLinkField is the custom field name to get epic link for a Jira issue.
 	EpicLinkField = "customfield_10014"
+	// StoryPointsField is the custom field name of a Jira issue's story point estimate.
+	StoryPointsField = "customfield_10016"
 )
 
+// ErrNotFound is returned when Jira responds with 404, e.g. for a deleted issue.
+var ErrNotFound = errors.New("not found")
+
 // Client communicates with the Jira Cloud REST API v3 via Resty.
 type Client struct {
-	http   *resty.Client
-	logger zerolog.Logger
-	cfg    *config.Config
+	http     *resty.Client
+	logger   zerolog.Logger
+	cfg      *config.Config
+	agileURL string // Jira Software REST API base URL, for board endpoints
+}
+
+type options struct {
+	baseURL      string
+	agileBaseURL string
+	httpClient   *http.Client
+	logger       zerolog.Logger
+	timeout      time.Duration
+	maxRetries   int
+}
+
+// Option configures optional Client behavior.
+type Option func(*options)
+
+// WithBaseURL overrides the REST API base URL, which defaults to Config.JiraURL + "/rest/api/3",
+// e.g. to point at a test server.
+func WithBaseURL(url string) Option {
+	return func(o *options) {
+		o.baseURL = url
+	}
+}
+
+// WithAgileBaseURL overrides the Jira Software REST API base URL used for board
+// endpoints, which defaults to Config.JiraURL + "/rest/agile/1.0".
+func WithAgileBaseURL(url string) Option {
+	return func(o *options) {
+		o.agileBaseURL = url
+	}
+}
+
+// WithHTTPClient sets the underlying HTTP client.
+func WithHTTPClient(c *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = c
+	}
+}
+
+// WithLogger overrides the logger passed to NewClient.
+func WithLogger(l zerolog.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// WithTimeout overrides Config.JiraRequestTimeout.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// WithMaxRetries overrides Config.JiraMaxRetries.
+func WithMaxRetries(n int) Option {
+	return func(o *options) {
+		o.maxRetries = n
+	}
 }
 
 // NewClient creates a new Jira API v3 client.
-func NewClient(cfg *config.Config, logger zerolog.Logger) (*Client, error) {
-	l := logger.With().Str("component", "jira").Logger()
+// Idempotent requests that fail with a 503 or 504 are retried with exponential
+// backoff, up to Config.JiraMaxRetries times.
+func NewClient(cfg *config.Config, logger zerolog.Logger, opts ...Option) (*Client, error) {
+	o := options{
+		baseURL:      cfg.JiraURL + "/rest/api/3",
+		agileBaseURL: cfg.JiraURL + "/rest/agile/1.0",
+		logger:       logger,
+		timeout:      cfg.JiraRequestTimeout,
+		maxRetries:   cfg.JiraMaxRetries,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
 
-	r := resty.New().
-		SetBasicAuth(cfg.JiraEmail, cfg.JiraToken).
-		SetBaseURL(cfg.JiraURL+"/rest/api/3").
+	l := o.logger.With().Str("component", "jira").Logger()
+	r := resty.New()
+	if o.httpClient != nil {
+		r = resty.NewWithClient(o.httpClient)
+	}
+	if cfg.JiraAuthType == config.JiraAuthTypePAT {
+		r.SetAuthScheme("Bearer").SetAuthToken(cfg.JiraToken)
+	} else {
+		r.SetBasicAuth(cfg.JiraEmail, cfg.JiraToken)
+	}
+	r.SetBaseURL(o.baseURL).
 		SetHeader("Accept", "application/json").
 		SetHeader("Content-Type", "application/json").
 		AddResponseMiddleware(func(_ *resty.Client, resp *resty.Response) error {
@@ -57,13 +145,21 @@ func NewClient(cfg *config.Config, logger zerolog.Logger) (*Client, error) {
 				ev.Str("resp_body", body)
 			}
 			ev.Msg("http round trip")
+			if resp.StatusCode() == http.StatusNotFound {
+				return fmt.Errorf("%w: jira API error %d: %s", ErrNotFound, resp.StatusCode(), body)
+			}
 			if resp.IsError() {
+				if req.Attempt > 1 {
+					return fmt.Errorf("jira API error %d after %d attempts: %s", resp.StatusCode(), req.Attempt, body)
+				}
 				return fmt.Errorf("jira API error %d: %s", resp.StatusCode(), body)
 			}
 			return nil
-		})
+		}).
+		SetTimeout(o.timeout)
+	retry.Configure(r, o.maxRetries, l, "jira")
 
-	return &Client{http: r, logger: l, cfg: cfg}, nil
+	return &Client{http: r, logger: l, cfg: cfg, agileURL: o.agileBaseURL}, nil
 }
 
 // SearchIssues searches for issues using JQL (enhanced search endpoint).
@@ -89,6 +185,129 @@ func (c *Client) SearchIssues(
 	return result.Issues, nil
 }
 
+// SearchIssuesPaginated searches for issues using JQL, following nextPageToken
+// until the last page so the caller gets every matching issue.
+// Page size is Config.JiraSearchPageSize.
+func (c *Client) SearchIssuesPaginated(
+	ctx context.Context,
+	jql string,
+	fields []string,
+) ([]Issue, error) {
+	pageSize := c.cfg.JiraSearchPageSize
+	if pageSize <= 0 {
+		pageSize = config.DefaultJiraSearchPageSize
+	}
+
+	var all []Issue
+	var pageToken string
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var page SearchResponse
+		req := c.http.R().
+			SetContext(ctx).
+			SetQueryParam("jql", jql).
+			SetQueryParam("maxResults", fmt.Sprintf("%d", pageSize)).
+			SetResult(&page)
+		if len(fields) > 0 {
+			req.SetQueryParam("fields", strings.Join(fields, ","))
+		}
+		if pageToken != "" {
+			req.SetQueryParam("nextPageToken", pageToken)
+		}
+		if _, err := req.Get("/search/jql"); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Issues...)
+		if page.IsLast || page.NextPageToken == "" || len(page.Issues) == 0 {
+			break
+		}
+		pageToken = page.NextPageToken
+	}
+	return all, nil
+}
+
+// GetIssuesForBoard returns every issue on a Jira Software board that matches jql,
+// following startAt/total pagination. Page size is Config.JiraSearchPageSize.
+func (c *Client) GetIssuesForBoard(
+	ctx context.Context,
+	boardID int,
+	jql string,
+	fields []string,
+) ([]Issue, error) {
+	pageSize := c.cfg.JiraSearchPageSize
+	if pageSize <= 0 {
+		pageSize = config.DefaultJiraSearchPageSize
+	}
+
+	var all []Issue
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var page SearchResponse
+		req := c.http.R().
+			SetContext(ctx).
+			SetQueryParam("jql", jql).
+			SetQueryParam("startAt", strconv.Itoa(len(all))).
+			SetQueryParam("maxResults", strconv.Itoa(pageSize)).
+			SetResult(&page)
+		if len(fields) > 0 {
+			req.SetQueryParam("fields", strings.Join(fields, ","))
+		}
+		if _, err := req.Get(fmt.Sprintf("%s/board/%d/issue", c.agileURL, boardID)); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Issues...)
+		if len(page.Issues) == 0 || len(all) >= page.Total {
+			break
+		}
+	}
+	return all, nil
+}
+
+// GetSprintsForBoard returns the sprints of a Jira Software board in the given
+// state, e.g. SprintStateActive, or every sprint if state is empty. States can
+// be comma-separated.
+func (c *Client) GetSprintsForBoard(ctx context.Context, boardID int, state string) ([]Sprint, error) {
+	var all []Sprint
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var page SprintsResponse
+		req := c.http.R().
+			SetContext(ctx).
+			SetQueryParam("startAt", strconv.Itoa(len(all))).
+			SetResult(&page)
+		if state != "" {
+			req.SetQueryParam("state", state)
+		}
+		if _, err := req.Get(fmt.Sprintf("%s/board/%d/sprint", c.agileURL, boardID)); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Values...)
+		if page.IsLast || len(page.Values) == 0 {
+			break
+		}
+	}
+	return all, nil
+}
+
+// GetActiveSprint returns the active sprint of a Jira Software board, or nil
+// if it has none. If several sprints are active, the first one Jira lists wins.
+func (c *Client) GetActiveSprint(ctx context.Context, boardID int) (*Sprint, error) {
+	sprints, err := c.GetSprintsForBoard(ctx, boardID, SprintStateActive)
+	if err != nil {
+		return nil, err
+	}
+	if len(sprints) == 0 {
+		return nil, nil
+	}
+	return &sprints[0], nil
+}
+
 // CreateIssue creates a new Jira issue.
 func (c *Client) CreateIssue(ctx context.Context, issue *Issue) (*CreateIssueResponse, error) {
 	var result CreateIssueResponse
@@ -118,11 +337,64 @@ func (c *Client) GetIssue(ctx context.Context, key string, fields []string) (*Is
 	return &result, nil
 }
 
-// UpdateIssue updates an existing issue's fields.
-func (c *Client) UpdateIssue(ctx context.Context, key string, issue *Issue) error {
+// GetAttachments returns the files attached to an issue.
+func (c *Client) GetAttachments(ctx context.Context, key string) ([]Attachment, error) {
+	issue, err := c.GetIssue(ctx, key, []string{"attachment"})
+	if err != nil {
+		return nil, err
+	}
+	if issue.Fields == nil {
+		return nil, nil
+	}
+	return issue.Fields.Attachments, nil
+}
+
+// GetWatchers returns the users watching an issue.
+func (c *Client) GetWatchers(ctx context.Context, key string) (*WatcherList, error) {
+	var result WatcherList
+	_, err := c.http.R().
+		SetContext(ctx).
+		SetResult(&result).
+		Get("/issue/" + key + "/watchers")
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DownloadAttachment streams the content of an attachment. The caller must
+// close the returned reader.
+func (c *Client) DownloadAttachment(ctx context.Context, attachment Attachment) (io.ReadCloser, error) {
+	resp, err := c.http.R().
+		SetContext(ctx).
+		SetDoNotParseResponse(true).
+		Get(attachment.Content)
+	if err != nil {
+		if resp != nil && resp.Body != nil {
+			_ = resp.Body.Close()
+		}
+		return nil, fmt.Errorf("download attachment %q: %w", attachment.Filename, err)
+	}
+	return resp.Body, nil
+}
+
+// GetIssueLinks returns the links from an issue to other issues.
+func (c *Client) GetIssueLinks(ctx context.Context, key string) ([]IssueLink, error) {
+	issue, err := c.GetIssue(ctx, key, []string{"issuelinks"})
+	if err != nil {
+		return nil, err
+	}
+	if issue.Fields == nil {
+		return nil, nil
+	}
+	return issue.Fields.IssueLinks, nil
+}
+
+// UpdateIssue updates an existing issue's non-zero fields.
+func (c *Client) UpdateIssue(ctx context.Context, key string, fields UpdateFields) error {
 	_, err := c.http.R().
 		SetContext(ctx).
-		SetBody(issue).
+		SetBody(updateIssueRequest{Fields: fields}).
 		Put("/issue/" + key)
 	return err
 }
@@ -182,6 +454,95 @@ func (c *Client) DoTransition(ctx context.Context, issueKey, targetStatus string
 	return nil
 }
 
+// GetProjectStatuses returns the names of all workflow statuses used by any
+// issue type in the project.
+func (c *Client) GetProjectStatuses(ctx context.Context, projectKey string) ([]string, error) {
+	var result []IssueTypeStatuses
+	_, err := c.http.R().
+		SetContext(ctx).
+		SetResult(&result).
+		Get("/project/" + projectKey + "/statuses")
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, it := range result {
+		for _, st := range it.Statuses {
+			if !slices.Contains(names, st.Name) {
+				names = append(names, st.Name)
+			}
+		}
+	}
+	return names, nil
+}
+
+// GetComponents fetches the components of a project.
+func (c *Client) GetComponents(ctx context.Context, projectKey string) ([]Component, error) {
+	var result []Component
+	_, err := c.http.R().
+		SetContext(ctx).
+		SetResult(&result).
+		Get("/project/" + projectKey + "/components")
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetComponentNames returns the names of the components of a project.
+func (c *Client) GetComponentNames(ctx context.Context, projectKey string) ([]string, error) {
+	components, err := c.GetComponents(ctx, projectKey)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(components))
+	for _, component := range components {
+		names = append(names, component.Name)
+	}
+	return names, nil
+}
+
+// GetVersions fetches the versions of a project.
+func (c *Client) GetVersions(ctx context.Context, projectKey string) ([]Version, error) {
+	var result []Version
+	_, err := c.http.R().
+		SetContext(ctx).
+		SetResult(&result).
+		Get("/project/" + projectKey + "/versions")
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetCurrentUser fetches the user whose credentials the client uses.
+func (c *Client) GetCurrentUser(ctx context.Context) (*User, error) {
+	var result User
+	_, err := c.http.R().
+		SetContext(ctx).
+		SetResult(&result).
+		Get("/myself")
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetUserByAccountID fetches a user by their Atlassian account ID.
+func (c *Client) GetUserByAccountID(ctx context.Context, accountID string) (*User, error) {
+	var result User
+	_, err := c.http.R().
+		SetContext(ctx).
+		SetQueryParam("accountId", accountID).
+		SetResult(&result).
+		Get("/user")
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 // AddComment adds a comment to an issue. Body must be ADF JSON.
 func (c *Client) AddComment(ctx context.Context, issueKey string, body json.RawMessage) (*Comment, error) {
 	var result Comment
@@ -199,16 +560,15 @@ func (c *Client) AddComment(ctx context.Context, issueKey string, body json.RawM
 // InCurrentSprint checks if the issue is in an active sprint by inspecting
 // the SprintRaw (customfield_10020) field.
 func InCurrentSprint(issue *Issue) bool {
-	if issue.Fields == nil || len(issue.Fields.SprintRaw) == 0 || string(issue.Fields.SprintRaw) == "null" {
+	if issue.Fields == nil {
 		return false
 	}
-
-	var sprints []map[string]any
-	if err := json.Unmarshal(issue.Fields.SprintRaw, &sprints); err != nil {
+	sprints, err := ParseSprintsFromRaw(issue.Fields.SprintRaw)
+	if err != nil {
 		return false
 	}
 	for _, s := range sprints {
-		if s["state"] == "active" {
+		if s.State == SprintStateActive {
 			return true
 		}
 	}
@@ -2,8 +2,14 @@ package jira
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strconv"
 	"testing"
 	"time"
 
@@ -80,12 +86,10 @@ func TestJiraIssueCRUD(t *testing.T) { //nolint:paralleltest
 	newDesc := "updated description"
 	newDue := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
 
-	err := client.UpdateIssue(ctx, issue.Key, &Issue{
-		Fields: &IssueFields{
-			Summary:     newSummary,
-			Description: TextToADF(newDesc),
-			Duedate:     newDue,
-		},
+	err := client.UpdateIssue(ctx, issue.Key, UpdateFields{
+		Summary:     newSummary,
+		Description: TextToADF(newDesc),
+		DueDate:     newDue,
 	})
 	require.NoError(t, err)
 
@@ -95,3 +99,538 @@ func TestJiraIssueCRUD(t *testing.T) { //nolint:paralleltest
 	assert.Equal(t, newDesc, ADFToText(fetched.Fields.Description))
 	assert.Equal(t, newDue, fetched.Fields.Duedate)
 }
+
+func TestJiraComponents(t *testing.T) { //nolint:paralleltest
+	client, project := e2eSetup(t)
+	ctx := context.Background()
+
+	components, err := client.GetComponents(ctx, project)
+	require.NoError(t, err)
+	if len(components) == 0 {
+		t.Skipf("project %s has no components", project)
+	}
+
+	created, err := client.CreateIssue(ctx, &Issue{
+		Fields: &IssueFields{
+			Project:    &Project{Key: project},
+			Summary:    fmt.Sprintf("e2e-test-%s", testID()),
+			IssueType:  &IssueType{Name: "Story"},
+			Components: []Component{{ID: components[0].ID}},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		err := client.DeleteIssue(context.Background(), created.Key)
+		require.NoError(t, err)
+	})
+
+	issue, err := client.GetIssue(ctx, created.Key, []string{"components"})
+	require.NoError(t, err)
+	require.Len(t, issue.Fields.Components, 1)
+	assert.Equal(t, components[0].Name, issue.Fields.Components[0].Name)
+}
+
+func TestAuthType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		cfg      *config.Config
+		wantAuth string
+	}{
+		{
+			name:     "basic",
+			cfg:      &config.Config{JiraEmail: "me@example.com", JiraToken: "api-token"},
+			wantAuth: "Basic " + base64.StdEncoding.EncodeToString([]byte("me@example.com:api-token")),
+		},
+		{
+			name:     "pat",
+			cfg:      &config.Config{JiraAuthType: config.JiraAuthTypePAT, JiraToken: "personal-token"},
+			wantAuth: "Bearer personal-token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tt.wantAuth, r.Header.Get("Authorization"))
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"accountId":"abc"}`))
+			}))
+			t.Cleanup(srv.Close)
+
+			tt.cfg.JiraURL = srv.URL
+			client, err := NewClient(tt.cfg, zerolog.Nop())
+			require.NoError(t, err)
+			_, err = client.GetCurrentUser(t.Context())
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestGetComponents(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/3/project/PROJ/components", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id":"10000","name":"Backend","description":"APIs",` +
+			`"lead":{"accountId":"abc","displayName":"Ada"},"assigneeType":"COMPONENT_LEAD"}]`))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(&config.Config{JiraURL: srv.URL}, zerolog.Nop())
+	require.NoError(t, err)
+
+	components, err := client.GetComponents(t.Context(), "PROJ")
+	require.NoError(t, err)
+	assert.Equal(t, []Component{{
+		ID:           "10000",
+		Name:         "Backend",
+		Description:  "APIs",
+		Lead:         &User{AccountID: "abc", DisplayName: "Ada"},
+		AssigneeType: "COMPONENT_LEAD",
+	}}, components)
+
+	names, err := client.GetComponentNames(t.Context(), "PROJ")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Backend"}, names)
+}
+
+func TestGetVersions(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/3/project/PROJ/versions", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id":"10000","name":"2.1.0","description":"Spring release",` +
+			`"released":true,"releaseDate":"2026-04-01","archived":false},{"id":"10001","name":"2.2.0"}]`))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(&config.Config{JiraURL: srv.URL}, zerolog.Nop())
+	require.NoError(t, err)
+
+	versions, err := client.GetVersions(t.Context(), "PROJ")
+	require.NoError(t, err)
+	assert.Equal(t, []Version{
+		{ID: "10000", Name: "2.1.0", Description: "Spring release", Released: true, ReleaseDate: "2026-04-01"},
+		{ID: "10001", Name: "2.2.0"},
+	}, versions)
+}
+
+func TestGetWatchers(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/3/issue/PROJ-1/watchers", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"isWatching":false,"watchCount":2,` +
+			`"watchers":[{"accountId":"a1","displayName":"Alice"},{"accountId":"b2","displayName":"Bob"}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(&config.Config{JiraURL: srv.URL}, zerolog.Nop())
+	require.NoError(t, err)
+
+	watchers, err := client.GetWatchers(t.Context(), "PROJ-1")
+	require.NoError(t, err)
+	assert.Equal(t, &WatcherList{
+		WatchCount: 2,
+		Watchers:   []User{{AccountID: "a1", DisplayName: "Alice"}, {AccountID: "b2", DisplayName: "Bob"}},
+	}, watchers)
+}
+
+func TestSearchIssuesPaginated(t *testing.T) {
+	t.Parallel()
+
+	pages := map[string]SearchResponse{
+		"": {
+			Issues:        []Issue{{Key: "PROJ-1"}, {Key: "PROJ-2"}},
+			NextPageToken: "page-2",
+		},
+		"page-2": {
+			Issues:        []Issue{{Key: "PROJ-3"}, {Key: "PROJ-4"}},
+			NextPageToken: "page-3",
+		},
+		"page-3": {
+			Issues: []Issue{{Key: "PROJ-5"}},
+			IsLast: true,
+		},
+	}
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/search/jql", r.URL.Path)
+		assert.Equal(t, "project = PROJ", r.URL.Query().Get("jql"))
+		assert.Equal(t, "2", r.URL.Query().Get("maxResults"))
+		page, ok := pages[r.URL.Query().Get("nextPageToken")]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(page))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(&config.Config{JiraSearchPageSize: 2}, zerolog.Nop(), WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	issues, err := client.SearchIssuesPaginated(context.Background(), "project = PROJ", nil)
+	require.NoError(t, err)
+	keys := make([]string, len(issues))
+	for i, issue := range issues {
+		keys[i] = issue.Key
+	}
+	assert.Equal(t, []string{"PROJ-1", "PROJ-2", "PROJ-3", "PROJ-4", "PROJ-5"}, keys)
+	assert.Equal(t, 3, requests)
+}
+
+func TestRetryOnUnavailable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		maxRetries   int
+		statuses     []int
+		wantErr      string
+		wantRequests int
+	}{
+		{name: "recovers after 503", maxRetries: 2, statuses: []int{503, 200}, wantRequests: 2},
+		{name: "recovers after 504", maxRetries: 2, statuses: []int{504, 200}, wantRequests: 2},
+		{name: "gives up", maxRetries: 1, statuses: []int{503, 503, 200}, wantErr: "503 after 2 attempts", wantRequests: 2},
+		{name: "retries disabled", maxRetries: 0, statuses: []int{503, 200}, wantErr: "jira API error 503", wantRequests: 1},
+		{name: "no retry on 500", maxRetries: 2, statuses: []int{500, 200}, wantErr: "jira API error 500", wantRequests: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests int
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				status := tt.statuses[requests]
+				requests++
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(status)
+				_, _ = w.Write([]byte(`{"accountId":"abc123","displayName":"Jane Doe"}`))
+			}))
+			t.Cleanup(srv.Close)
+
+			client, err := NewClient(&config.Config{}, zerolog.Nop(), WithBaseURL(srv.URL), WithMaxRetries(tt.maxRetries))
+			require.NoError(t, err)
+
+			user, err := client.GetUserByAccountID(context.Background(), "abc123")
+			assert.Equal(t, tt.wantRequests, requests)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "Jane Doe", user.DisplayName)
+		})
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	timeout := 200 * time.Millisecond
+	client, err := NewClient(&config.Config{}, zerolog.Nop(), WithBaseURL(srv.URL), WithTimeout(timeout))
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = client.GetUserByAccountID(context.Background(), "abc123")
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), timeout+100*time.Millisecond)
+}
+
+func TestSearchIssuesPaginatedCancel(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(SearchResponse{
+			Issues:        []Issue{{Key: "PROJ-1"}},
+			NextPageToken: fmt.Sprintf("page-%d", requests+1),
+		}))
+		cancel()
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(&config.Config{}, zerolog.Nop(), WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	_, err = client.SearchIssuesPaginated(ctx, "project = PROJ", nil)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, requests)
+}
+
+func TestGetIssueNotFound(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errorMessages":["Issue does not exist or you do not have permission to see it."]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(&config.Config{}, zerolog.Nop(), WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	_, err = client.GetIssue(context.Background(), "PROJ-404", nil)
+	require.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorContains(t, err, "404")
+}
+
+func TestGetCurrentUser(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/myself", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"accountId":"abc123","displayName":"Jane Doe"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(&config.Config{}, zerolog.Nop(), WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	user, err := client.GetCurrentUser(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &User{AccountID: "abc123", DisplayName: "Jane Doe"}, user)
+}
+
+func TestUpdateIssuePartial(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/issue/PROJ-1", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t,
+			`{"fields":{"summary":"New","labels":["a"],"timetracking":{"originalEstimate":"2h"},"customfield_10016":3}}`,
+			string(body),
+		)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(&config.Config{}, zerolog.Nop(), WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	points := 3.0
+	err = client.UpdateIssue(context.Background(), "PROJ-1", UpdateFields{
+		Summary:      "New",
+		Labels:       []string{"a"},
+		TimeTracking: &TimeTracking{OriginalEstimate: "2h"},
+		StoryPoints:  &points,
+	})
+	require.NoError(t, err)
+}
+
+func TestGetIssuesForBoard(t *testing.T) {
+	t.Parallel()
+
+	all := []Issue{{Key: "PROJ-1"}, {Key: "PROJ-2"}, {Key: "PROJ-3"}, {Key: "PROJ-4"}, {Key: "PROJ-5"}}
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/rest/agile/1.0/board/7/issue", r.URL.Path)
+		assert.Equal(t, "project = PROJ", r.URL.Query().Get("jql"))
+		assert.Equal(t, "summary,status", r.URL.Query().Get("fields"))
+		startAt, err := strconv.Atoi(r.URL.Query().Get("startAt"))
+		assert.NoError(t, err)
+		end := min(startAt+2, len(all))
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(SearchResponse{
+			Issues:     all[startAt:end],
+			StartAt:    startAt,
+			MaxResults: 2,
+			Total:      len(all),
+		}))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(&config.Config{JiraURL: srv.URL, JiraSearchPageSize: 2}, zerolog.Nop())
+	require.NoError(t, err)
+
+	issues, err := client.GetIssuesForBoard(context.Background(), 7, "project = PROJ", []string{"summary", "status"})
+	require.NoError(t, err)
+	assert.Equal(t, all, issues)
+	assert.Equal(t, 3, requests)
+}
+
+func TestWithBaseURLs(t *testing.T) {
+	t.Parallel()
+
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(SearchResponse{IsLast: true}))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(&config.Config{JiraURL: "https://example.atlassian.net"}, zerolog.Nop(),
+		WithBaseURL(srv.URL+"/api"),
+		WithAgileBaseURL(srv.URL+"/agile"),
+		WithHTTPClient(srv.Client()),
+	)
+	require.NoError(t, err)
+
+	_, err = client.SearchIssuesPaginated(t.Context(), "project = PROJ", nil)
+	require.NoError(t, err)
+	_, err = client.GetIssuesForBoard(t.Context(), 7, "project = PROJ", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/api/search/jql", "/agile/board/7/issue"}, paths)
+}
+
+func TestGetAttachments(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/3/issue/PROJ-1", r.URL.Path)
+		assert.Equal(t, "attachment", r.URL.Query().Get("fields"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"key":"PROJ-1","fields":{"attachment":[{"id":"10000","filename":"spec.pdf",` +
+			`"mimeType":"application/pdf","size":2048,"content":"https://example.com/attachment/10000",` +
+			`"created":"2026-03-04T05:06:07.000+0000","author":{"accountId":"abc","displayName":"Ada"}}]}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(&config.Config{JiraURL: srv.URL}, zerolog.Nop())
+	require.NoError(t, err)
+
+	attachments, err := client.GetAttachments(t.Context(), "PROJ-1")
+	require.NoError(t, err)
+	assert.Equal(t, []Attachment{{
+		ID:       "10000",
+		Filename: "spec.pdf",
+		MimeType: "application/pdf",
+		Size:     2048,
+		Content:  "https://example.com/attachment/10000",
+		Created:  "2026-03-04T05:06:07.000+0000",
+		Author:   &User{AccountID: "abc", DisplayName: "Ada"},
+	}}, attachments)
+}
+
+func TestDownloadAttachment(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/attachment/content/10000" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("file contents"))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(&config.Config{JiraURL: srv.URL}, zerolog.Nop())
+	require.NoError(t, err)
+
+	body, err := client.DownloadAttachment(t.Context(), Attachment{
+		Filename: "spec.txt",
+		Content:  srv.URL + "/rest/api/3/attachment/content/10000",
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = body.Close() })
+	got, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, "file contents", string(got))
+
+	_, err = client.DownloadAttachment(t.Context(), Attachment{Filename: "gone.txt", Content: srv.URL + "/missing"})
+	require.Error(t, err)
+}
+
+func TestGetActiveSprint(t *testing.T) {
+	t.Parallel()
+
+	sprint1 := Sprint{ID: 1, Name: "Sprint 1", State: SprintStateActive, BoardID: 7}
+	sprint2 := Sprint{ID: 2, Name: "Sprint 2", State: SprintStateActive, BoardID: 7}
+	tests := []struct {
+		name    string
+		sprints []Sprint
+		want    *Sprint
+	}{
+		{name: "none", sprints: []Sprint{}},
+		{name: "one", sprints: []Sprint{sprint1}, want: &sprint1},
+		{name: "two", sprints: []Sprint{sprint1, sprint2}, want: &sprint1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/rest/agile/1.0/board/7/sprint", r.URL.Path)
+				assert.Equal(t, "active", r.URL.Query().Get("state"))
+				w.Header().Set("Content-Type", "application/json")
+				assert.NoError(t, json.NewEncoder(w).Encode(SprintsResponse{
+					MaxResults: 50,
+					IsLast:     true,
+					Values:     tt.sprints,
+				}))
+			}))
+			t.Cleanup(srv.Close)
+
+			client, err := NewClient(&config.Config{JiraURL: srv.URL}, zerolog.Nop())
+			require.NoError(t, err)
+
+			got, err := client.GetActiveSprint(t.Context(), 7)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetSprintsForBoardPaginated(t *testing.T) {
+	t.Parallel()
+
+	all := []Sprint{
+		{ID: 1, State: SprintStateClosed},
+		{ID: 2, State: SprintStateClosed},
+		{ID: 3, State: SprintStateActive},
+	}
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Empty(t, r.URL.Query().Get("state"))
+		startAt, err := strconv.Atoi(r.URL.Query().Get("startAt"))
+		assert.NoError(t, err)
+		end := min(startAt+2, len(all))
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(SprintsResponse{
+			MaxResults: 2,
+			StartAt:    startAt,
+			IsLast:     end == len(all),
+			Values:     all[startAt:end],
+		}))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(&config.Config{JiraURL: srv.URL}, zerolog.Nop())
+	require.NoError(t, err)
+
+	sprints, err := client.GetSprintsForBoard(t.Context(), 7, "")
+	require.NoError(t, err)
+	assert.Equal(t, all, sprints)
+	assert.Equal(t, 2, requests)
+}
@@ -0,0 +1,209 @@
+package jira
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// JQLBuilder builds a JQL query from clauses that are ANDed together, quoting
+// values where JQL needs it. Methods given no values add no clause, so
+// optional filters can be chained unconditionally:
+//
+//	jql := NewJQLBuilder().
+//		Project("PROJ").
+//		Assignee("currentUser()").
+//		IssueTypes("Story", "Bug").
+//		OrderBy("updated", "DESC").
+//		Build()
+type JQLBuilder struct {
+	clauses []string
+	orderBy []string
+}
+
+// NewJQLBuilder returns an empty JQLBuilder.
+func NewJQLBuilder() *JQLBuilder {
+	return &JQLBuilder{}
+}
+
+// Project restricts the query to a project key.
+func (b *JQLBuilder) Project(key string) *JQLBuilder {
+	if key = strings.TrimSpace(key); key == "" {
+		return b
+	}
+	return b.add("project = " + quoteJQL(key))
+}
+
+// Assignee restricts the query by assignee: "currentUser()" (or empty) for the
+// user whose credentials are used, "EMPTY" for unassigned issues, or an email
+// or account ID.
+func (b *JQLBuilder) Assignee(filter string) *JQLBuilder {
+	filter = strings.TrimSpace(filter)
+	switch {
+	case filter == "" || strings.EqualFold(filter, "currentUser()"):
+		return b.add("assignee = currentUser()")
+	case strings.EqualFold(filter, "EMPTY"):
+		return b.add("assignee is EMPTY")
+	default:
+		return b.add("assignee = " + quoteJQL(filter))
+	}
+}
+
+// Keys restricts the query to issues with any of the keys.
+func (b *JQLBuilder) Keys(keys ...string) *JQLBuilder {
+	return b.in("key", keys)
+}
+
+// IssueTypes restricts the query to issues of any of the types.
+func (b *JQLBuilder) IssueTypes(types ...string) *JQLBuilder {
+	return b.in("issuetype", types)
+}
+
+// Labels restricts the query to issues with any of the labels.
+func (b *JQLBuilder) Labels(labels ...string) *JQLBuilder {
+	return b.in("labels", labels)
+}
+
+// Components restricts the query to issues in any of the components.
+func (b *JQLBuilder) Components(comps ...string) *JQLBuilder {
+	return b.in("component", comps)
+}
+
+// FixVersions restricts the query to issues targeting any of the versions.
+func (b *JQLBuilder) FixVersions(vers ...string) *JQLBuilder {
+	return b.in("fixVersion", vers)
+}
+
+// UpdatedSince restricts the query to issues updated at or after t, to the
+// minute. Jira reads the time in the user's time zone, so t is formatted in
+// its own location. A zero t adds no clause.
+func (b *JQLBuilder) UpdatedSince(t time.Time) *JQLBuilder {
+	if t.IsZero() {
+		return b
+	}
+	return b.add(`updated >= "` + t.Format("2006-01-02 15:04") + `"`)
+}
+
+// sprintFunctions maps sprint states to the JQL functions listing those sprints.
+var sprintFunctions = map[string]string{
+	SprintStateActive: "openSprints()",
+	SprintStateFuture: "futureSprints()",
+	SprintStateClosed: "closedSprints()",
+}
+
+// SprintStates restricts the query to issues in a sprint with any of the
+// states: active, future, or closed. Unknown states are ignored.
+func (b *JQLBuilder) SprintStates(states ...string) *JQLBuilder {
+	var sprints []string
+	for _, state := range states {
+		fn, ok := sprintFunctions[strings.ToLower(strings.TrimSpace(state))]
+		if ok && !slices.Contains(sprints, "sprint in "+fn) {
+			sprints = append(sprints, "sprint in "+fn)
+		}
+	}
+	return b.add(orJQL(sprints))
+}
+
+// OrderBy adds a sort field. dir is ASC, DESC, or empty for Jira's default.
+// Fields are sorted by in the order they were added.
+func (b *JQLBuilder) OrderBy(field, dir string) *JQLBuilder {
+	if field = strings.TrimSpace(field); field == "" {
+		return b
+	}
+	order := quoteJQL(field)
+	if dir = strings.ToUpper(strings.TrimSpace(dir)); dir != "" {
+		order += " " + dir
+	}
+	b.orderBy = append(b.orderBy, order)
+	return b
+}
+
+// And adds the clauses of other. Its sort fields are ignored.
+func (b *JQLBuilder) And(other *JQLBuilder) *JQLBuilder {
+	b.clauses = append(b.clauses, other.clauses...)
+	return b
+}
+
+// Or replaces the clauses with a single clause matching either the current
+// clauses or those of other. other's sort fields are ignored.
+func (b *JQLBuilder) Or(other *JQLBuilder) *JQLBuilder {
+	switch {
+	case len(other.clauses) == 0:
+		return b
+	case len(b.clauses) == 0:
+		b.clauses = slices.Clone(other.clauses)
+		return b
+	}
+	b.clauses = []string{orJQL([]string{andJQL(b.clauses), andJQL(other.clauses)})}
+	return b
+}
+
+// Build returns the JQL query.
+func (b *JQLBuilder) Build() string {
+	jql := strings.Join(b.clauses, " AND ")
+	if len(b.orderBy) > 0 {
+		jql = strings.TrimSpace(jql + " ORDER BY " + strings.Join(b.orderBy, ", "))
+	}
+	return jql
+}
+
+func (b *JQLBuilder) add(clause string) *JQLBuilder {
+	if clause != "" {
+		b.clauses = append(b.clauses, clause)
+	}
+	return b
+}
+
+// in adds a `field IN (...)` clause for the non-blank values.
+func (b *JQLBuilder) in(field string, values []string) *JQLBuilder {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			quoted = append(quoted, quoteJQL(v))
+		}
+	}
+	if len(quoted) == 0 {
+		return b
+	}
+	return b.add(field + " IN (" + strings.Join(quoted, ", ") + ")")
+}
+
+// andJQL joins clauses with AND, in parentheses if there is more than one.
+func andJQL(clauses []string) string {
+	if len(clauses) == 1 {
+		return clauses[0]
+	}
+	return "(" + strings.Join(clauses, " AND ") + ")"
+}
+
+// orJQL joins clauses with OR, in parentheses if there is more than one.
+func orJQL(clauses []string) string {
+	switch len(clauses) {
+	case 0:
+		return ""
+	case 1:
+		return clauses[0]
+	}
+	return "(" + strings.Join(clauses, " OR ") + ")"
+}
+
+// unquotedJQL matches values that are safe to use in JQL without quotes.
+// Anything else, e.g. "-" or ".", may be read as an operator or function.
+var unquotedJQL = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// reservedJQL lists JQL keywords that must be quoted when used as values.
+var reservedJQL = []string{
+	"and", "or", "not", "empty", "null", "in", "is", "was", "changed", "order", "by", "asc", "desc",
+}
+
+// quoteJQL returns v as a JQL value, in double quotes with quotes and
+// backslashes escaped unless it's a plain word.
+func quoteJQL(v string) string {
+	if unquotedJQL.MatchString(v) && !slices.Contains(reservedJQL, strings.ToLower(v)) {
+		return v
+	}
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `"`, `\"`)
+	return `"` + v + `"`
+}
@@ -0,0 +1,156 @@
+package jira
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteJQL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "PROJ", want: "PROJ"},
+		{value: "Sub-task", want: `"Sub-task"`},
+		{value: "v2.1.0", want: `"v2.1.0"`},
+		{value: "PROJ-1", want: `"PROJ-1"`},
+		{value: "snake_case", want: "snake_case"},
+		{value: "Developer Experience", want: `"Developer Experience"`},
+		{value: "A,B", want: `"A,B"`},
+		{value: `Release "3"`, want: `"Release \"3\""`},
+		{value: `C:\temp`, want: `"C:\\temp"`},
+		{value: "automation@example.com", want: `"automation@example.com"`},
+		{value: "-leading-dash", want: `"-leading-dash"`},
+		{value: "(parens)", want: `"(parens)"`},
+		{value: "a=b", want: `"a=b"`},
+		{value: "Ünïcode", want: `"Ünïcode"`},
+		{value: "", want: `""`},
+		{value: "and", want: `"and"`},
+		{value: "EMPTY", want: `"EMPTY"`},
+		{value: "Order", want: `"Order"`},
+		{value: "android", want: "android"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, quoteJQL(tt.value))
+		})
+	}
+}
+
+func TestJQLBuilder(t *testing.T) {
+	t.Parallel()
+
+	since := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	tests := []struct {
+		name    string
+		builder *JQLBuilder
+		want    string
+	}{
+		{
+			name:    "empty",
+			builder: NewJQLBuilder(),
+			want:    "",
+		},
+		{
+			name: "sync query",
+			builder: NewJQLBuilder().
+				Project("DX").
+				Assignee("").
+				IssueTypes("Story", " Sub-task ").
+				Components("Developer Experience", "A,B").
+				FixVersions(`Release "3"`).
+				OrderBy("updated", "desc"),
+			want: `project = DX AND assignee = currentUser() AND issuetype IN (Story, "Sub-task") AND ` +
+				`component IN ("Developer Experience", "A,B") AND fixVersion IN ("Release \"3\"") ORDER BY updated DESC`,
+		},
+		{
+			name:    "empty values add no clause",
+			builder: NewJQLBuilder().Project(" ").IssueTypes().Labels("", " ").Components().FixVersions(),
+			want:    "",
+		},
+		{
+			name:    "unassigned",
+			builder: NewJQLBuilder().Assignee("empty"),
+			want:    "assignee is EMPTY",
+		},
+		{
+			name:    "assignee email",
+			builder: NewJQLBuilder().Assignee("automation@example.com"),
+			want:    `assignee = "automation@example.com"`,
+		},
+		{
+			name:    "labels",
+			builder: NewJQLBuilder().Labels("backend", "needs review"),
+			want:    `labels IN (backend, "needs review")`,
+		},
+		{
+			name:    "keys",
+			builder: NewJQLBuilder().Project("PROJ").Keys("PROJ-1"),
+			want:    `project = PROJ AND key IN ("PROJ-1")`,
+		},
+		{
+			name:    "updated since",
+			builder: NewJQLBuilder().UpdatedSince(since).UpdatedSince(time.Time{}),
+			want:    `updated >= "2026-03-04 05:06"`,
+		},
+		{
+			name:    "one sprint state",
+			builder: NewJQLBuilder().SprintStates("Active"),
+			want:    "sprint in openSprints()",
+		},
+		{
+			name:    "several sprint states",
+			builder: NewJQLBuilder().Project("DX").SprintStates("active", "future", "bogus", "active"),
+			want:    "project = DX AND (sprint in openSprints() OR sprint in futureSprints())",
+		},
+		{
+			name:    "unknown sprint states add no clause",
+			builder: NewJQLBuilder().SprintStates("bogus"),
+			want:    "",
+		},
+		{
+			name:    "order only",
+			builder: NewJQLBuilder().OrderBy("priority", "").OrderBy("created", "asc"),
+			want:    "ORDER BY priority, created ASC",
+		},
+		{
+			name:    "and",
+			builder: NewJQLBuilder().Project("DX").And(NewJQLBuilder().Labels("a").OrderBy("rank", "")),
+			want:    "project = DX AND labels IN (a)",
+		},
+		{
+			name: "or",
+			builder: NewJQLBuilder().Project("DX").Assignee("").
+				Or(NewJQLBuilder().Project("OPS").Assignee("EMPTY")).
+				OrderBy("updated", "DESC"),
+			want: "((project = DX AND assignee = currentUser()) OR (project = OPS AND assignee is EMPTY)) " +
+				"ORDER BY updated DESC",
+		},
+		{
+			name:    "or single clauses",
+			builder: NewJQLBuilder().Labels("a").Or(NewJQLBuilder().Labels("b")),
+			want:    "(labels IN (a) OR labels IN (b))",
+		},
+		{
+			name:    "or then and",
+			builder: NewJQLBuilder().Labels("a").Or(NewJQLBuilder().Labels("b")).And(NewJQLBuilder().Project("DX")),
+			want:    "(labels IN (a) OR labels IN (b)) AND project = DX",
+		},
+		{
+			name:    "or with empty",
+			builder: NewJQLBuilder().Or(NewJQLBuilder().Project("DX")).Or(NewJQLBuilder()),
+			want:    "project = DX",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.builder.Build())
+		})
+	}
+}
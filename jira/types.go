@@ -1,15 +1,27 @@
 // Package jira provides an HTTP client for the Jira Cloud REST API v3.
 package jira
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	// TimeFormat is the timestamp format Jira uses, e.g. 2026-03-01T10:15:30.000+0000.
+	TimeFormat = "2006-01-02T15:04:05.000-0700"
+	// DateFormat is the format of date-only fields like duedate.
+	DateFormat = "2006-01-02"
+)
 
 // SearchResponse is returned by the JQL search endpoint.
 type SearchResponse struct {
-	Issues     []Issue `json:"issues"`
-	MaxResults int     `json:"maxResults"`
-	StartAt    int     `json:"startAt"`
-	Total      int     `json:"total"`
-	IsLast     bool    `json:"isLast"`
+	Issues        []Issue `json:"issues"`
+	MaxResults    int     `json:"maxResults"`
+	StartAt       int     `json:"startAt"`
+	Total         int     `json:"total"`
+	IsLast        bool    `json:"isLast"`
+	NextPageToken string  `json:"nextPageToken"`
 }
 
 // Issue represents a Jira issue from the v3 API.
@@ -23,17 +35,145 @@ type Issue struct {
 // IssueFields holds the fields of a Jira issue.
 // Description and comment Body are ADF (Atlassian Document Format) JSON.
 type IssueFields struct {
-	Summary     string          `json:"summary,omitempty"`
-	Description json.RawMessage `json:"description,omitempty"`
-	Status      *Status         `json:"status,omitempty"`
-	Priority    *Priority       `json:"priority,omitempty"`
-	Resolution  *Resolution     `json:"resolution,omitempty"`
-	Updated     string          `json:"updated,omitempty"`
-	Duedate     string          `json:"duedate,omitempty"`
-	Comment     *CommentPage    `json:"comment,omitempty"`
-	Project     *Project        `json:"project,omitempty"`
-	IssueType   *IssueType      `json:"issuetype,omitempty"`
-	SprintRaw   json.RawMessage `json:"customfield_10020,omitempty"`
+	Summary      string          `json:"summary,omitempty"`
+	Description  json.RawMessage `json:"description,omitempty"`
+	Status       *Status         `json:"status,omitempty"`
+	Priority     *Priority       `json:"priority,omitempty"`
+	Resolution   *Resolution     `json:"resolution,omitempty"`
+	Updated      string          `json:"updated,omitempty"`
+	Duedate      string          `json:"duedate,omitempty"`
+	Comment      *CommentPage    `json:"comment,omitempty"`
+	Project      *Project        `json:"project,omitempty"`
+	IssueType    *IssueType      `json:"issuetype,omitempty"`
+	SprintRaw    json.RawMessage `json:"customfield_10020,omitempty"`
+	IssueLinks   []IssueLink     `json:"issuelinks,omitempty"`
+	Labels       []string        `json:"labels,omitempty"`
+	TimeTracking *TimeTracking   `json:"timetracking,omitempty"`
+	Attachments  []Attachment    `json:"attachment,omitempty"`
+	Components   []Component     `json:"components,omitempty"`
+	FixVersions  []Version       `json:"fixVersions,omitempty"`
+}
+
+// UpdatedTime parses the updated field, accepting Jira's own format or RFC 3339.
+// An empty field returns the zero time.
+func (f *IssueFields) UpdatedTime() (time.Time, error) {
+	return parseTime(f.Updated)
+}
+
+// DueDateParsed parses the duedate field. An empty field returns the zero time.
+func (f *IssueFields) DueDateParsed() (time.Time, error) {
+	if f.Duedate == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(DateFormat, f.Duedate)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse jira due date %q: %w", f.Duedate, err)
+	}
+	return t, nil
+}
+
+// parseTime parses a Jira timestamp in Jira's own format or RFC 3339.
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(TimeFormat, s)
+	if err == nil {
+		return t, nil
+	}
+	if t, rfcErr := time.Parse(time.RFC3339, s); rfcErr == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("parse jira time %q: %w", s, err)
+}
+
+// blocksLinkType is the name of Jira's built-in "blocks / is blocked by" link type.
+const blocksLinkType = "Blocks"
+
+// BlockedBy returns the keys of issues that block this one.
+func (f *IssueFields) BlockedBy() []string {
+	var keys []string
+	for _, link := range f.IssueLinks {
+		if link.Type.Name == blocksLinkType && link.InwardIssue != nil {
+			keys = append(keys, link.InwardIssue.Key)
+		}
+	}
+	return keys
+}
+
+// IssueLink is a relationship between two issues. Exactly one of InwardIssue
+// and OutwardIssue is set: with InwardIssue set, this issue is the target of
+// Type.Inward (e.g. "is blocked by" InwardIssue), with OutwardIssue set, it's
+// the source of Type.Outward (e.g. "blocks" OutwardIssue).
+type IssueLink struct {
+	ID           string        `json:"id,omitempty"`
+	Type         IssueLinkType `json:"type"`
+	InwardIssue  *LinkedIssue  `json:"inwardIssue,omitempty"`
+	OutwardIssue *LinkedIssue  `json:"outwardIssue,omitempty"`
+}
+
+// IssueLinkType describes a kind of issue link, e.g. Blocks.
+type IssueLinkType struct {
+	ID      string `json:"id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Inward  string `json:"inward,omitempty"`
+	Outward string `json:"outward,omitempty"`
+}
+
+// LinkedIssue is the abbreviated issue on the other end of an IssueLink.
+type LinkedIssue struct {
+	ID  string `json:"id,omitempty"`
+	Key string `json:"key"`
+}
+
+// Sprint states reported by Jira.
+const (
+	SprintStateActive = "active"
+	SprintStateFuture = "future"
+	SprintStateClosed = "closed"
+)
+
+// Sprint is a Jira Software sprint, as found in the sprint custom field (SprintInfoField).
+type Sprint struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	State     string     `json:"state"`
+	StartDate *time.Time `json:"startDate,omitempty"`
+	EndDate   *time.Time `json:"endDate,omitempty"`
+	Goal      string     `json:"goal,omitempty"`
+	BoardID   int        `json:"boardId,omitempty"`
+}
+
+// SprintsResponse is a page of sprints from the Jira Software board sprint endpoint.
+type SprintsResponse struct {
+	MaxResults int      `json:"maxResults"`
+	StartAt    int      `json:"startAt"`
+	IsLast     bool     `json:"isLast"`
+	Values     []Sprint `json:"values"`
+}
+
+// ParseSprintsFromRaw parses the sprint custom field of an issue.
+// An empty or null field returns no sprints.
+func ParseSprintsFromRaw(raw json.RawMessage) ([]Sprint, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var sprints []Sprint
+	if err := json.Unmarshal(raw, &sprints); err != nil {
+		return nil, fmt.Errorf("parse jira sprints: %w", err)
+	}
+	return sprints, nil
+}
+
+// TimeTracking holds an issue's time estimates, e.g. OriginalEstimate "1h 30m".
+// Jira fills in the *Seconds fields; only the string fields can be set.
+type TimeTracking struct {
+	OriginalEstimate         string `json:"originalEstimate,omitempty"`
+	RemainingEstimate        string `json:"remainingEstimate,omitempty"`
+	TimeSpent                string `json:"timeSpent,omitempty"`
+	OriginalEstimateSeconds  int    `json:"originalEstimateSeconds,omitempty"`
+	RemainingEstimateSeconds int    `json:"remainingEstimateSeconds,omitempty"`
+	TimeSpentSeconds         int    `json:"timeSpentSeconds,omitempty"`
 }
 
 // Status represents a Jira workflow status.
@@ -83,12 +223,65 @@ type Comment struct {
 	Updated string          `json:"updated,omitempty"`
 }
 
+// CreatedTime parses the created field. An empty field returns the zero time.
+func (c *Comment) CreatedTime() (time.Time, error) {
+	return parseTime(c.Created)
+}
+
+// UpdatedTime parses the updated field. An empty field returns the zero time.
+func (c *Comment) UpdatedTime() (time.Time, error) {
+	return parseTime(c.Updated)
+}
+
 // User represents a Jira user.
 type User struct {
 	AccountID   string `json:"accountId,omitempty"`
 	DisplayName string `json:"displayName,omitempty"`
 }
 
+// WatcherList is the set of users watching an issue.
+type WatcherList struct {
+	WatchCount int    `json:"watchCount"`
+	Watchers   []User `json:"watchers"`
+}
+
+// Attachment is a file attached to an issue.
+type Attachment struct {
+	ID       string `json:"id,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+	Size     int64  `json:"size,omitempty"`
+	Content  string `json:"content,omitempty"` // download URL
+	Created  string `json:"created,omitempty"`
+	Author   *User  `json:"author,omitempty"`
+}
+
+// Component is a Jira project component.
+type Component struct {
+	ID           string `json:"id,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Description  string `json:"description,omitempty"`
+	Lead         *User  `json:"lead,omitempty"`
+	AssigneeType string `json:"assigneeType,omitempty"` // e.g. PROJECT_DEFAULT, COMPONENT_LEAD
+}
+
+// Version is a Jira project version, used as an issue's fix version.
+type Version struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Released    bool   `json:"released,omitempty"`
+	ReleaseDate string `json:"releaseDate,omitempty"` // YYYY-MM-DD
+	Archived    bool   `json:"archived,omitempty"`
+}
+
+// IssueTypeStatuses lists the workflow statuses available to one issue type in a project.
+type IssueTypeStatuses struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Statuses []Status `json:"statuses"`
+}
+
 // Transition represents an available workflow transition.
 type Transition struct {
 	ID   string `json:"id"`
@@ -101,6 +294,23 @@ type TransitionsResponse struct {
 	Transitions []Transition `json:"transitions"`
 }
 
+// UpdateFields is a partial update of an issue's fields. Zero fields are left unchanged.
+type UpdateFields struct {
+	Summary      string          `json:"summary,omitempty"`
+	Description  json.RawMessage `json:"description,omitempty"` // ADF
+	DueDate      string          `json:"duedate,omitempty"`     // YYYY-MM-DD
+	Priority     *Priority       `json:"priority,omitempty"`
+	Labels       []string        `json:"labels,omitempty"` // replaces all labels
+	TimeTracking *TimeTracking   `json:"timetracking,omitempty"`
+	Assignee     *User           `json:"assignee,omitempty"`
+	StoryPoints  *float64        `json:"customfield_10016,omitempty"` // StoryPointsField
+}
+
+// updateIssueRequest is the payload for PUT /issue/{key}.
+type updateIssueRequest struct {
+	Fields UpdateFields `json:"fields"`
+}
+
 // CreateIssueResponse is the response from creating an issue.
 type CreateIssueResponse struct {
 	ID   string `json:"id"`
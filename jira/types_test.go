@@ -0,0 +1,146 @@
+package jira
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssueFieldsUpdatedTime(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		updated string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "jira format", updated: "2026-03-01T12:15:30.000+0200", want: time.Date(2026, 3, 1, 10, 15, 30, 0, time.UTC)},
+		{name: "rfc3339", updated: "2026-03-01T10:15:30Z", want: time.Date(2026, 3, 1, 10, 15, 30, 0, time.UTC)},
+		{name: "empty", updated: "", want: time.Time{}},
+		{name: "invalid", updated: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			f := &IssueFields{Updated: tt.updated}
+			got, err := f.UpdatedTime()
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+		})
+	}
+}
+
+func TestIssueFieldsDueDateParsed(t *testing.T) {
+	t.Parallel()
+
+	got, err := (&IssueFields{Duedate: "2026-12-25"}).DueDateParsed()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC), got)
+
+	got, err = (&IssueFields{}).DueDateParsed()
+	require.NoError(t, err)
+	assert.True(t, got.IsZero())
+
+	_, err = (&IssueFields{Duedate: "12/25/2026"}).DueDateParsed()
+	assert.Error(t, err)
+}
+
+func TestCommentTimes(t *testing.T) {
+	t.Parallel()
+
+	c := &Comment{Created: "2026-03-01T10:15:30.000+0000", Updated: "2026-03-02T10:15:30Z"}
+	created, err := c.CreatedTime()
+	require.NoError(t, err)
+	assert.True(t, time.Date(2026, 3, 1, 10, 15, 30, 0, time.UTC).Equal(created))
+	updated, err := c.UpdatedTime()
+	require.NoError(t, err)
+	assert.True(t, time.Date(2026, 3, 2, 10, 15, 30, 0, time.UTC).Equal(updated))
+
+	created, err = (&Comment{}).CreatedTime()
+	require.NoError(t, err)
+	assert.True(t, created.IsZero())
+
+	_, err = (&Comment{Updated: "bad"}).UpdatedTime()
+	assert.Error(t, err)
+}
+
+func TestIssueFieldsBlockedBy(t *testing.T) {
+	t.Parallel()
+
+	raw := `{
+		"issuelinks": [
+			{"type": {"name": "Blocks", "inward": "is blocked by", "outward": "blocks"}, "inwardIssue": {"key": "PROJ-1"}},
+			{"type": {"name": "Blocks", "inward": "is blocked by", "outward": "blocks"}, "outwardIssue": {"key": "PROJ-3"}},
+			{"type": {"name": "Relates", "inward": "relates to", "outward": "relates to"}, "inwardIssue": {"key": "PROJ-4"}},
+			{"type": {"name": "Blocks", "inward": "is blocked by", "outward": "blocks"}, "inwardIssue": {"key": "PROJ-5"}}
+		]
+	}`
+	var fields IssueFields
+	require.NoError(t, json.Unmarshal([]byte(raw), &fields))
+	assert.Equal(t, []string{"PROJ-1", "PROJ-5"}, fields.BlockedBy())
+	assert.Empty(t, (&IssueFields{}).BlockedBy())
+}
+
+func TestParseSprintsFromRaw(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		raw        string
+		want       []Sprint
+		wantActive bool
+		wantErr    bool
+	}{
+		{name: "empty"},
+		{name: "null", raw: "null"},
+		{
+			name: "sprints",
+			raw: `[
+				{"id": 1, "name": "Sprint 1", "state": "closed", "boardId": 7},
+				{"id": 2, "name": "Sprint 2", "state": "active", "boardId": 7, "goal": "Ship it",
+				 "startDate": "2025-01-06T09:00:00.000Z", "endDate": "2025-01-20T09:00:00.000Z"}
+			]`,
+			want: []Sprint{
+				{ID: 1, Name: "Sprint 1", State: SprintStateClosed, BoardID: 7},
+				{
+					ID: 2, Name: "Sprint 2", State: SprintStateActive, BoardID: 7, Goal: "Ship it",
+					StartDate: &start, EndDate: &end,
+				},
+			},
+			wantActive: true,
+		},
+		{
+			name: "closed only",
+			raw:  `[{"id": 1, "name": "Sprint 1", "state": "closed", "endDate": "2025-01-20T09:00:00.000Z"}]`,
+			want: []Sprint{{ID: 1, Name: "Sprint 1", State: SprintStateClosed, EndDate: &end}},
+		},
+		{name: "malformed", raw: `{"id": 1}`, wantErr: true},
+		{name: "malformed date", raw: `[{"id": 1, "startDate": "next week"}]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseSprintsFromRaw(json.RawMessage(tt.raw))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			issue := &Issue{Fields: &IssueFields{SprintRaw: json.RawMessage(tt.raw)}}
+			assert.Equal(t, tt.wantActive, InCurrentSprint(issue))
+		})
+	}
+}
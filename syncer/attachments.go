@@ -0,0 +1,119 @@
+package syncer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kalverra/todoist-jira-sync/jira"
+	"github.com/kalverra/todoist-jira-sync/todoist"
+)
+
+// attachmentCommentPrefix starts every Todoist comment linking a Jira attachment.
+const attachmentCommentPrefix = "📎 "
+
+// attachmentComment returns the Todoist comment linking a Jira attachment,
+// e.g. "📎 [spec.pdf](https://example.atlassian.net/rest/api/3/attachment/content/10000)".
+func attachmentComment(a jira.Attachment, jiraURL string) string {
+	return fmt.Sprintf("%s[%s](%s/rest/api/3/attachment/content/%s)",
+		attachmentCommentPrefix, a.Filename, strings.TrimRight(jiraURL, "/"), a.ID)
+}
+
+// attachmentFilename returns the name of the Jira attachment a Todoist comment
+// links or holds an upload of. It reports false for other comments, which
+// are synced back to Jira.
+func attachmentFilename(c todoist.Comment) (string, bool) {
+	if c.FileAttachment != nil && c.Content == c.FileAttachment.FileName {
+		return c.FileAttachment.FileName, true
+	}
+	link, ok := strings.CutPrefix(c.Content, attachmentCommentPrefix+"[")
+	if !ok {
+		return "", false
+	}
+	name, _, ok := strings.Cut(link, "](")
+	return name, ok
+}
+
+// newAttachments returns the attachments that no comment links to or holds
+// yet, matched by filename.
+func newAttachments(attachments []jira.Attachment, comments []todoist.Comment) []jira.Attachment {
+	linked := make(map[string]bool)
+	for _, c := range comments {
+		if name, ok := attachmentFilename(c); ok {
+			linked[name] = true
+		}
+	}
+	var added []jira.Attachment
+	for _, a := range attachments {
+		if !linked[a.Filename] {
+			added = append(added, a)
+			linked[a.Filename] = true
+		}
+	}
+	return added
+}
+
+// syncAttachmentsToTodoist comments a link to each of the issue's attachments
+// that the task doesn't have yet. With SyncAttachmentContent, files up to
+// MaxAttachmentBytes are uploaded to Todoist instead.
+func (e *Engine) syncAttachmentsToTodoist(ctx context.Context, issue *jira.Issue, todoistTaskID string) error {
+	attachments, err := e.jira.GetAttachments(ctx, issue.Key)
+	if err != nil {
+		return fmt.Errorf("get jira attachments: %w", err)
+	}
+	if len(attachments) == 0 {
+		return nil
+	}
+	comments, err := e.todoist.GetComments(ctx, todoistTaskID)
+	if err != nil {
+		return fmt.Errorf("get todoist comments: %w", err)
+	}
+
+	for _, a := range newAttachments(attachments, comments) {
+		if e.cfg.SyncAttachmentContent && a.Size <= e.cfg.MaxAttachmentBytes {
+			err := e.uploadAttachment(ctx, a, todoistTaskID)
+			if err == nil {
+				continue
+			}
+			e.logger.Warn().Err(err).
+				Str("task_id", todoistTaskID).
+				Str("issue_key", issue.Key).
+				Str("attachment", a.Filename).
+				Msg("failed to upload attachment to todoist, linking it instead")
+		}
+		_, err := e.todoist.CreateComment(ctx, todoist.CreateCommentRequest{
+			TaskID:  todoistTaskID,
+			Content: attachmentComment(a, e.cfg.JiraURL),
+		})
+		if err != nil {
+			e.logger.Error().Err(err).
+				Str("task_id", todoistTaskID).
+				Str("issue_key", issue.Key).
+				Str("attachment", a.Filename).
+				Msg("failed to add attachment link to todoist")
+		}
+	}
+	return nil
+}
+
+// uploadAttachment copies a Jira attachment's content to a Todoist comment.
+// The size Jira reports isn't trusted: content over MaxAttachmentBytes is an
+// error, and nothing is uploaded.
+func (e *Engine) uploadAttachment(ctx context.Context, a jira.Attachment, todoistTaskID string) error {
+	body, err := e.jira.DownloadAttachment(ctx, a)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = body.Close() }()
+	content, err := io.ReadAll(io.LimitReader(body, e.cfg.MaxAttachmentBytes+1))
+	if err != nil {
+		return fmt.Errorf("download attachment %q: %w", a.Filename, err)
+	}
+	if int64(len(content)) > e.cfg.MaxAttachmentBytes {
+		return fmt.Errorf("attachment %q is over %d bytes", a.Filename, e.cfg.MaxAttachmentBytes)
+	}
+	_, err = e.todoist.UploadFile(ctx, todoistTaskID, a.Filename, bytes.NewReader(content))
+	return err
+}
@@ -0,0 +1,157 @@
+package syncer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kalverra/todoist-jira-sync/config"
+	"github.com/kalverra/todoist-jira-sync/internal/testserver"
+	"github.com/kalverra/todoist-jira-sync/jira"
+	"github.com/kalverra/todoist-jira-sync/todoist"
+)
+
+func TestAttachmentComment(t *testing.T) {
+	t.Parallel()
+
+	got := attachmentComment(jira.Attachment{ID: "10000", Filename: "spec.pdf"}, "https://example.atlassian.net/")
+	assert.Equal(t, "📎 [spec.pdf](https://example.atlassian.net/rest/api/3/attachment/content/10000)", got)
+}
+
+func TestAttachmentFilename(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		comment todoist.Comment
+		want    string
+		wantOK  bool
+	}{
+		{
+			name:    "link",
+			comment: todoist.Comment{Content: "📎 [spec.pdf](https://example.atlassian.net/attachment/1)"},
+			want:    "spec.pdf",
+			wantOK:  true,
+		},
+		{
+			name: "upload",
+			comment: todoist.Comment{
+				Content:        "spec.pdf",
+				FileAttachment: &todoist.FileAttachment{FileName: "spec.pdf"},
+			},
+			want:   "spec.pdf",
+			wantOK: true,
+		},
+		{
+			name: "file with a message",
+			comment: todoist.Comment{
+				Content:        "here's the spec",
+				FileAttachment: &todoist.FileAttachment{FileName: "spec.pdf"},
+			},
+		},
+		{name: "no link", comment: todoist.Comment{Content: "📎 spec.pdf"}},
+		{name: "plain comment", comment: todoist.Comment{Content: "see [spec.pdf](https://example.com)"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := attachmentFilename(tt.comment)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantOK, ok)
+		})
+	}
+}
+
+func TestNewAttachments(t *testing.T) {
+	t.Parallel()
+
+	spec := jira.Attachment{ID: "1", Filename: "spec.pdf"}
+	logs := jira.Attachment{ID: "2", Filename: "logs.txt"}
+	screenshot := jira.Attachment{ID: "3", Filename: "screenshot.png"}
+	comments := []todoist.Comment{
+		{Content: attachmentComment(spec, "https://example.atlassian.net")},
+		{Content: "logs.txt is attached in Jira"},
+		{Content: "notes.md", FileAttachment: &todoist.FileAttachment{FileName: "notes.md"}},
+	}
+
+	tests := []struct {
+		name        string
+		attachments []jira.Attachment
+		comments    []todoist.Comment
+		want        []jira.Attachment
+	}{
+		{name: "none"},
+		{name: "no comments", attachments: []jira.Attachment{spec, logs}, want: []jira.Attachment{spec, logs}},
+		{
+			name:        "skips linked filenames",
+			attachments: []jira.Attachment{spec, logs, screenshot, {ID: "5", Filename: "notes.md"}},
+			comments:    comments,
+			want:        []jira.Attachment{logs, screenshot},
+		},
+		{
+			name:        "duplicate filenames",
+			attachments: []jira.Attachment{logs, {ID: "4", Filename: "logs.txt"}},
+			want:        []jira.Attachment{logs},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, newAttachments(tt.attachments, tt.comments))
+		})
+	}
+}
+
+func TestUploadAttachmentLimit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "within the limit", content: "12345678"},
+		{name: "over the limit", content: "123456789", wantErr: "is over 8 bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			files := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(tt.content))
+			}))
+			t.Cleanup(files.Close)
+			var uploads atomic.Int32
+			todoistSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/uploads" {
+					uploads.Add(1)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"id":"1","file_name":"spec.pdf"}`))
+			}))
+			t.Cleanup(todoistSrv.Close)
+
+			e := &Engine{
+				todoist: todoist.NewClient("token", zerolog.Nop(), todoist.WithBaseURL(todoistSrv.URL)),
+				jira:    testserver.JiraClient(t, testserver.NewJira(t)),
+				cfg:     &config.Config{MaxAttachmentBytes: 8},
+				logger:  zerolog.Nop(),
+			}
+			// Jira under-reports the size, so only the downloaded content is checked.
+			a := jira.Attachment{Filename: "spec.pdf", Size: 1, Content: files.URL + "/spec.pdf"}
+			err := e.uploadAttachment(t.Context(), a, "task-1")
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				assert.Zero(t, uploads.Load(), "nothing is uploaded")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int32(1), uploads.Load())
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/kalverra/todoist-jira-sync/config"
+	"github.com/kalverra/todoist-jira-sync/jira"
+	"github.com/kalverra/todoist-jira-sync/todoist"
+)
+
+// orphanLabel marks Todoist tasks whose linked Jira issue no longer exists.
+const orphanLabel = "jira-orphaned"
+
+// CleanupOrphanedTasks finds linked Todoist tasks whose Jira issue returns 404,
+// e.g. because it was deleted, and labels, closes, or deletes them according
+// to Config.OrphanAction. Tasks already labelled as orphaned are skipped.
+// With Config.ProjectPairs, every pair's Todoist project is cleaned up.
+// With dryRun, orphaned tasks are only logged.
+func (e *Engine) CleanupOrphanedTasks(ctx context.Context, dryRun bool) error {
+	e.dryRun = dryRun
+	defer func() { e.dryRun = false }()
+
+	for _, pe := range e.pairEngines() {
+		if err := pe.cleanupOrphanedTasks(ctx); err != nil {
+			return pe.pairError(err)
+		}
+	}
+	return nil
+}
+
+// cleanupOrphanedTasks cleans up the orphaned tasks in e.cfg's Todoist project.
+func (e *Engine) cleanupOrphanedTasks(ctx context.Context) error {
+	project, err := e.todoist.FindProjectByName(ctx, e.cfg.TodoistProject)
+	if err != nil {
+		return fmt.Errorf("find todoist project: %w", err)
+	}
+	tasks, err := e.todoist.GetTasks(ctx, project.ID)
+	if err != nil {
+		return fmt.Errorf("get todoist tasks: %w", err)
+	}
+
+	orphaned := 0
+	for _, task := range tasks {
+		jiraKey := ExtractJiraKey(task.Content)
+		if jiraKey == "" || !slices.Contains(task.Labels, linkLabel) || slices.Contains(task.Labels, orphanLabel) {
+			continue
+		}
+		_, err := e.jira.GetIssue(ctx, jiraKey, []string{"summary"})
+		if !errors.Is(err, jira.ErrNotFound) {
+			if err != nil {
+				return fmt.Errorf("get jira issue %s: %w", jiraKey, err)
+			}
+			continue
+		}
+
+		e.logger.Info().
+			Str("task_id", task.ID).
+			Str("issue_key", jiraKey).
+			Str("action", e.cfg.OrphanAction).
+			Bool("dry_run", e.dryRun).
+			Msg("todoist task linked to missing jira issue")
+		orphaned++
+		if e.dryRun {
+			continue
+		}
+		if err := e.cleanupOrphan(ctx, &task); err != nil {
+			return fmt.Errorf("clean up todoist task %s: %w", task.ID, err)
+		}
+	}
+
+	e.logger.Info().Int("count", orphaned).Msg("orphaned task cleanup complete")
+	return nil
+}
+
+// cleanupOrphan applies Config.OrphanAction to task.
+func (e *Engine) cleanupOrphan(ctx context.Context, task *todoist.Task) error {
+	switch e.cfg.OrphanAction {
+	case config.OrphanActionDelete:
+		return e.todoist.DeleteTask(ctx, task.ID)
+	case config.OrphanActionClose:
+		return e.todoist.CloseTask(ctx, task.ID)
+	default:
+		labels := append(slices.Clone(task.Labels), orphanLabel)
+		_, err := e.todoist.UpdateTask(ctx, task.ID, todoist.UpdateTaskRequest{Labels: labels})
+		return err
+	}
+}
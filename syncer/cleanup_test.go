@@ -0,0 +1,142 @@
+package syncer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kalverra/todoist-jira-sync/config"
+	"github.com/kalverra/todoist-jira-sync/internal/testserver"
+	"github.com/kalverra/todoist-jira-sync/jira"
+	"github.com/kalverra/todoist-jira-sync/todoist"
+)
+
+func TestCleanupOrphanedTasks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		action      string
+		wantExists  bool
+		wantChecked bool
+		wantLabels  []string
+	}{
+		{action: config.OrphanActionLabel, wantExists: true, wantLabels: []string{linkLabel, orphanLabel}},
+		{action: config.OrphanActionClose, wantExists: true, wantChecked: true, wantLabels: []string{linkLabel}},
+		{action: config.OrphanActionDelete},
+	}
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			t.Parallel()
+
+			todoistSrv := testserver.NewTodoist(t)
+			jiraSrv := testserver.NewJira(t)
+			cfg := &config.Config{TodoistProject: "Work", JiraURL: jiraSrv.URL, OrphanAction: tt.action}
+			e, err := NewEngine(
+				WithTodoistClient(testserver.TodoistClient(t, todoistSrv)),
+				WithJiraClient(testserver.JiraClient(t, jiraSrv)),
+				WithConfig(cfg),
+				WithStateStore(newMemoryStateStore()),
+			)
+			require.NoError(t, err)
+
+			project := todoistSrv.AddProject("Work")
+			issue := jiraSrv.AddIssue(jira.IssueFields{Project: &jira.Project{Key: "PROJ"}, Summary: "Still here"})
+			linked := todoistSrv.AddTask(todoist.Task{
+				ProjectID: project.ID,
+				Content:   PrependJiraLink("Still here", issue.Key, jiraSrv.URL),
+				Labels:    []string{linkLabel},
+			})
+			orphan := todoistSrv.AddTask(todoist.Task{
+				ProjectID: project.ID,
+				Content:   PrependJiraLink("Deleted", "PROJ-999", jiraSrv.URL),
+				Labels:    []string{linkLabel},
+			})
+
+			require.NoError(t, e.CleanupOrphanedTasks(t.Context(), false))
+
+			got, ok := todoistSrv.Task(linked.ID)
+			require.True(t, ok)
+			assert.Equal(t, []string{linkLabel}, got.Labels, "task with an existing issue is untouched")
+			assert.False(t, got.Checked)
+
+			got, ok = todoistSrv.Task(orphan.ID)
+			require.Equal(t, tt.wantExists, ok)
+			if ok {
+				assert.Equal(t, tt.wantChecked, got.Checked)
+				assert.Equal(t, tt.wantLabels, got.Labels)
+			}
+		})
+	}
+}
+
+func TestCleanupOrphanedTasksDryRun(t *testing.T) {
+	t.Parallel()
+
+	for _, action := range []string{config.OrphanActionLabel, config.OrphanActionClose, config.OrphanActionDelete} {
+		todoistSrv := testserver.NewTodoist(t)
+		jiraSrv := testserver.NewJira(t)
+		cfg := &config.Config{TodoistProject: "Work", JiraURL: jiraSrv.URL, OrphanAction: action}
+		e, err := NewEngine(
+			WithTodoistClient(testserver.TodoistClient(t, todoistSrv)),
+			WithJiraClient(testserver.JiraClient(t, jiraSrv)),
+			WithConfig(cfg),
+			WithStateStore(newMemoryStateStore()),
+		)
+		require.NoError(t, err)
+
+		project := todoistSrv.AddProject("Work")
+		orphan := todoistSrv.AddTask(todoist.Task{
+			ProjectID: project.ID,
+			Content:   PrependJiraLink("Deleted", "PROJ-999", jiraSrv.URL),
+			Labels:    []string{linkLabel},
+		})
+
+		require.NoError(t, e.CleanupOrphanedTasks(t.Context(), true))
+
+		got, ok := todoistSrv.Task(orphan.ID)
+		require.True(t, ok, action)
+		assert.Equal(t, orphan, got, "dry run doesn't %s the orphan", action)
+	}
+}
+
+func TestCleanupOrphanedTasksProjectPairs(t *testing.T) {
+	t.Parallel()
+
+	todoistSrv := testserver.NewTodoist(t)
+	jiraSrv := testserver.NewJira(t)
+	cfg := &config.Config{
+		JiraURL:      jiraSrv.URL,
+		OrphanAction: config.OrphanActionLabel,
+		ProjectPairs: []config.ProjectPair{
+			{TodoistProject: "Work", JiraProject: "PROJ"},
+			{TodoistProject: "Ops", JiraProject: "OPS"},
+		},
+	}
+	e, err := NewEngine(
+		WithTodoistClient(testserver.TodoistClient(t, todoistSrv)),
+		WithJiraClient(testserver.JiraClient(t, jiraSrv)),
+		WithConfig(cfg),
+		WithStateStore(newMemoryStateStore()),
+	)
+	require.NoError(t, err)
+
+	var orphans []string
+	for _, pair := range cfg.ProjectPairs {
+		project := todoistSrv.AddProject(pair.TodoistProject)
+		orphan := todoistSrv.AddTask(todoist.Task{
+			ProjectID: project.ID,
+			Content:   PrependJiraLink("Deleted", pair.JiraProject+"-999", jiraSrv.URL),
+			Labels:    []string{linkLabel},
+		})
+		orphans = append(orphans, orphan.ID)
+	}
+
+	require.NoError(t, e.CleanupOrphanedTasks(t.Context(), false))
+
+	for _, id := range orphans {
+		got, ok := todoistSrv.Task(id)
+		require.True(t, ok)
+		assert.Equal(t, []string{linkLabel, orphanLabel}, got.Labels, "orphan in every pair's project is labelled")
+	}
+}
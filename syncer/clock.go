@@ -0,0 +1,24 @@
+package syncer
+
+import "time"
+
+// Clock tells the Engine the current time, so tests can control it.
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock is the wall clock, used unless WithClock is given.
+type RealClock struct{}
+
+// Now implements Clock.
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock always returns the same time, for tests.
+type FixedClock time.Time
+
+// Now implements Clock.
+func (c FixedClock) Now() time.Time {
+	return time.Time(c)
+}
@@ -0,0 +1,127 @@
+package syncer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kalverra/todoist-jira-sync/jira"
+	"github.com/kalverra/todoist-jira-sync/todoist"
+)
+
+// Direction is the direction a linked task/issue pair should be synced in.
+type Direction int
+
+const (
+	// DirJiraToTodoist overwrites the Todoist task with the Jira issue.
+	DirJiraToTodoist Direction = iota
+	// DirTodoistToJira overwrites the Jira issue with the Todoist task.
+	DirTodoistToJira
+	// DirSkip leaves both sides untouched for this cycle.
+	DirSkip
+)
+
+// String returns a human-readable name for the direction.
+func (d Direction) String() string {
+	switch d {
+	case DirJiraToTodoist:
+		return "jira -> todoist"
+	case DirTodoistToJira:
+		return "todoist -> jira"
+	case DirSkip:
+		return "skip"
+	}
+	return fmt.Sprintf("Direction(%d)", int(d))
+}
+
+// ConflictResolver decides which side of a linked pair wins when syncing.
+// syncedAt is the start of the previous sync cycle, or the zero time if this
+// is the first cycle.
+type ConflictResolver interface {
+	Resolve(task *todoist.Task, issue *jira.Issue, syncedAt time.Time) Direction
+}
+
+// JiraWinsResolver always syncs Jira -> Todoist.
+type JiraWinsResolver struct{}
+
+// Resolve implements ConflictResolver.
+func (JiraWinsResolver) Resolve(*todoist.Task, *jira.Issue, time.Time) Direction {
+	return DirJiraToTodoist
+}
+
+// TodoistWinsResolver always syncs Todoist -> Jira.
+type TodoistWinsResolver struct{}
+
+// Resolve implements ConflictResolver.
+func (TodoistWinsResolver) Resolve(*todoist.Task, *jira.Issue, time.Time) Direction {
+	return DirTodoistToJira
+}
+
+// NewerWinsResolver syncs from whichever side was updated most recently.
+// Todoist wins ties and cases where its timestamp can't be parsed.
+type NewerWinsResolver struct{}
+
+// Resolve implements ConflictResolver.
+func (NewerWinsResolver) Resolve(task *todoist.Task, issue *jira.Issue, _ time.Time) Direction {
+	todoistUpdated, err := task.UpdatedAtTime()
+	if err != nil || todoistUpdated.IsZero() {
+		return DirTodoistToJira
+	}
+	jiraUpdated, _ := issue.Fields.UpdatedTime()
+	if jiraUpdated.After(todoistUpdated) {
+		return DirJiraToTodoist
+	}
+	return DirTodoistToJira
+}
+
+// InteractiveResolver asks the user which side wins for every linked pair
+// that changed since the last sync. Pairs that haven't changed on either side
+// fall back to NewerWinsResolver without prompting. It's safe for concurrent
+// use, e.g. with several project pairs syncing at once; prompts are asked one
+// at a time.
+type InteractiveResolver struct {
+	mu  sync.Mutex
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// NewInteractiveResolver creates an InteractiveResolver that reads answers
+// from in and writes prompts to out, typically os.Stdin and os.Stdout.
+func NewInteractiveResolver(in io.Reader, out io.Writer) *InteractiveResolver {
+	return &InteractiveResolver{in: bufio.NewReader(in), out: out}
+}
+
+// Resolve implements ConflictResolver.
+func (r *InteractiveResolver) Resolve(task *todoist.Task, issue *jira.Issue, syncedAt time.Time) Direction {
+	if !syncedAt.IsZero() {
+		todoistUpdated, _ := task.UpdatedAtTime()
+		jiraUpdated, _ := issue.Fields.UpdatedTime()
+		if !todoistUpdated.After(syncedAt) && !jiraUpdated.After(syncedAt) {
+			return NewerWinsResolver{}.Resolve(task, issue, syncedAt)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for {
+		fmt.Fprintf(r.out,
+			"\n[%s] %s\n  Jira updated:    %s\n  Todoist updated: %s\nSync from [j]ira, [t]odoist, or [s]kip? ",
+			issue.Key, issue.Fields.Summary, issue.Fields.Updated, task.UpdatedAt,
+		)
+		line, err := r.in.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "j", "jira":
+			return DirJiraToTodoist
+		case "t", "todoist":
+			return DirTodoistToJira
+		case "s", "skip":
+			return DirSkip
+		}
+		if err != nil {
+			return DirSkip
+		}
+	}
+}
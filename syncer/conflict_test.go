@@ -0,0 +1,122 @@
+package syncer
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kalverra/todoist-jira-sync/jira"
+	"github.com/kalverra/todoist-jira-sync/todoist"
+)
+
+func TestNewerWinsResolver(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		todoistUpdated string
+		jiraUpdated    string
+		want           Direction
+	}{
+		{
+			name:           "jira newer",
+			todoistUpdated: "2026-01-01T10:00:00Z",
+			jiraUpdated:    "2026-01-01T11:00:00.000+0000",
+			want:           DirJiraToTodoist,
+		},
+		{
+			name:           "todoist newer",
+			todoistUpdated: "2026-01-01T12:00:00Z",
+			jiraUpdated:    "2026-01-01T11:00:00.000+0000",
+			want:           DirTodoistToJira,
+		},
+		{
+			name:           "same time favors todoist",
+			todoistUpdated: "2026-01-01T11:00:00Z",
+			jiraUpdated:    "2026-01-01T11:00:00Z",
+			want:           DirTodoistToJira,
+		},
+		{
+			name:           "unparseable todoist timestamp",
+			todoistUpdated: "garbage",
+			jiraUpdated:    "2026-01-01T11:00:00.000+0000",
+			want:           DirTodoistToJira,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			task := &todoist.Task{UpdatedAt: tt.todoistUpdated}
+			issue := &jira.Issue{Fields: &jira.IssueFields{Updated: tt.jiraUpdated}}
+			got := NewerWinsResolver{}.Resolve(task, issue, time.Time{})
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFixedResolvers(t *testing.T) {
+	t.Parallel()
+
+	task := &todoist.Task{}
+	issue := &jira.Issue{Fields: &jira.IssueFields{}}
+	assert.Equal(t, DirJiraToTodoist, JiraWinsResolver{}.Resolve(task, issue, time.Time{}))
+	assert.Equal(t, DirTodoistToJira, TodoistWinsResolver{}.Resolve(task, issue, time.Time{}))
+}
+
+func TestInteractiveResolver(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  Direction
+	}{
+		{name: "jira", input: "j\n", want: DirJiraToTodoist},
+		{name: "todoist", input: "todoist\n", want: DirTodoistToJira},
+		{name: "skip", input: "s\n", want: DirSkip},
+		{name: "reprompts on invalid answer", input: "x\nJ\n", want: DirJiraToTodoist},
+		{name: "eof skips", input: "", want: DirSkip},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			r := NewInteractiveResolver(strings.NewReader(tt.input), &out)
+			task := &todoist.Task{UpdatedAt: "2026-01-01T10:00:00Z"}
+			issue := &jira.Issue{Key: "PROJ-1", Fields: &jira.IssueFields{Summary: "A task"}}
+			got := r.Resolve(task, issue, time.Time{})
+			assert.Equal(t, tt.want, got)
+			assert.Contains(t, out.String(), "[PROJ-1] A task")
+		})
+	}
+}
+
+func TestInteractiveResolverConcurrent(t *testing.T) {
+	t.Parallel()
+
+	const pairs = 8
+	var out bytes.Buffer
+	r := NewInteractiveResolver(strings.NewReader(strings.Repeat("j\n", pairs)), &out)
+	got := make([]Direction, pairs)
+	var wg sync.WaitGroup
+	for i := range pairs {
+		wg.Go(func() {
+			task := &todoist.Task{UpdatedAt: "2026-01-01T10:00:00Z"}
+			issue := &jira.Issue{Key: fmt.Sprintf("PROJ-%d", i), Fields: &jira.IssueFields{Summary: "A task"}}
+			got[i] = r.Resolve(task, issue, time.Time{})
+		})
+	}
+	wg.Wait()
+
+	for i, dir := range got {
+		assert.Equal(t, DirJiraToTodoist, dir, "each prompt reads its own answer")
+		assert.Contains(t, out.String(), fmt.Sprintf("[PROJ-%d] A task", i))
+	}
+}
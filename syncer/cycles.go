@@ -0,0 +1,42 @@
+package syncer
+
+import (
+	"slices"
+
+	"github.com/kalverra/todoist-jira-sync/todoist"
+)
+
+// DetectCycles returns the IDs of tasks whose ParentID chain loops back to
+// themselves, sorted. Todoist's UI prevents such cycles, but the API doesn't.
+// Tasks whose chain leads into a cycle without being part of it aren't returned.
+func DetectCycles(tasks []todoist.Task) []string {
+	parents := make(map[string]string, len(tasks))
+	for _, task := range tasks {
+		parents[task.ID] = task.ParentID
+	}
+
+	const (
+		unvisited = iota
+		onPath
+		done
+	)
+	state := make(map[string]int, len(tasks))
+	var cyclic []string
+	for _, task := range tasks {
+		var path []string
+		id := task.ID
+		for id != "" && state[id] == unvisited {
+			state[id] = onPath
+			path = append(path, id)
+			id = parents[id]
+		}
+		if id != "" && state[id] == onPath {
+			cyclic = append(cyclic, path[slices.Index(path, id):]...)
+		}
+		for _, visited := range path {
+			state[visited] = done
+		}
+	}
+	slices.Sort(cyclic)
+	return cyclic
+}
@@ -0,0 +1,63 @@
+package syncer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kalverra/todoist-jira-sync/todoist"
+)
+
+func TestDetectCycles(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		tasks []todoist.Task
+		want  []string
+	}{
+		{name: "no tasks"},
+		{
+			name: "no cycles",
+			tasks: []todoist.Task{
+				{ID: "1"},
+				{ID: "2", ParentID: "1"},
+				{ID: "3", ParentID: "2"},
+				{ID: "4", ParentID: "missing"},
+			},
+		},
+		{
+			name: "three task cycle",
+			tasks: []todoist.Task{
+				{ID: "1", ParentID: "3"},
+				{ID: "2", ParentID: "1"},
+				{ID: "3", ParentID: "2"},
+				{ID: "4"},
+			},
+			want: []string{"1", "2", "3"},
+		},
+		{
+			name: "chain into a cycle",
+			tasks: []todoist.Task{
+				{ID: "1", ParentID: "2"},
+				{ID: "2", ParentID: "3"},
+				{ID: "3", ParentID: "2"},
+			},
+			want: []string{"2", "3"},
+		},
+		{
+			name: "own parent",
+			tasks: []todoist.Task{
+				{ID: "1", ParentID: "1"},
+				{ID: "2", ParentID: "1"},
+			},
+			want: []string{"1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, DetectCycles(tt.tasks))
+		})
+	}
+}
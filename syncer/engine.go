@@ -2,9 +2,16 @@ package syncer
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"os"
 	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -15,72 +22,241 @@ import (
 	"github.com/kalverra/todoist-jira-sync/todoist"
 )
 
-const (
-	commentFromJiraPrefix = "`[From Jira %s]`" // %s is the Jira issue key
-	defaultIssueType      = "Story"
-	linkLabel             = "jira-sync"
-)
+const linkLabel = "jira-sync"
 
 // Engine orchestrates bidirectional sync between Todoist and Jira.
 type Engine struct {
-	todoist *todoist.Client
-	jira    *jira.Client
-	cfg     *config.Config
-	logger  zerolog.Logger
+	todoist  *todoist.Client
+	jira     *jira.Client
+	cfg      *config.Config
+	logger   zerolog.Logger
+	resolver ConflictResolver
+	events   SyncEventHandler
+	state    StateStore
+	clock    Clock
+	lastSync time.Time
+	dryRun   bool
+
+	retryQueue *retryQueue
+
+	summaryMu   sync.RWMutex
+	lastSummary SyncSummary
+	summaryOut  io.Writer // where the text summary is printed
+
+	userNames    map[string]string // Jira account ID -> display name, reset every cycle
+	todoistUsers todoistUsers      // reset every cycle
+	pair         string            // project pair of an engine from forPair, tags its retry items
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// EngineOption configures an Engine.
+type EngineOption func(*Engine)
+
+// WithTodoistClient sets the Todoist client. Required.
+func WithTodoistClient(c *todoist.Client) EngineOption {
+	return func(e *Engine) {
+		e.todoist = c
+	}
+}
+
+// WithJiraClient sets the Jira client. Required.
+func WithJiraClient(c *jira.Client) EngineOption {
+	return func(e *Engine) {
+		e.jira = c
+	}
+}
+
+// WithConfig sets the sync config. Required.
+func WithConfig(cfg *config.Config) EngineOption {
+	return func(e *Engine) {
+		e.cfg = cfg
+	}
+}
+
+// WithLogger sets the logger. Defaults to a no-op logger.
+func WithLogger(l zerolog.Logger) EngineOption {
+	return func(e *Engine) {
+		e.logger = l
+	}
+}
+
+// WithConflictResolver sets the strategy used to pick a sync direction for
+// linked pairs. Defaults to NewerWinsResolver.
+func WithConflictResolver(cr ConflictResolver) EngineOption {
+	return func(e *Engine) {
+		e.resolver = cr
+	}
+}
+
+// WithEventHandler sets the handler notified of sync events. Defaults to NopEventHandler.
+func WithEventHandler(h SyncEventHandler) EngineOption {
+	return func(e *Engine) {
+		e.events = h
+	}
+}
+
+// WithStateStore sets where sync state is persisted between cycles.
+// Defaults to an in-memory store.
+func WithStateStore(store StateStore) EngineOption {
+	return func(e *Engine) {
+		e.state = store
+	}
+}
+
+// WithClock sets the source of the current time. Defaults to the wall clock.
+func WithClock(c Clock) EngineOption {
+	return func(e *Engine) {
+		e.clock = c
+	}
 }
 
 // NewEngine creates a new sync engine.
-func NewEngine(
-	todoistClient *todoist.Client,
-	jiraClient *jira.Client,
-	cfg *config.Config,
-	logger zerolog.Logger,
-) *Engine {
-	return &Engine{
-		todoist: todoistClient,
-		jira:    jiraClient,
-		cfg:     cfg,
-		logger:  logger.With().Str("component", "syncer").Logger(),
+// WithTodoistClient, WithJiraClient, and WithConfig are required.
+func NewEngine(opts ...EngineOption) (*Engine, error) {
+	e := &Engine{
+		logger:   zerolog.Nop(),
+		resolver: NewerWinsResolver{},
+		events:   NopEventHandler{},
+		state:    newMemoryStateStore(),
+		clock:    RealClock{},
+
+		summaryOut: os.Stdout,
+	}
+	for _, opt := range opts {
+		opt(e)
 	}
+	switch {
+	case e.todoist == nil:
+		return nil, errors.New("todoist client is required")
+	case e.jira == nil:
+		return nil, errors.New("jira client is required")
+	case e.cfg == nil:
+		return nil, errors.New("config is required")
+	}
+	e.logger = e.logger.With().Str("component", "syncer").Logger()
+	e.retryQueue = &retryQueue{store: e.state, logger: e.logger}
+	return e, nil
 }
 
-type sectionMap struct {
+// SetSummaryOutput directs the text summary printed after every sync cycle to w
+// instead of os.Stdout. It must not be called during a cycle.
+func (e *Engine) SetSummaryOutput(w io.Writer) {
+	e.summaryOut = w
+}
+
+// SectionMap looks up Todoist sections of a project by ID or name.
+type SectionMap struct {
 	byID   map[string]string
 	byName map[string]string
 }
 
-type syncAction struct {
-	jiraKey string
-	summary string
+// Name returns the name of the section with the given ID.
+func (sm SectionMap) Name(id string) (string, bool) {
+	name, ok := sm.byID[id]
+	return name, ok
+}
+
+// ID returns the ID of the section with the given name.
+func (sm SectionMap) ID(name string) (string, bool) {
+	id, ok := sm.byName[name]
+	return id, ok
+}
+
+// SyncAction is a single change made, or attempted, by a sync cycle.
+type SyncAction struct {
+	JiraKey     string   `json:"jira_key,omitempty"` // empty if the Jira issue doesn't exist yet
+	Summary     string   `json:"summary"`
+	Changed     []string `json:"changed_fields,omitempty"` // fields changed by an update
+	Diff        string   `json:"-"`                        // readable list of the changed fields, for the text summary
+	ProjectPair string   `json:"project_pair,omitempty"`   // "todoist project/jira project", set with Config.ProjectPairs
+}
+
+// SyncSummary lists the changes made by a sync cycle.
+type SyncSummary struct {
+	StartedAt        time.Time    `json:"started_at"`
+	FinishedAt       time.Time    `json:"finished_at"`
+	CreatedJira      []SyncAction `json:"created_jira"`
+	CreatedTodoist   []SyncAction `json:"created_todoist"`
+	UpdatedToTodoist []SyncAction `json:"updated_to_todoist"`
+	UpdatedToJira    []SyncAction `json:"updated_to_jira"`
+	CompletedTodoist []SyncAction `json:"completed_todoist"`
+	ResolvedJira     []SyncAction `json:"resolved_jira"`
+	Errors           []SyncAction `json:"errors"`
+	Skipped          []SyncAction `json:"skipped"` // linked pairs that needed no changes, only filled in verbose mode
+	DryRun           bool         `json:"dry_run"` // the changes were only previewed
+}
+
+// recordSkipped adds a linked pair that needed no changes to the summary, in verbose mode.
+func (e *Engine) recordSkipped(s *SyncSummary, action SyncAction) {
+	if e.cfg.Verbose {
+		s.Skipped = append(s.Skipped, action)
+	}
 }
 
-type syncSummary struct {
-	createdJira      []syncAction
-	createdTodoist   []syncAction
-	updatedToTodoist []syncAction
-	updatedToJira    []syncAction
-	completedTodoist []syncAction
-	resolvedJira     []syncAction
-	errors           []syncAction
+// add appends the actions of other to s.
+func (s *SyncSummary) add(other SyncSummary) {
+	s.CreatedJira = append(s.CreatedJira, other.CreatedJira...)
+	s.CreatedTodoist = append(s.CreatedTodoist, other.CreatedTodoist...)
+	s.UpdatedToTodoist = append(s.UpdatedToTodoist, other.UpdatedToTodoist...)
+	s.UpdatedToJira = append(s.UpdatedToJira, other.UpdatedToJira...)
+	s.CompletedTodoist = append(s.CompletedTodoist, other.CompletedTodoist...)
+	s.ResolvedJira = append(s.ResolvedJira, other.ResolvedJira...)
+	s.Errors = append(s.Errors, other.Errors...)
+	s.Skipped = append(s.Skipped, other.Skipped...)
 }
 
-func (s *syncSummary) print(duration time.Duration) {
+// setProjectPair labels every action in s with a project pair.
+func (s *SyncSummary) setProjectPair(name string) {
+	for _, actions := range [][]SyncAction{
+		s.CreatedJira, s.CreatedTodoist, s.UpdatedToTodoist, s.UpdatedToJira,
+		s.CompletedTodoist, s.ResolvedJira, s.Errors, s.Skipped,
+	} {
+		for i := range actions {
+			actions[i].ProjectPair = name
+		}
+	}
+}
+
+// clone returns a deep copy of s.
+func (s *SyncSummary) clone() SyncSummary {
+	return SyncSummary{
+		StartedAt:        s.StartedAt,
+		FinishedAt:       s.FinishedAt,
+		CreatedJira:      slices.Clone(s.CreatedJira),
+		CreatedTodoist:   slices.Clone(s.CreatedTodoist),
+		UpdatedToTodoist: slices.Clone(s.UpdatedToTodoist),
+		UpdatedToJira:    slices.Clone(s.UpdatedToJira),
+		CompletedTodoist: slices.Clone(s.CompletedTodoist),
+		ResolvedJira:     slices.Clone(s.ResolvedJira),
+		Errors:           slices.Clone(s.Errors),
+		Skipped:          slices.Clone(s.Skipped),
+		DryRun:           s.DryRun,
+	}
+}
+
+func (s *SyncSummary) print(w io.Writer) {
 	var b strings.Builder
 	b.WriteString("\n================================\n")
-	b.WriteString("  Sync Summary\n")
+	if s.DryRun {
+		b.WriteString("  Sync Summary (dry run)\n")
+	} else {
+		b.WriteString("  Sync Summary\n")
+	}
 	b.WriteString("================================\n")
 
 	sections := []struct {
 		label   string
-		actions []syncAction
+		actions []SyncAction
 	}{
-		{"Created in Jira", s.createdJira},
-		{"Created in Todoist", s.createdTodoist},
-		{"Updated Jira -> Todoist", s.updatedToTodoist},
-		{"Updated Todoist -> Jira", s.updatedToJira},
-		{"Completed in Todoist", s.completedTodoist},
-		{"Resolved in Jira", s.resolvedJira},
-		{"Errors", s.errors},
+		{"Created in Jira", s.CreatedJira},
+		{"Created in Todoist", s.CreatedTodoist},
+		{"Updated Jira -> Todoist", s.UpdatedToTodoist},
+		{"Updated Todoist -> Jira", s.UpdatedToJira},
+		{"Completed in Todoist", s.CompletedTodoist},
+		{"Resolved in Jira", s.ResolvedJira},
+		{"Errors", s.Errors},
 	}
 
 	anyActivity := false
@@ -89,12 +265,21 @@ func (s *syncSummary) print(duration time.Duration) {
 			continue
 		}
 		anyActivity = true
-		fmt.Fprintf(&b, "\n%s (%d):\n", sec.label, len(sec.actions))
-		for _, a := range sec.actions {
-			if a.jiraKey != "" {
-				fmt.Fprintf(&b, "  - [%s] %s\n", a.jiraKey, a.summary)
-			} else {
-				fmt.Fprintf(&b, "  - %s\n", a.summary)
+		for _, group := range groupByProjectPair(sec.actions) {
+			label := sec.label
+			if pair := group[0].ProjectPair; pair != "" {
+				label += " [" + pair + "]"
+			}
+			fmt.Fprintf(&b, "\n%s (%d):\n", label, len(group))
+			for _, a := range group {
+				line := a.Summary
+				if a.JiraKey != "" {
+					line = "[" + a.JiraKey + "] " + line
+				}
+				if a.Diff != "" {
+					line += " (changed: " + a.Diff + ")"
+				}
+				fmt.Fprintf(&b, "  - %s\n", line)
 			}
 		}
 	}
@@ -103,9 +288,29 @@ func (s *syncSummary) print(duration time.Duration) {
 		b.WriteString("\nEverything is up to date.\n")
 	}
 
-	fmt.Fprintf(&b, "\nCompleted in %s\n", duration.Truncate(time.Millisecond))
+	if len(s.Skipped) > 0 {
+		fmt.Fprintf(&b, "\nSkipped (no changes) (%d):\n", len(s.Skipped))
+		for _, a := range s.Skipped {
+			fmt.Fprintf(&b, "  - [%s] %s\n", a.JiraKey, a.Summary)
+		}
+	}
+
+	fmt.Fprintf(&b, "\nCompleted in %s\n", s.FinishedAt.Sub(s.StartedAt).Truncate(time.Millisecond))
 	b.WriteString("================================\n")
-	fmt.Print(b.String())
+	_, _ = io.WriteString(w, b.String())
+}
+
+// groupByProjectPair splits actions into runs with the same project pair,
+// in order. Summaries add each pair's actions together, so each pair gets one run.
+func groupByProjectPair(actions []SyncAction) [][]SyncAction {
+	var groups [][]SyncAction
+	for i, a := range actions {
+		if i == 0 || a.ProjectPair != actions[i-1].ProjectPair {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], a)
+	}
+	return groups
 }
 
 var searchFields = []string{
@@ -117,26 +322,111 @@ var searchFields = []string{
 	"comment",
 	"priority",
 	"resolution",
+	"issuelinks",
+	"labels",
+	"components",
+	"fixVersions",
+	"timetracking",
 	jira.SprintInfoField,
 	jira.EpicLinkField,
 }
 
-// Run executes a single sync cycle.
+// DryRun executes a sync cycle that only reads from Todoist and Jira,
+// printing the summary of what Run would change.
+func (e *Engine) DryRun(ctx context.Context) error {
+	e.dryRun = true
+	defer func() { e.dryRun = false }()
+	return e.Run(ctx)
+}
+
+// RunN executes n sync cycles back to back, stopping early if ctx is
+// cancelled. A failed cycle doesn't stop the ones after it; their errors are
+// joined.
+func (e *Engine) RunN(ctx context.Context, n int) error {
+	var errs []error
+	for cycle := range n {
+		if err := ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		if err := e.Run(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("sync cycle %d: %w", cycle+1, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run executes a single sync cycle for every project pair, syncing up to
+// Config.SyncConcurrency pairs at once. A pair that fails doesn't stop the
+// others; the summary covers the pairs that synced.
 func (e *Engine) Run(ctx context.Context) error {
-	start := time.Now()
+	start := e.clock.Now()
 	e.logger.Info().Msg("syncing todoist and jira")
 
+	pairs := e.cfg.ProjectPairs
+	if len(pairs) == 0 {
+		pairs = []config.ProjectPair{{}}
+	}
+	summaries := make([]SyncSummary, len(pairs))
+	errs := make([]error, len(pairs))
+	eg := errgroup.Group{}
+	eg.SetLimit(max(e.cfg.SyncConcurrency, 1))
+	for i, pair := range pairs {
+		eg.Go(func() error {
+			summaries[i], errs[i] = e.runForPair(ctx, pair)
+			return nil
+		})
+	}
+	_ = eg.Wait()
+
+	summary := SyncSummary{DryRun: e.dryRun}
+	synced := 0
+	for i := range pairs {
+		if errs[i] == nil {
+			summary.add(summaries[i])
+			synced++
+		}
+	}
+	err := errors.Join(errs...)
+	if synced == 0 {
+		return err
+	}
+	if err == nil && !e.dryRun {
+		e.lastSync = start
+	}
+	e.finishSync(ctx, start, summary)
+	return err
+}
+
+// syncProject syncs the Todoist and Jira projects of e.cfg. Engines from
+// forPair run it once per cycle.
+func (e *Engine) syncProject(ctx context.Context) (SyncSummary, error) {
+	e.startSync(ctx)
+
 	var (
 		project              *todoist.Project
 		sections             []todoist.Section
-		secMap               sectionMap
+		secMap               SectionMap
 		tasks                []todoist.Task
 		completedTodoistKeys map[string]bool
 		issues               []jira.Issue
 		eg                   = errgroup.Group{}
-		summary              syncSummary
+		summary              = SyncSummary{DryRun: e.dryRun}
 	)
 
+	if e.cfg.ValidateStatusMapOnStart {
+		if err := e.cfg.ValidateWithJira(ctx, e.jira); err != nil {
+			return SyncSummary{}, fmt.Errorf("sync: %w", err)
+		}
+	}
+
+	e.userNames = make(map[string]string)
+	e.todoistUsers = todoistUsers{}
+
+	var retried map[string]bool
+	if !e.dryRun {
+		retried = e.processRetryQueue(ctx, &summary)
+	}
+
 	eg.Go(func() error {
 		var todoistErr error
 		project, todoistErr = e.todoist.FindProjectByName(ctx, e.cfg.TodoistProject)
@@ -152,23 +442,27 @@ func (e *Engine) Run(ctx context.Context) error {
 		if todoistErr != nil {
 			return fmt.Errorf("get todoist sections: %w", todoistErr)
 		}
-		secMap = buildSectionMap(sections)
+		secMap = BuildSectionMap(sections)
 
 		tasks, todoistErr = e.todoist.GetTasks(ctx, project.ID)
 		if todoistErr != nil {
 			return fmt.Errorf("get todoist tasks: %w", todoistErr)
 		}
-
-		since := time.Now().Add(-72 * time.Hour).UTC().Format(time.RFC3339)
-		until := time.Now().UTC().Format(time.RFC3339)
-		completedTasks, err := e.todoist.GetCompletedTasks(ctx, project.ID, since, until)
-		if err != nil {
-			e.logger.Warn().Err(err).Msg("failed to fetch completed todoist tasks, skipping completion sync")
-		} else {
-			completedTodoistKeys = make(map[string]bool)
-			for _, ct := range completedTasks {
-				if key := ExtractJiraKey(ct.Content); key != "" {
-					completedTodoistKeys[key] = true
+		tasks = append(tasks, e.rehomeDriftedTasks(ctx, project.ID)...)
+
+		if e.cfg.CompletedLookback > 0 {
+			now := e.clock.Now().UTC()
+			since := now.Add(-e.cfg.CompletedLookback).Format(time.RFC3339)
+			until := now.Format(time.RFC3339)
+			completedTasks, err := e.todoist.GetCompletedTasks(ctx, project.ID, since, until)
+			if err != nil {
+				e.logger.Warn().Err(err).Msg("failed to fetch completed todoist tasks, skipping completion sync")
+			} else {
+				completedTodoistKeys = make(map[string]bool)
+				for _, ct := range completedTasks {
+					if key := ExtractJiraKey(ct.Content); key != "" {
+						completedTodoistKeys[key] = true
+					}
 				}
 			}
 		}
@@ -179,13 +473,7 @@ func (e *Engine) Run(ctx context.Context) error {
 
 	eg.Go(func() error {
 		var jiraErr error
-		jql := "project = " + e.cfg.JiraProject +
-			" AND assignee = currentUser()"
-		if typesJQL := e.cfg.JiraIssueTypesJQL(); typesJQL != "" {
-			jql += " AND " + typesJQL
-		}
-		jql += " ORDER BY updated DESC"
-		issues, jiraErr = e.jira.SearchIssues(ctx, jql, searchFields, 200)
+		issues, jiraErr = e.searchIssues(ctx, e.issueJQL().OrderBy("updated", "DESC").Build())
 		if jiraErr != nil {
 			return fmt.Errorf("search jira issues: %w", jiraErr)
 		}
@@ -193,50 +481,87 @@ func (e *Engine) Run(ctx context.Context) error {
 		return nil
 	})
 
-	err := eg.Wait()
-	if err != nil {
-		return fmt.Errorf("sync: %w", err)
+	if err := eg.Wait(); err != nil {
+		return SyncSummary{}, fmt.Errorf("sync: %w", err)
+	}
+	issues = slices.DeleteFunc(issues, func(issue jira.Issue) bool {
+		return e.cfg.ExcludesJiraKey(issue.Key)
+	})
+	if cyclic := DetectCycles(tasks); len(cyclic) > 0 {
+		e.logger.Error().
+			Strs("task_ids", cyclic).
+			Msg("todoist tasks are their own ancestors, skipping them")
+		tasks = slices.DeleteFunc(tasks, func(task todoist.Task) bool {
+			return slices.Contains(cyclic, task.ID)
+		})
 	}
 
 	todoistByJiraKey := make(map[string]*todoist.Task)
+	excludedJiraKeys := make(map[string]bool)
 	var unlinkedTodoistTasks []*todoist.Task
 	for i := range tasks {
 		jiraKey := ExtractJiraKey(tasks[i].Content)
-		if jiraKey != "" {
+		switch e.classifyTask(&tasks[i]) {
+		case taskExcluded:
+			if jiraKey != "" {
+				excludedJiraKeys[jiraKey] = true
+			}
+		case taskLinked:
 			todoistByJiraKey[jiraKey] = &tasks[i]
-		} else if slices.Contains(tasks[i].Labels, linkLabel) {
-			unlinkedTodoistTasks = append(unlinkedTodoistTasks, &tasks[i])
+		case taskUnlinked:
+			if !retried[retryID(retryCreateJira, "", tasks[i].ID)] {
+				unlinkedTodoistTasks = append(unlinkedTodoistTasks, &tasks[i])
+			}
+		case taskSkipped:
 		}
 	}
 
 	var unlinkedJiraIssues []*jira.Issue
 	for i := range issues {
-		if _, linked := todoistByJiraKey[issues[i].Key]; linked {
+		if _, linked := todoistByJiraKey[issues[i].Key]; linked || excludedJiraKeys[issues[i].Key] {
 			continue
 		}
 		if completedTodoistKeys[issues[i].Key] {
-			e.resolveJiraIssue(ctx, &issues[i], &summary)
+			if !retried[retryID(retryResolveJira, issues[i].Key, "")] {
+				e.resolveJiraIssue(ctx, &issues[i], &summary)
+			}
+			continue
+		}
+		if retried[retryID(retryCreateTodoist, issues[i].Key, "")] {
 			continue
 		}
 		if issues[i].Fields != nil && issues[i].Fields.Resolution != nil {
 			continue
 		}
-		if !jira.InCurrentSprint(&issues[i]) {
+		if !e.inSyncedSprint(&issues[i]) {
 			e.logger.Debug().
 				Str("issue_key", issues[i].Key).
-				Msg("jira issue not in active sprint, skipping todoist creation")
+				Msg("jira issue not in a synced sprint, skipping todoist creation")
 			continue
 		}
 		unlinkedJiraIssues = append(unlinkedJiraIssues, &issues[i])
 	}
 
+	if e.cfg.MaxSyncItems > 0 {
+		var capped bool
+		unlinkedTodoistTasks, unlinkedJiraIssues, capped = capSyncItems(
+			unlinkedTodoistTasks, unlinkedJiraIssues, e.cfg.MaxSyncItems,
+		)
+		if capped {
+			e.logger.Info().
+				Int("max_sync_items", e.cfg.MaxSyncItems).
+				Msg("sync cap reached, remaining items will be processed in future cycles")
+		}
+	}
+
 	for _, task := range unlinkedTodoistTasks {
 		if err := e.createJiraFromTodoist(ctx, task, secMap, &summary); err != nil {
 			e.logger.Error().Err(err).
 				Str("task_id", task.ID).
 				Str("task", task.Content).
 				Msg("failed to create jira issue from todoist task")
-			summary.errors = append(summary.errors, syncAction{summary: "create Jira from: " + task.Content})
+			e.recordError(ctx, &summary, SyncAction{Summary: "create Jira from: " + task.Content}, err)
+			e.queueRetry(retryItem{Kind: retryCreateJira, TaskID: task.ID, Summary: task.Content})
 		}
 	}
 
@@ -246,15 +571,17 @@ func (e *Engine) Run(ctx context.Context) error {
 				Str("issue_key", issue.Key).
 				Str("summary", issue.Fields.Summary).
 				Msg("failed to create todoist task from jira issue")
-			summary.errors = append(
-				summary.errors,
-				syncAction{jiraKey: issue.Key, summary: "create Todoist from: " + issue.Fields.Summary},
-			)
+			action := SyncAction{JiraKey: issue.Key, Summary: "create Todoist from: " + issue.Fields.Summary}
+			e.recordError(ctx, &summary, action, err)
+			e.queueRetry(retryItem{Kind: retryCreateTodoist, JiraKey: issue.Key, Summary: issue.Fields.Summary})
 		}
 	}
 
 	for jiraKey, task := range todoistByJiraKey {
-		issue, ok := findIssueByKey(issues, jiraKey)
+		if retried[retryID(retrySyncPair, jiraKey, task.ID)] {
+			continue
+		}
+		issue, ok := FindIssueByKey(issues, jiraKey)
 		if !ok {
 			e.logger.Warn().
 				Str("jira_key", jiraKey).
@@ -269,29 +596,315 @@ func (e *Engine) Run(ctx context.Context) error {
 				Str("issue_key", issue.Key).
 				Str("issue", issue.Fields.Summary).
 				Msg("failed to sync linked pair")
-			summary.errors = append(
-				summary.errors,
-				syncAction{jiraKey: issue.Key, summary: "sync: " + issue.Fields.Summary},
-			)
+			e.recordError(ctx, &summary, SyncAction{JiraKey: issue.Key, Summary: "sync: " + issue.Fields.Summary}, err)
+			e.queueRetry(retryItem{Kind: retrySyncPair, JiraKey: issue.Key, TaskID: task.ID, Summary: issue.Fields.Summary})
+		}
+	}
+
+	return summary, nil
+}
+
+// taskKind is how a sync cycle treats a Todoist task.
+type taskKind int
+
+const (
+	taskSkipped  taskKind = iota // left alone
+	taskExcluded                 // left alone, along with its Jira issue
+	taskLinked                   // synced with its Jira issue
+	taskUnlinked                 // gets a new Jira issue
+)
+
+// classifyTask reports how a sync cycle treats task, logging why it's skipped.
+func (e *Engine) classifyTask(task *todoist.Task) taskKind {
+	jiraKey := ExtractJiraKey(task.Content)
+	switch {
+	case e.cfg.ExcludesTask(task.Labels):
+		e.logger.Debug().
+			Str("task_id", task.ID).
+			Str("task", task.Content).
+			Msg("todoist task has an excluded label, skipping")
+		return taskExcluded
+	case e.cfg.ExcludesJiraKey(jiraKey):
+		e.logger.Debug().
+			Str("task_id", task.ID).
+			Str("issue_key", jiraKey).
+			Msg("todoist task linked to an excluded jira issue, skipping")
+		return taskSkipped
+	case jiraKey != "":
+		return taskLinked
+	case !slices.Contains(task.Labels, linkLabel):
+		return taskSkipped
+	case !e.cfg.IncludesTask(task.Labels):
+		e.logger.Debug().
+			Str("task_id", task.ID).
+			Str("task", task.Content).
+			Msg("todoist task has no included label, skipping")
+		return taskSkipped
+	case task.Due != nil && task.Due.IsRecurring && e.cfg.SkipsRecurringTask(task.Labels):
+		e.logger.Debug().
+			Str("task_id", task.ID).
+			Str("task", task.Content).
+			Msg("todoist task is recurring, skipping")
+		return taskSkipped
+	}
+	return taskUnlinked
+}
+
+// issueJQL returns a query for the Jira issues synced with e.cfg.
+func (e *Engine) issueJQL() *jira.JQLBuilder {
+	return jira.NewJQLBuilder().
+		Project(e.cfg.JiraProject).
+		Assignee(e.cfg.JiraAssigneeFilter).
+		IssueTypes(e.cfg.JiraIssueTypes...).
+		Components(e.cfg.JiraComponents...).
+		FixVersions(e.cfg.JiraFixVersions...).
+		Labels(e.cfg.JiraRequiredLabel)
+}
+
+// searchIssues runs jql, on Config.JiraBoard if it's set.
+func (e *Engine) searchIssues(ctx context.Context, jql string) ([]jira.Issue, error) {
+	if e.cfg.JiraBoard != 0 {
+		return e.jira.GetIssuesForBoard(ctx, e.cfg.JiraBoard, jql, searchFields)
+	}
+	return e.jira.SearchIssuesPaginated(ctx, jql, searchFields)
+}
+
+// rehomeDriftedTasks finds linked tasks of this Jira project that were moved
+// out of the Todoist project, e.g. by hand, and moves them back. The tasks are
+// returned to be synced with the project's own, so their issues don't get
+// duplicate tasks.
+func (e *Engine) rehomeDriftedTasks(ctx context.Context, projectID string) []todoist.Task {
+	linked, err := e.todoist.GetTasksByLabel(ctx, linkLabel)
+	if err != nil {
+		e.logger.Warn().Err(err).Msg("failed to fetch linked todoist tasks, not checking for drifted tasks")
+		return nil
+	}
+	var drifted []todoist.Task
+	for _, task := range linked {
+		jiraKey := ExtractJiraKey(task.Content)
+		if task.ProjectID == projectID || !inJiraProject(jiraKey, e.cfg.JiraProject) {
+			continue
+		}
+		e.logger.Warn().
+			Str("task_id", task.ID).
+			Str("issue_key", jiraKey).
+			Str("task_project_id", task.ProjectID).
+			Str("project_id", projectID).
+			Msg("todoist task drifted to another project, moving it back")
+		if !e.dryRun {
+			if err := e.todoist.MoveTaskToProject(ctx, task.ID, projectID); err != nil {
+				e.logger.Warn().Err(err).
+					Str("task_id", task.ID).
+					Msg("failed to move todoist task back to its project")
+			} else {
+				task.ProjectID = projectID
+			}
+		}
+		drifted = append(drifted, task)
+	}
+	return drifted
+}
+
+// todoistLabels returns labels plus the link label, the Jira issue's labels,
+// and extra. Labels are only ever added, so labels set in Todoist are kept.
+func todoistLabels(labels []string, issue *jira.Issue, extra ...string) []string {
+	merged := slices.Clone(labels)
+	for _, label := range slices.Concat([]string{linkLabel}, issue.Fields.Labels, extra) {
+		if !slices.Contains(merged, label) {
+			merged = append(merged, label)
+		}
+	}
+	return merged
+}
+
+// fixVersionLabels returns the Todoist labels for the issue's fix versions
+// with Config.SyncFixVersions, each named Config.FixVersionLabelPrefix plus the version.
+func (e *Engine) fixVersionLabels(issue *jira.Issue) []string {
+	if !e.cfg.SyncFixVersions {
+		return nil
+	}
+	labels := make([]string, 0, len(issue.Fields.FixVersions))
+	for _, version := range issue.Fields.FixVersions {
+		labels = append(labels, e.cfg.FixVersionLabelPrefix+version.Name)
+	}
+	return labels
+}
+
+// todoistDueDate returns the Todoist date synced with the Jira due date,
+// the deadline or the due date depending on Config.TodoistDueDateField.
+func (e *Engine) todoistDueDate(task *todoist.Task) string {
+	if e.cfg.SyncTodoistDeadline() {
+		return task.DeadlineDate()
+	}
+	return task.DueDate()
+}
+
+// jiraDueDate returns the Jira due date synced with Todoist. With
+// Config.UseSprintEndAsDueDate, an issue without a due date falls back to the
+// end date (in UTC) of its active sprint.
+func (e *Engine) jiraDueDate(issue *jira.Issue) string {
+	if issue.Fields.Duedate != "" || !e.cfg.UseSprintEndAsDueDate {
+		return issue.Fields.Duedate
+	}
+	sprints, err := jira.ParseSprintsFromRaw(issue.Fields.SprintRaw)
+	if err != nil {
+		e.logger.Debug().Err(err).
+			Str("issue_key", issue.Key).
+			Msg("failed to parse jira sprints, not using sprint end as due date")
+		return ""
+	}
+	for _, sprint := range sprints {
+		if sprint.State == jira.SprintStateActive && sprint.EndDate != nil {
+			return sprint.EndDate.UTC().Format(jira.DateFormat)
+		}
+	}
+	return ""
+}
+
+// todoistSection returns the Todoist section for issue: the section of its
+// first component, alphabetically, in Config.ComponentSectionMap, or else the
+// section mapped from its status. Components are matched case-insensitively.
+func (e *Engine) todoistSection(issue *jira.Issue) string {
+	var matches []string
+	for _, component := range slices.Sorted(maps.Keys(e.cfg.ComponentSectionMap)) {
+		if slices.ContainsFunc(issue.Fields.Components, func(c jira.Component) bool {
+			return strings.EqualFold(c.Name, component)
+		}) {
+			matches = append(matches, component)
+		}
+	}
+	if len(matches) > 1 {
+		e.logger.Debug().
+			Str("issue_key", issue.Key).
+			Strs("components", matches).
+			Str("component", matches[0]).
+			Msg("jira issue is in several mapped components, using the first")
+	}
+	if len(matches) > 0 {
+		return e.cfg.ComponentSectionMap[matches[0]]
+	}
+	if issue.Fields.Status == nil {
+		return ""
+	}
+	return e.cfg.JiraToTodoistStatus(issue.Fields.Status.Name)
+}
+
+// inSyncedSprint reports whether issue is in a sprint with one of Config.SprintStates.
+// With Config.SyncBacklog, every issue counts, in a sprint or not.
+func (e *Engine) inSyncedSprint(issue *jira.Issue) bool {
+	if e.cfg.SyncBacklog {
+		return true
+	}
+	if issue.Fields == nil {
+		return false
+	}
+	sprints, err := jira.ParseSprintsFromRaw(issue.Fields.SprintRaw)
+	if err != nil {
+		e.logger.Warn().Err(err).Str("issue_key", issue.Key).Msg("failed to parse jira sprints")
+		return false
+	}
+	for _, sprint := range sprints {
+		if slices.ContainsFunc(e.cfg.SprintStates(), func(state string) bool {
+			return strings.EqualFold(state, sprint.State)
+		}) {
+			return true
 		}
 	}
+	return false
+}
 
-	elapsed := time.Since(start)
+// finishSync saves sync state, then records and prints the summary.
+func (e *Engine) finishSync(ctx context.Context, start time.Time, summary SyncSummary) {
+	if !e.dryRun {
+		if err := e.state.Save(); err != nil {
+			e.logger.Error().Err(err).Msg("failed to save sync state")
+		}
+	}
+	summary.StartedAt = start
+	summary.FinishedAt = e.clock.Now()
 	e.logger.Info().
-		Str("duration", elapsed.String()).
+		Str("duration", summary.FinishedAt.Sub(start).String()).
 		Msg("sync complete")
 
-	summary.print(elapsed)
+	e.summaryMu.Lock()
+	e.lastSummary = summary.clone()
+	e.summaryMu.Unlock()
+
+	summary.print(e.summaryOut)
+	e.handler().OnSyncComplete(ctx, summary.clone(), summary.FinishedAt.Sub(start))
+	if e.cfg.SummaryLogFile != "" {
+		if err := writeSummaryLog(e.cfg.SummaryLogFile, summary); err != nil {
+			e.logger.Warn().Err(err).Str("path", e.cfg.SummaryLogFile).Msg("failed to write sync summary log")
+		}
+	}
+}
+
+// writeSummaryLog appends summary to path as a JSON line. The file is only
+// open for the write, so long-running watch mode doesn't hold it.
+func writeSummaryLog(path string, summary SyncSummary) error {
+	line, err := json.Marshal(summary)
+	if err != nil {
+		return fmt.Errorf("marshal sync summary: %w", err)
+	}
+	//nolint:gosec // Path comes from the user's own config
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("open sync summary log: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write sync summary log: %w", err)
+	}
+	return f.Close()
+}
+
+// Close releases the engine's resources and flushes sync state to the state store.
+// It's safe to call more than once; later calls return the first call's result.
+func (e *Engine) Close() error {
+	e.closeOnce.Do(func() {
+		var errs []error
+		if err := e.state.Save(); err != nil {
+			errs = append(errs, fmt.Errorf("save sync state: %w", err))
+		}
+		e.closeErr = errors.Join(errs...)
+	})
+	return e.closeErr
+}
+
+// ResetState clears all persisted sync state: field and content hashes and the
+// retry queue. The next Run behaves like the first one ever, so linked pairs
+// are compared in full.
+func (e *Engine) ResetState(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	e.state.Clear()
+	e.lastSync = time.Time{}
+	if err := e.state.Save(); err != nil {
+		return fmt.Errorf("save sync state: %w", err)
+	}
+	e.logger.Warn().Msg("sync state reset; next cycle will perform full re-sync")
 	return nil
 }
 
+// LastSummary returns a copy of the summary of the most recent completed Run, DryRun, SyncIssue, or SyncTask.
+func (e *Engine) LastSummary() SyncSummary {
+	e.summaryMu.RLock()
+	defer e.summaryMu.RUnlock()
+	return e.lastSummary.clone()
+}
+
 func (e *Engine) createJiraFromTodoist(
 	ctx context.Context,
 	task *todoist.Task,
-	secMap sectionMap,
-	s *syncSummary,
+	secMap SectionMap,
+	s *SyncSummary,
 ) error {
-	if !slices.Contains(task.Labels, linkLabel) {
+	if !slices.Contains(task.Labels, linkLabel) || !e.cfg.SyncsToJira() {
+		return nil
+	}
+	if e.dryRun {
+		s.CreatedJira = append(s.CreatedJira, SyncAction{Summary: task.Content})
 		return nil
 	}
 	sectionName := secMap.byID[task.SectionID]
@@ -302,13 +915,15 @@ func (e *Engine) createJiraFromTodoist(
 			Project:     &jira.Project{Key: e.cfg.JiraProject},
 			Summary:     task.Content,
 			Description: jira.TextToADF(task.Description),
-			IssueType:   &jira.IssueType{Name: defaultIssueType},
+			IssueType:   &jira.IssueType{Name: e.cfg.IssueTypeForSection(sectionName)},
 		},
 	})
 	if err != nil {
 		return fmt.Errorf("create jira issue: %w", err)
 	}
-	s.createdJira = append(s.createdJira, syncAction{jiraKey: created.Key, summary: task.Content})
+	createdAction := SyncAction{JiraKey: created.Key, Summary: task.Content}
+	s.CreatedJira = append(s.CreatedJira, createdAction)
+	e.handler().OnItemCreated(ctx, createdAction)
 	e.logger.Info().
 		Str("task_id", task.ID).
 		Str("task", task.Content).
@@ -339,18 +954,18 @@ func (e *Engine) createTodoistFromJira(
 	ctx context.Context,
 	issue *jira.Issue,
 	projectID string,
-	secMap sectionMap,
-	s *syncSummary,
+	secMap SectionMap,
+	s *SyncSummary,
 ) error {
-	statusName := ""
-	if issue.Fields.Status != nil {
-		statusName = issue.Fields.Status.Name
+	if issue.Fields.Resolution != nil || !e.cfg.SyncsToTodoist() {
+		return nil
 	}
-	if issue.Fields.Resolution != nil {
+	if e.dryRun {
+		s.CreatedTodoist = append(s.CreatedTodoist, SyncAction{JiraKey: issue.Key, Summary: issue.Fields.Summary})
 		return nil
 	}
 
-	sectionName := e.cfg.JiraToTodoistStatus(statusName)
+	sectionName := e.todoistSection(issue)
 	sectionID := secMap.byName[sectionName]
 
 	if sectionID == "" && sectionName != "" {
@@ -374,18 +989,30 @@ func (e *Engine) createTodoistFromJira(
 		Description: jira.ADFToText(issue.Fields.Description),
 		ProjectID:   projectID,
 		SectionID:   sectionID,
-		Labels:      []string{linkLabel},
+		Labels:      todoistLabels(nil, issue, e.fixVersionLabels(issue)...),
 		Priority:    jira.TodoistPriority(priorityID),
 	}
-	if issue.Fields.Duedate != "" {
-		createReq.DueDate = issue.Fields.Duedate
+	if dueDate := e.jiraDueDate(issue); dueDate != "" {
+		createReq.DueDate = dueDate
+		if e.cfg.SyncTodoistDeadline() {
+			createReq.DeadlineDate = dueDate
+		}
+	}
+	if d := todoistDuration(issue.Fields.TimeTracking); e.cfg.SyncDuration && d != nil {
+		createReq.Duration = d.Amount
+		createReq.DurationUnit = d.Unit
+	}
+	if e.cfg.SyncIssueLinks {
+		createReq.ChildOrder = blockOrder(issue)
 	}
 
 	task, err := e.todoist.CreateTask(ctx, createReq)
 	if err != nil {
 		return fmt.Errorf("create todoist task: %w", err)
 	}
-	s.createdTodoist = append(s.createdTodoist, syncAction{jiraKey: issue.Key, summary: issue.Fields.Summary})
+	createdAction := SyncAction{JiraKey: issue.Key, Summary: issue.Fields.Summary}
+	s.CreatedTodoist = append(s.CreatedTodoist, createdAction)
+	e.handler().OnItemCreated(ctx, createdAction)
 	e.logger.Info().
 		Str("issue_key", issue.Key).
 		Str("task_id", task.ID).
@@ -394,7 +1021,7 @@ func (e *Engine) createTodoistFromJira(
 		Int("priority", jira.TodoistPriority(priorityID)).
 		Msg("created todoist task from jira issue")
 
-	if err := e.syncCommentsToTodoist(ctx, issue, task.ID); err != nil {
+	if err := e.syncCommentsToTodoist(ctx, issue, task); err != nil {
 		e.logger.Warn().Err(err).
 			Str("task_id", task.ID).
 			Str("task", task.Content).
@@ -402,6 +1029,22 @@ func (e *Engine) createTodoistFromJira(
 			Str("issue", issue.Fields.Summary).
 			Msg("failed to sync comments to new todoist task")
 	}
+	if e.cfg.SyncAttachments || e.cfg.SyncAttachmentContent {
+		if err := e.syncAttachmentsToTodoist(ctx, issue, task.ID); err != nil {
+			e.logger.Warn().Err(err).
+				Str("task_id", task.ID).
+				Str("issue_key", issue.Key).
+				Msg("failed to sync attachments jira -> todoist")
+		}
+	}
+	if e.cfg.SyncWatchers {
+		if err := e.syncWatchersToTodoist(ctx, issue, task.ID); err != nil {
+			e.logger.Warn().Err(err).
+				Str("task_id", task.ID).
+				Str("issue_key", issue.Key).
+				Msg("failed to sync watchers jira -> todoist")
+		}
+	}
 
 	return nil
 }
@@ -411,98 +1054,236 @@ func (e *Engine) syncLinkedPair(
 	task *todoist.Task,
 	issue *jira.Issue,
 	projectID string,
-	secMap sectionMap,
-	s *syncSummary,
+	secMap SectionMap,
+	s *SyncSummary,
 ) error {
+	if e.cfg.ExcludesJiraKey(issue.Key) {
+		e.logger.Debug().
+			Str("task_id", task.ID).
+			Str("issue_key", issue.Key).
+			Msg("jira issue excluded, skipping linked pair")
+		return nil
+	}
+
+	action := SyncAction{JiraKey: issue.Key, Summary: issue.Fields.Summary}
+	if issue.Fields.Resolution != nil && !e.cfg.SyncsToTodoist() {
+		// Pushing the task would overwrite, or reopen, the resolved issue.
+		e.logger.Debug().
+			Str("task_id", task.ID).
+			Str("issue_key", issue.Key).
+			Msg("jira issue resolved, skipping linked pair")
+		e.recordSkipped(s, action)
+		return nil
+	}
 	if issue.Fields.Resolution != nil {
 		e.logger.Info().
 			Str("task_id", task.ID).
 			Str("issue_key", issue.Key).
 			Msg("jira issue resolved, closing todoist task")
-		s.completedTodoist = append(s.completedTodoist, syncAction{jiraKey: issue.Key, summary: issue.Fields.Summary})
-		return e.todoist.CloseTask(ctx, task.ID)
-	}
-
-	jiraUpdated, err := time.Parse("2006-01-02T15:04:05.000-0700", issue.Fields.Updated)
-	if err != nil {
-		jiraUpdated, err = time.Parse(time.RFC3339, issue.Fields.Updated)
-		if err != nil {
-			e.logger.Warn().Err(err).
-				Str("issue_key", issue.Key).
-				Str("raw", issue.Fields.Updated).
-				Msg("could not parse jira updated timestamp")
+		s.CompletedTodoist = append(s.CompletedTodoist, action)
+		if e.dryRun {
+			return nil
+		}
+		if err := e.todoist.CloseTask(ctx, task.ID); err != nil {
+			return err
 		}
+		e.handler().OnItemCompleted(ctx, action)
+		return nil
 	}
 
-	todoistUpdated, err := time.Parse(time.RFC3339Nano, task.UpdatedAt)
-	if err != nil {
+	if _, err := issue.Fields.UpdatedTime(); err != nil {
+		e.logger.Warn().Err(err).
+			Str("issue_key", issue.Key).
+			Str("raw", issue.Fields.Updated).
+			Msg("could not parse jira updated timestamp")
+	}
+	if _, err := task.UpdatedAtTime(); err != nil {
 		e.logger.Warn().Err(err).
 			Str("task_id", task.ID).
-			Msg("could not parse todoist updated_at, assuming todoist is newer")
-		s.updatedToJira = append(s.updatedToJira, syncAction{jiraKey: issue.Key, summary: issue.Fields.Summary})
-		return e.pushTodoistToJira(ctx, task, issue, secMap)
+			Str("raw", task.UpdatedAt).
+			Msg("could not parse todoist updated_at")
 	}
 
-	if jiraUpdated.After(todoistUpdated) {
+	switch e.direction(task, issue) {
+	case DirJiraToTodoist:
+		e.logger.Debug().
+			Str("task_id", task.ID).
+			Str("issue_key", issue.Key).
+			Msg("syncing jira -> todoist")
+		diff, err := e.pushJiraToTodoist(ctx, task, issue, projectID, secMap)
+		if err != nil {
+			return err
+		}
+		if diff.empty() {
+			e.recordSkipped(s, action)
+			return nil
+		}
+		action.Changed, action.Diff = diff.names(), diff.summarize()
+		s.UpdatedToTodoist = append(s.UpdatedToTodoist, action)
+		if !e.dryRun {
+			e.handler().OnItemUpdated(ctx, action)
+		}
+		return nil
+	case DirTodoistToJira:
 		e.logger.Debug().
 			Str("task_id", task.ID).
 			Str("issue_key", issue.Key).
-			Msg("jira is newer, syncing jira -> todoist")
-		s.updatedToTodoist = append(s.updatedToTodoist, syncAction{jiraKey: issue.Key, summary: issue.Fields.Summary})
-		return e.pushJiraToTodoist(ctx, task, issue, projectID, secMap)
+			Msg("syncing todoist -> jira")
+		diff, err := e.pushTodoistToJira(ctx, task, issue, secMap)
+		if err != nil {
+			return err
+		}
+		if diff.empty() {
+			e.recordSkipped(s, action)
+			return nil
+		}
+		action.Changed, action.Diff = diff.names(), diff.summarize()
+		s.UpdatedToJira = append(s.UpdatedToJira, action)
+		if !e.dryRun {
+			e.handler().OnItemUpdated(ctx, action)
+		}
+		return nil
+	default:
+		e.logger.Debug().
+			Str("task_id", task.ID).
+			Str("issue_key", issue.Key).
+			Msg("conflict resolver skipped linked pair")
+		return nil
 	}
+}
 
-	e.logger.Debug().
-		Str("task_id", task.ID).
-		Str("issue_key", issue.Key).
-		Msg("todoist is newer (or same), syncing todoist -> jira")
-	s.updatedToJira = append(s.updatedToJira, syncAction{jiraKey: issue.Key, summary: issue.Fields.Summary})
-	return e.pushTodoistToJira(ctx, task, issue, secMap)
+// direction returns the direction to sync a linked pair in. With a one-way
+// Config.SyncDirection that's always the allowed direction, otherwise the
+// conflict resolver decides.
+func (e *Engine) direction(task *todoist.Task, issue *jira.Issue) Direction {
+	switch {
+	case !e.cfg.SyncsToJira():
+		return DirJiraToTodoist
+	case !e.cfg.SyncsToTodoist():
+		return DirTodoistToJira
+	}
+	return e.resolver.Resolve(task, issue, e.lastSync)
 }
 
+// pushJiraToTodoist updates task from issue. It returns the fields it changed,
+// which are empty if the update was skipped because nothing needed to change.
+// In a dry run, it only returns the fields it would change.
 func (e *Engine) pushJiraToTodoist(
 	ctx context.Context,
 	task *todoist.Task,
 	issue *jira.Issue,
 	projectID string,
-	secMap sectionMap,
-) error {
+	secMap SectionMap,
+) (fieldDiff, error) {
 	linkedContent := PrependJiraLink(issue.Fields.Summary, issue.Key, e.cfg.JiraURL)
 	desc := jira.ADFToText(issue.Fields.Description)
+	dueDate := e.jiraDueDate(issue)
 
-	updateReq := todoist.UpdateTaskRequest{
-		Content:     &linkedContent,
-		Description: &desc,
+	fields := syncFields{
+		fieldSummary:     issue.Fields.Summary,
+		fieldDescription: desc,
+		fieldDueDate:     dueDate,
+	}
+	if section := e.todoistSection(issue); section != "" {
+		fields[fieldStatus] = section
 	}
-	if issue.Fields.Duedate != "" {
-		updateReq.DueDate = &issue.Fields.Duedate
+	if estimate := currentEstimate(issue); e.cfg.SyncDuration && estimate != "" {
+		fields[fieldEstimate] = estimate
 	}
+	changed := e.changedFields(task.ID, fields)
 
-	if _, err := e.todoist.UpdateTask(ctx, task.ID, updateReq); err != nil {
-		return fmt.Errorf("update todoist task: %w", err)
+	updateReq := todoist.UpdateTaskRequest{}
+	diff := newFieldDiff()
+	if changed[fieldSummary] && linkedContent != task.Content {
+		updateReq.Content = &linkedContent
+		diff.add(fieldSummary, StripJiraPrefix(task.Content), issue.Fields.Summary)
+	}
+	if changed[fieldDescription] && desc != task.Description {
+		updateReq.Description = &desc
+		diff.add(fieldDescription, task.Description, desc)
+	}
+	if changed[fieldDueDate] && dueDate != "" {
+		if dueDate != task.DueDate() {
+			updateReq.DueDate = &dueDate
+			diff.add(fieldDueDate, task.DueDate(), dueDate)
+		}
+		if e.cfg.SyncTodoistDeadline() && dueDate != task.DeadlineDate() {
+			updateReq.DeadlineDate = &dueDate
+			diff.add(fieldDeadline, task.DeadlineDate(), dueDate)
+		}
+	}
+	if issue.Fields.Priority != nil {
+		if priority := jira.TodoistPriority(issue.Fields.Priority.ID); priority != task.Priority {
+			updateReq.Priority = &priority
+			diff.add(fieldPriority, strconv.Itoa(task.Priority), strconv.Itoa(priority))
+		}
+	}
+	if labels := todoistLabels(task.Labels, issue, e.fixVersionLabels(issue)...); !slices.Equal(labels, task.Labels) {
+		updateReq.Labels = labels
+		diff.add(fieldLabels, strings.Join(task.Labels, ","), strings.Join(labels, ","))
+	}
+	if changed[fieldEstimate] && fields[fieldEstimate] != jiraEstimate(task.Duration) {
+		if d := todoistDuration(issue.Fields.TimeTracking); d != nil {
+			updateReq.Duration = &d.Amount
+			updateReq.DurationUnit = &d.Unit
+			diff.add(fieldEstimate, jiraEstimate(task.Duration), fields[fieldEstimate])
+		}
+	}
+	if e.cfg.SyncIssueLinks {
+		if order := blockOrder(issue); order != task.ChildOrder {
+			updateReq.ChildOrder = &order
+			diff.add(fieldOrder, strconv.Itoa(task.ChildOrder), strconv.Itoa(order))
+		}
 	}
 
-	if issue.Fields.Status != nil {
-		targetSection := e.cfg.JiraToTodoistStatus(issue.Fields.Status.Name)
+	if fields[fieldStatus] != "" && changed[fieldStatus] {
+		targetSection := fields[fieldStatus]
 		currentSection := secMap.byID[task.SectionID]
 		if targetSection != currentSection {
 			targetSectionID := secMap.byName[targetSection]
-			if targetSectionID == "" {
+			if targetSectionID == "" && !e.dryRun {
 				sec, err := e.todoist.CreateSection(ctx, projectID, targetSection)
 				if err != nil {
-					return fmt.Errorf("create todoist section %q: %w", targetSection, err)
+					return fieldDiff{}, fmt.Errorf("create todoist section %q: %w", targetSection, err)
 				}
 				targetSectionID = sec.ID
 				secMap.byID[sec.ID] = targetSection
 				secMap.byName[targetSection] = sec.ID
 			}
-			if err := e.todoist.MoveTaskToSection(ctx, task.ID, targetSectionID); err != nil {
-				return fmt.Errorf("move todoist task to section: %w", err)
-			}
+			updateReq.SectionID = &targetSectionID
+			diff.add(fieldStatus, currentSection, targetSection)
+		}
+	}
+
+	issueHash := IssueContentHash(issue)
+	switch {
+	case updateReq.IsEmpty():
+		e.logger.Debug().
+			Str("task_id", task.ID).
+			Str("issue_key", issue.Key).
+			Msg("todoist task already matches jira issue, skipping update")
+	case e.contentUnchanged(issue.Key, issueHash):
+		e.logger.Debug().
+			Str("task_id", task.ID).
+			Str("issue_key", issue.Key).
+			Msg("jira issue unchanged since last sync, skipping todoist update")
+		diff = newFieldDiff()
+		// Nothing was written, so no field is recorded as synced.
+		changed = nil
+	case e.dryRun:
+	default:
+		if _, err := e.todoist.UpdateTask(ctx, task.ID, updateReq); err != nil {
+			return fieldDiff{}, fmt.Errorf("update todoist task: %w", err)
 		}
 	}
+	if e.dryRun {
+		// The diff previews the update, and nothing else is written.
+		return diff, nil
+	}
+	e.recordContentHash(issue.Key, issueHash)
+	e.recordFields(task.ID, fields, changed)
 
-	if err := e.syncCommentsToTodoist(ctx, issue, task.ID); err != nil {
+	if err := e.syncCommentsToTodoist(ctx, issue, task); err != nil {
 		e.logger.Warn().Err(err).
 			Str("task_id", task.ID).
 			Str("task", task.Content).
@@ -510,96 +1291,347 @@ func (e *Engine) pushJiraToTodoist(
 			Str("issue", issue.Fields.Summary).
 			Msg("failed to sync comments jira -> todoist")
 	}
+	if e.cfg.SyncAttachments || e.cfg.SyncAttachmentContent {
+		if err := e.syncAttachmentsToTodoist(ctx, issue, task.ID); err != nil {
+			e.logger.Warn().Err(err).
+				Str("task_id", task.ID).
+				Str("issue_key", issue.Key).
+				Msg("failed to sync attachments jira -> todoist")
+		}
+	}
+	if e.cfg.SyncWatchers {
+		if err := e.syncWatchersToTodoist(ctx, issue, task.ID); err != nil {
+			e.logger.Warn().Err(err).
+				Str("task_id", task.ID).
+				Str("issue_key", issue.Key).
+				Msg("failed to sync watchers jira -> todoist")
+		}
+	}
 
-	return nil
+	return diff, nil
 }
 
+// pushTodoistToJira updates issue from task. It returns the fields it changed,
+// which are empty if the update and transition were skipped because nothing
+// needed to change. In a dry run, it only returns the fields it would change.
 func (e *Engine) pushTodoistToJira(
 	ctx context.Context,
 	task *todoist.Task,
 	issue *jira.Issue,
-	secMap sectionMap,
-) error {
+	secMap SectionMap,
+) (fieldDiff, error) {
 	summary := StripJiraPrefix(task.Content)
+	sectionName := secMap.byID[task.SectionID]
 
-	updateIssue := &jira.Issue{
-		Fields: &jira.IssueFields{
-			Summary:     summary,
-			Description: jira.TextToADF(task.Description),
-		},
+	fields := syncFields{
+		fieldSummary:     summary,
+		fieldDescription: task.Description,
+		fieldStatus:      sectionName,
 	}
-	if task.Due != nil && task.Due.Date != "" {
-		updateIssue.Fields.Duedate = task.Due.Date
+	dueDate := e.todoistDueDate(task)
+	if dueDate != "" {
+		fields[fieldDueDate] = dueDate
 	}
+	estimate := jiraEstimate(task.Duration)
+	if e.cfg.SyncDuration && estimate != "" {
+		fields[fieldEstimate] = estimate
+	}
+	changed := e.changedFields(task.ID, fields)
 
-	if err := e.jira.UpdateIssue(ctx, issue.Key, updateIssue); err != nil {
-		return fmt.Errorf("update jira issue: %w", err)
+	updateFields := jira.UpdateFields{}
+	diff := newFieldDiff()
+	if changed[fieldSummary] && summary != issue.Fields.Summary {
+		updateFields.Summary = summary
+		diff.add(fieldSummary, issue.Fields.Summary, summary)
+	}
+	if desc := jira.ADFToText(issue.Fields.Description); changed[fieldDescription] && task.Description != desc {
+		updateFields.Description = jira.TextToADF(task.Description)
+		diff.add(fieldDescription, desc, task.Description)
+	}
+	// A task due at the end of the issue's sprint got that date from the sprint,
+	// so it isn't copied to the issue.
+	if changed[fieldDueDate] && dueDate != "" && dueDate != e.jiraDueDate(issue) {
+		updateFields.DueDate = dueDate
+		diff.add(fieldDueDate, issue.Fields.Duedate, dueDate)
+	}
+	if changed[fieldEstimate] && estimate != currentEstimate(issue) {
+		updateFields.TimeTracking = &jira.TimeTracking{OriginalEstimate: estimate}
+		diff.add(fieldEstimate, currentEstimate(issue), estimate)
 	}
 
-	sectionName := secMap.byID[task.SectionID]
-	if sectionName != "" {
+	taskHash := TaskContentHash(task)
+	switch {
+	case diff.empty():
+		e.logger.Debug().
+			Str("task_id", task.ID).
+			Str("issue_key", issue.Key).
+			Msg("jira issue already matches todoist task, skipping update")
+	case e.contentUnchanged(task.ID, taskHash):
+		e.logger.Debug().
+			Str("task_id", task.ID).
+			Str("issue_key", issue.Key).
+			Msg("todoist task unchanged since last sync, skipping jira update")
+		diff = newFieldDiff()
+		// Nothing was written, so only a transition can still sync the status.
+		changed = map[string]bool{fieldStatus: changed[fieldStatus]}
+	case e.dryRun:
+	default:
+		if err := e.jira.UpdateIssue(ctx, issue.Key, updateFields); err != nil {
+			return fieldDiff{}, fmt.Errorf("update jira issue: %w", err)
+		}
+	}
+
+	if sectionName != "" && changed[fieldStatus] {
 		targetJiraStatus := e.cfg.TodoistToJiraStatus(sectionName)
 		currentStatus := ""
 		if issue.Fields.Status != nil {
 			currentStatus = issue.Fields.Status.Name
 		}
 		if !statusEquivalent(targetJiraStatus, currentStatus) {
+			if e.dryRun {
+				// The diff previews the transition, and nothing else is written.
+				diff.add(fieldStatus, currentStatus, targetJiraStatus)
+				return diff, nil
+			}
 			if err := e.jira.DoTransition(ctx, issue.Key, targetJiraStatus); err != nil {
 				e.logger.Warn().Err(err).
 					Str("issue_key", issue.Key).
 					Str("target", targetJiraStatus).
 					Str("issue", issue.Fields.Summary).
 					Msg("failed to transition jira issue")
+				changed[fieldStatus] = false
+			} else {
+				diff.add(fieldStatus, currentStatus, targetJiraStatus)
 			}
 		}
 	}
+	if e.dryRun {
+		return diff, nil
+	}
+	e.recordContentHash(task.ID, taskHash)
+	e.recordFields(task.ID, fields, changed)
 
-	return nil
+	if err := e.syncCommentsToJira(ctx, task, issue); err != nil {
+		e.logger.Warn().Err(err).
+			Str("task_id", task.ID).
+			Str("task", task.Content).
+			Str("issue_key", issue.Key).
+			Str("issue", issue.Fields.Summary).
+			Msg("failed to sync comments todoist -> jira")
+	}
+
+	return diff, nil
 }
 
 func (e *Engine) syncCommentsToTodoist(
 	ctx context.Context,
 	issue *jira.Issue,
-	todoistTaskID string,
+	task *todoist.Task,
 ) error {
 	if issue.Fields.Comment == nil {
 		return nil
 	}
 
-	todoistComments, err := e.todoist.GetComments(ctx, todoistTaskID)
+	todoistComments, err := e.todoist.GetComments(ctx, task.ID)
 	if err != nil {
 		return fmt.Errorf("get todoist comments: %w", err)
 	}
 
 	existingComments := make([]string, 0, len(todoistComments))
+	fromTodoist := make([]string, 0, 2*len(todoistComments))
 	for _, c := range todoistComments {
 		existingComments = append(existingComments, c.Content)
+		// Comments synced before attribution was enabled have the plain prefix.
+		fromTodoist = append(fromTodoist,
+			e.cfg.FormatCommentFromTodoist("")+c.Content,
+			e.todoistCommentPrefix(ctx, task.ProjectID, c)+c.Content,
+		)
 	}
 
 	for _, jc := range issue.Fields.Comment.Comments {
-		authorName := ""
-		if jc.Author != nil {
-			authorName = jc.Author.DisplayName
+		if e.commentSynced(jc.ID, jiraCommentKey) {
+			continue
 		}
-		syncedContent := fmt.Sprintf(commentFromJiraPrefix, authorName) + "\n" + jira.ADFToText(jc.Body)
-		if slices.Contains(existingComments, syncedContent) {
+		body := jira.ADFToText(jc.Body)
+		syncedContent := e.cfg.FormatCommentFromJira(e.jiraUserName(ctx, jc.Author)) + body
+		// Comments synced before they were marked are matched by their text.
+		if slices.Contains(fromTodoist, body) || slices.Contains(existingComments, syncedContent) {
+			e.markCommentSynced(jc.ID, "")
 			continue
 		}
-		_, err := e.todoist.CreateComment(ctx, todoist.CreateCommentRequest{
-			TaskID:  todoistTaskID,
+		created, err := e.todoist.CreateComment(ctx, todoist.CreateCommentRequest{
+			TaskID:  task.ID,
 			Content: syncedContent,
 		})
 		if err != nil {
 			e.logger.Error().Err(err).
-				Str("task_id", todoistTaskID).
+				Str("task_id", task.ID).
 				Msg("failed to add comment to todoist")
+			continue
+		}
+		e.markCommentSynced(jc.ID, created.ID)
+	}
+
+	return nil
+}
+
+// State store key suffixes marking synced comments, stored as {comment ID}:jira_comment
+// and {comment ID}:todoist_comment. Each holds the ID of the comment's copy, if known.
+const (
+	jiraCommentKey    = "jira_comment"
+	todoistCommentKey = "todoist_comment"
+)
+
+// markCommentSynced records that a Jira comment and a Todoist comment are
+// copies of each other, so neither is synced again, even if the comment
+// prefixes or the author's name change. Either ID may be empty.
+func (e *Engine) markCommentSynced(jiraCommentID, todoistCommentID string) {
+	if jiraCommentID != "" {
+		e.state.Set(fieldStateKey(jiraCommentID, jiraCommentKey), todoistCommentID)
+	}
+	if todoistCommentID != "" {
+		e.state.Set(fieldStateKey(todoistCommentID, todoistCommentKey), jiraCommentID)
+	}
+}
+
+// commentSynced reports whether the comment was marked by markCommentSynced.
+func (e *Engine) commentSynced(commentID, key string) bool {
+	if commentID == "" {
+		return false
+	}
+	_, ok := e.state.Get(fieldStateKey(commentID, key))
+	return ok
+}
+
+// jiraUserName returns the user's display name, looking it up by account ID if
+// the API didn't include it. Falls back to the account ID if the lookup fails.
+func (e *Engine) jiraUserName(ctx context.Context, user *jira.User) string {
+	if user == nil {
+		return ""
+	}
+	if user.DisplayName != "" || user.AccountID == "" {
+		return user.DisplayName
+	}
+	if name, ok := e.userNames[user.AccountID]; ok {
+		return name
+	}
+
+	name := user.AccountID
+	found, err := e.jira.GetUserByAccountID(ctx, user.AccountID)
+	if err != nil {
+		e.logger.Warn().Err(err).
+			Str("account_id", user.AccountID).
+			Msg("failed to look up jira user, using account ID")
+	} else if found.DisplayName != "" {
+		name = found.DisplayName
+	}
+	if e.userNames == nil {
+		e.userNames = make(map[string]string)
+	}
+	e.userNames[user.AccountID] = name
+	return name
+}
+
+// todoistUsers caches Todoist user names for a sync cycle. Todoist can't look
+// users up by ID, so names come from the token's user and project collaborators.
+type todoistUsers struct {
+	names    map[string]string // user ID -> full name, nil until the token's user is looked up
+	projects map[string]bool   // projects whose collaborators are in names
+}
+
+// todoistUserName returns the full name of the Todoist user with the given ID
+// if they're the token's user or a collaborator on the project, or "" if not.
+func (e *Engine) todoistUserName(ctx context.Context, projectID, uid string) string {
+	if uid == "" {
+		return ""
+	}
+	users := &e.todoistUsers
+	if users.names == nil {
+		users.names = make(map[string]string)
+		users.projects = make(map[string]bool)
+		if me, err := e.todoist.GetCurrentUser(ctx); err != nil {
+			e.logger.Warn().Err(err).Msg("failed to look up todoist user")
+		} else {
+			users.names[me.ID] = me.FullName
+		}
+	}
+	if name, ok := users.names[uid]; ok || users.projects[projectID] {
+		return name
+	}
+
+	users.projects[projectID] = true
+	collaborators, err := e.todoist.GetCollaborators(ctx, projectID)
+	if err != nil {
+		e.logger.Warn().Err(err).
+			Str("project_id", projectID).
+			Msg("failed to look up todoist project collaborators")
+	}
+	for _, c := range collaborators {
+		users.names[c.ID] = c.Name
+	}
+	return users.names[uid]
+}
+
+// todoistCommentPrefix returns the prefix for a Todoist comment synced to
+// Jira, naming its author if Config.CommentAttributeUsers is set.
+func (e *Engine) todoistCommentPrefix(ctx context.Context, projectID string, c todoist.Comment) string {
+	if !e.cfg.CommentAttributeUsers {
+		return e.cfg.FormatCommentFromTodoist("")
+	}
+	return e.cfg.FormatCommentFromTodoist(e.todoistUserName(ctx, projectID, c.PostedUID))
+}
+
+func (e *Engine) syncCommentsToJira(
+	ctx context.Context,
+	task *todoist.Task,
+	issue *jira.Issue,
+) error {
+	todoistComments, err := e.todoist.GetComments(ctx, task.ID)
+	if err != nil {
+		return fmt.Errorf("get todoist comments: %w", err)
+	}
+
+	var existingComments, fromJira []string
+	if issue.Fields.Comment != nil {
+		for _, jc := range issue.Fields.Comment.Comments {
+			body := jira.ADFToText(jc.Body)
+			existingComments = append(existingComments, body)
+			fromJira = append(fromJira, e.cfg.FormatCommentFromJira(e.jiraUserName(ctx, jc.Author))+body)
+		}
+	}
+
+	for _, c := range todoistComments {
+		if e.commentSynced(c.ID, todoistCommentKey) {
+			continue
+		}
+		if _, ok := attachmentFilename(c); ok || isWatchersComment(c) {
+			continue
 		}
+		syncedContent := e.todoistCommentPrefix(ctx, task.ProjectID, c) + c.Content
+		// Comments synced before they were marked are matched by their text.
+		if slices.Contains(fromJira, c.Content) ||
+			slices.Contains(existingComments, e.cfg.FormatCommentFromTodoist("")+c.Content) ||
+			slices.Contains(existingComments, syncedContent) {
+			e.markCommentSynced("", c.ID)
+			continue
+		}
+		created, err := e.jira.AddComment(ctx, issue.Key, jira.TextToADF(syncedContent))
+		if err != nil {
+			e.logger.Error().Err(err).
+				Str("issue_key", issue.Key).
+				Msg("failed to add comment to jira")
+			continue
+		}
+		e.markCommentSynced(created.ID, c.ID)
 	}
 
 	return nil
 }
 
-func (e *Engine) resolveJiraIssue(ctx context.Context, issue *jira.Issue, s *syncSummary) {
+func (e *Engine) resolveJiraIssue(ctx context.Context, issue *jira.Issue, s *SyncSummary) {
+	if !e.cfg.SyncsToJira() {
+		return
+	}
 	if issue.Fields != nil && issue.Fields.Resolution != nil {
 		e.logger.Debug().
 			Str("issue_key", issue.Key).
@@ -612,18 +1644,71 @@ func (e *Engine) resolveJiraIssue(ctx context.Context, issue *jira.Issue, s *syn
 		Str("summary", issue.Fields.Summary).
 		Msg("todoist task completed, resolving jira issue")
 
+	if e.dryRun {
+		s.ResolvedJira = append(s.ResolvedJira, SyncAction{JiraKey: issue.Key, Summary: issue.Fields.Summary})
+		return
+	}
 	if err := e.jira.DoTransition(ctx, issue.Key, "Closed"); err != nil {
 		e.logger.Error().Err(err).
 			Str("issue_key", issue.Key).
 			Msg("failed to transition jira issue to Closed")
-		s.errors = append(s.errors, syncAction{jiraKey: issue.Key, summary: "resolve: " + issue.Fields.Summary})
+		e.recordError(ctx, s, SyncAction{JiraKey: issue.Key, Summary: "resolve: " + issue.Fields.Summary}, err)
+		e.queueRetry(retryItem{Kind: retryResolveJira, JiraKey: issue.Key, Summary: issue.Fields.Summary})
 		return
 	}
-	s.resolvedJira = append(s.resolvedJira, syncAction{jiraKey: issue.Key, summary: issue.Fields.Summary})
+	action := SyncAction{JiraKey: issue.Key, Summary: issue.Fields.Summary}
+	s.ResolvedJira = append(s.ResolvedJira, action)
+	e.handler().OnItemCompleted(ctx, action)
+}
+
+// capSyncItems keeps the max most recently updated items across both lists.
+// It reports whether any items were dropped.
+func capSyncItems(
+	tasks []*todoist.Task,
+	issues []*jira.Issue,
+	maxItems int,
+) ([]*todoist.Task, []*jira.Issue, bool) {
+	if len(tasks)+len(issues) <= maxItems {
+		return tasks, issues, false
+	}
+
+	type candidate struct {
+		updated time.Time
+		task    *todoist.Task
+		issue   *jira.Issue
+	}
+	candidates := make([]candidate, 0, len(tasks)+len(issues))
+	for _, t := range tasks {
+		updated, _ := t.UpdatedAtTime()
+		candidates = append(candidates, candidate{updated: updated, task: t})
+	}
+	for _, i := range issues {
+		var updated time.Time
+		if i.Fields != nil {
+			updated, _ = i.Fields.UpdatedTime()
+		}
+		candidates = append(candidates, candidate{updated: updated, issue: i})
+	}
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		return b.updated.Compare(a.updated)
+	})
+
+	var keptTasks []*todoist.Task
+	var keptIssues []*jira.Issue
+	for _, c := range candidates[:maxItems] {
+		if c.task != nil {
+			keptTasks = append(keptTasks, c.task)
+		} else {
+			keptIssues = append(keptIssues, c.issue)
+		}
+	}
+	return keptTasks, keptIssues, true
 }
 
-func buildSectionMap(sections []todoist.Section) sectionMap {
-	sm := sectionMap{
+// BuildSectionMap indexes sections by ID and name.
+// If several sections share a name, the last one wins.
+func BuildSectionMap(sections []todoist.Section) SectionMap {
+	sm := SectionMap{
 		byID:   make(map[string]string, len(sections)),
 		byName: make(map[string]string, len(sections)),
 	}
@@ -634,7 +1719,8 @@ func buildSectionMap(sections []todoist.Section) sectionMap {
 	return sm
 }
 
-func findIssueByKey(issues []jira.Issue, key string) (*jira.Issue, bool) {
+// FindIssueByKey returns the issue with the given key.
+func FindIssueByKey(issues []jira.Issue, key string) (*jira.Issue, bool) {
 	for i := range issues {
 		if issues[i].Key == key {
 			return &issues[i], true
@@ -643,6 +1729,12 @@ func findIssueByKey(issues []jira.Issue, key string) (*jira.Issue, bool) {
 	return nil, false
 }
 
+// blockOrder returns the Todoist child order hint for an issue: issues blocked by
+// other issues sort after unblocked ones.
+func blockOrder(issue *jira.Issue) int {
+	return len(issue.Fields.BlockedBy()) + 1
+}
+
 // statusEquivalent returns true if two Jira statuses are functionally the same.
 var equivalentStatuses = [][]string{
 	{"To Do", "Open"},
@@ -1,9 +1,19 @@
 package syncer
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -14,6 +24,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/kalverra/todoist-jira-sync/config"
+	"github.com/kalverra/todoist-jira-sync/internal/testserver"
 	"github.com/kalverra/todoist-jira-sync/jira"
 	"github.com/kalverra/todoist-jira-sync/todoist"
 )
@@ -64,7 +75,8 @@ func e2eSetup(t *testing.T) *e2eEnv {
 	tc := todoist.NewClient(cfg.TodoistToken, logger)
 	jc, err := jira.NewClient(cfg, logger)
 	require.NoError(t, err)
-	engine := NewEngine(tc, jc, cfg, logger)
+	engine, err := NewEngine(WithTodoistClient(tc), WithJiraClient(jc), WithConfig(cfg), WithLogger(logger))
+	require.NoError(t, err)
 
 	project, err := tc.FindProjectByName(
 		context.Background(), cfg.TodoistProject,
@@ -125,6 +137,10 @@ func TestSyncTodoistToJira(t *testing.T) { //nolint:paralleltest
 	assert.Equal(t, taskName, issue.Fields.Summary)
 	assert.Contains(t, jira.ADFToText(issue.Fields.Description), "todoist to jira test")
 	assert.Equal(t, taskName, StripJiraPrefix(updatedTask.Content))
+
+	summary := env.engine.LastSummary()
+	assert.False(t, summary.DryRun)
+	assert.Contains(t, summary.CreatedJira, SyncAction{JiraKey: jiraKey, Summary: taskName})
 }
 
 func TestSyncJiraToTodoist(t *testing.T) { //nolint:paralleltest
@@ -175,6 +191,55 @@ func TestSyncJiraToTodoist(t *testing.T) { //nolint:paralleltest
 	assert.Contains(t, linkedTask.Labels, linkLabel)
 }
 
+func TestSyncIssue(t *testing.T) { //nolint:paralleltest
+	env := e2eSetup(t)
+	ctx := context.Background()
+
+	summary := fmt.Sprintf("e2e-sync-issue-%s", testID())
+	created, err := env.jiraClient.CreateIssue(ctx, &jira.Issue{
+		Fields: &jira.IssueFields{
+			Project:     &jira.Project{Key: env.cfg.JiraProject},
+			Summary:     summary,
+			Description: jira.TextToADF("single issue sync test"),
+			IssueType:   &jira.IssueType{Name: "Task"},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if err := env.jiraClient.DeleteIssue(
+			context.Background(), created.Key,
+		); err != nil {
+			t.Logf("cleanup: delete jira issue %s: %v", created.Key, err)
+		}
+	})
+
+	require.NoError(t, env.engine.SyncIssue(ctx, created.Key))
+
+	tasks, err := env.todoistClient.GetTasks(ctx, env.projectID)
+	require.NoError(t, err)
+	var linkedTask *todoist.Task
+	for i := range tasks {
+		if ExtractJiraKey(tasks[i].Content) == created.Key {
+			linkedTask = &tasks[i]
+			break
+		}
+	}
+	require.NotNil(t, linkedTask, "should find todoist task linked to %s", created.Key)
+	t.Cleanup(func() {
+		if err := env.todoistClient.DeleteTask(
+			context.Background(), linkedTask.ID,
+		); err != nil {
+			t.Logf("cleanup: delete todoist task %s: %v", linkedTask.ID, err)
+		}
+	})
+
+	assert.Equal(t, summary, StripJiraPrefix(linkedTask.Content))
+	assert.Equal(t,
+		[]SyncAction{{JiraKey: created.Key, Summary: summary}},
+		env.engine.LastSummary().CreatedTodoist,
+	)
+}
+
 func TestSyncComments(t *testing.T) { //nolint:paralleltest
 	env := e2eSetup(t)
 	ctx := context.Background()
@@ -347,11 +412,7 @@ func TestSyncDueDateUpdate(t *testing.T) { //nolint:paralleltest
 	})
 
 	newDue := "2027-03-15"
-	err = env.jiraClient.UpdateIssue(ctx, jiraKey, &jira.Issue{
-		Fields: &jira.IssueFields{
-			Duedate: newDue,
-		},
-	})
+	err = env.jiraClient.UpdateIssue(ctx, jiraKey, jira.UpdateFields{DueDate: newDue})
 	require.NoError(t, err)
 
 	time.Sleep(2 * time.Second)
@@ -363,3 +424,1786 @@ func TestSyncDueDateUpdate(t *testing.T) { //nolint:paralleltest
 	require.NotNil(t, refetched.Due)
 	assert.Equal(t, newDue, refetched.Due.Date)
 }
+
+func TestCapSyncItems(t *testing.T) {
+	t.Parallel()
+
+	tasks := []*todoist.Task{
+		{ID: "old-task", UpdatedAt: "2026-01-01T00:00:00Z"},
+		{ID: "new-task", UpdatedAt: "2026-01-04T00:00:00Z"},
+	}
+	issues := []*jira.Issue{
+		{Key: "PROJ-1", Fields: &jira.IssueFields{Updated: "2026-01-02T00:00:00.000+0000"}},
+		{Key: "PROJ-2", Fields: &jira.IssueFields{Updated: "2026-01-03T00:00:00.000+0000"}},
+	}
+
+	gotTasks, gotIssues, capped := capSyncItems(tasks, issues, 2)
+	assert.True(t, capped)
+	require.Len(t, gotTasks, 1)
+	assert.Equal(t, "new-task", gotTasks[0].ID)
+	require.Len(t, gotIssues, 1)
+	assert.Equal(t, "PROJ-2", gotIssues[0].Key)
+
+	gotTasks, gotIssues, capped = capSyncItems(tasks, issues, 10)
+	assert.False(t, capped)
+	assert.Len(t, gotTasks, 2)
+	assert.Len(t, gotIssues, 2)
+}
+
+func TestLastSummaryIsCopy(t *testing.T) {
+	t.Parallel()
+
+	e := &Engine{}
+	assert.Empty(t, e.LastSummary().CreatedJira)
+
+	e.lastSummary = SyncSummary{CreatedJira: []SyncAction{{JiraKey: "PROJ-1", Summary: "task"}}}
+	got := e.LastSummary()
+	got.CreatedJira[0].Summary = "changed"
+	assert.Equal(t, "task", e.LastSummary().CreatedJira[0].Summary)
+}
+
+func TestJiraUserName(t *testing.T) {
+	t.Parallel()
+
+	requests := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/user", r.URL.Path)
+		accountID := r.URL.Query().Get("accountId")
+		requests[accountID]++
+		if accountID != "abc123" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(jira.User{AccountID: accountID, DisplayName: "Jane Doe"}))
+	}))
+	t.Cleanup(srv.Close)
+
+	cfg := &config.Config{CommentFromJiraPrefix: config.DefaultCommentFromJiraPrefix}
+	jc, err := jira.NewClient(cfg, zerolog.Nop(), jira.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+	e, err := NewEngine(WithTodoistClient(todoist.NewClient("", zerolog.Nop())), WithJiraClient(jc), WithConfig(cfg))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	assert.Empty(t, e.jiraUserName(ctx, nil))
+	assert.Equal(t, "John Roe", e.jiraUserName(ctx, &jira.User{AccountID: "def456", DisplayName: "John Roe"}))
+	assert.Equal(t, "Jane Doe", e.jiraUserName(ctx, &jira.User{AccountID: "abc123"}))
+	assert.Equal(t, "Jane Doe", e.jiraUserName(ctx, &jira.User{AccountID: "abc123"}))
+	assert.Equal(t, "unknown", e.jiraUserName(ctx, &jira.User{AccountID: "unknown"}))
+	assert.Equal(t, map[string]int{"abc123": 1, "unknown": 1}, requests)
+
+	assert.Equal(t,
+		"`[From Jira Jane Doe]`\nhello",
+		cfg.FormatCommentFromJira(e.jiraUserName(ctx, &jira.User{AccountID: "abc123"}))+"hello",
+	)
+}
+
+func TestSyncCommentsToJiraSkipsAuthorWithoutDisplayName(t *testing.T) {
+	t.Parallel()
+
+	todoistSrv := testserver.NewTodoist(t)
+	jiraSrv := testserver.NewJira(t)
+	cfg := &config.Config{
+		CommentFromJiraPrefix:    config.DefaultCommentFromJiraPrefix,
+		CommentFromTodoistPrefix: config.DefaultCommentFromTodoistPrefix,
+	}
+	e := &Engine{
+		todoist: testserver.TodoistClient(t, todoistSrv),
+		jira:    testserver.JiraClient(t, jiraSrv),
+		cfg:     cfg,
+		state:   newMemoryStateStore(),
+		logger:  zerolog.Nop(),
+	}
+	project := todoistSrv.AddProject("Work")
+	task := todoistSrv.AddTask(todoist.Task{ProjectID: project.ID, Content: "commented"})
+	issue := jiraSrv.AddIssue(jira.IssueFields{Project: &jira.Project{Key: "PROJ"}, Summary: "commented"})
+	issue.Fields.Comment = &jira.CommentPage{Comments: []jira.Comment{
+		{Author: &jira.User{AccountID: "no-name"}, Body: jira.TextToADF("hello")},
+	}}
+
+	require.NoError(t, e.syncCommentsToTodoist(t.Context(), &issue, &task))
+	comments := todoistSrv.Comments(task.ID)
+	require.Len(t, comments, 1)
+	assert.Equal(t, cfg.FormatCommentFromJira("no-name")+"hello", comments[0].Content, "falls back to the account ID")
+
+	require.NoError(t, e.syncCommentsToJira(t.Context(), &task, &issue))
+	synced, ok := jiraSrv.Issue(issue.Key)
+	require.True(t, ok)
+	for _, c := range synced.Fields.Comment.Comments {
+		assert.NotContains(t, jira.ADFToText(c.Body), "[From Todoist]", "the jira comment isn't echoed back")
+	}
+}
+
+func TestSyncCommentsToJiraAttributesUsers(t *testing.T) {
+	t.Parallel()
+
+	todoistSrv := testserver.NewTodoist(t)
+	jiraSrv := testserver.NewJira(t)
+	e := &Engine{
+		todoist: testserver.TodoistClient(t, todoistSrv),
+		jira:    testserver.JiraClient(t, jiraSrv),
+		cfg: &config.Config{
+			CommentFromJiraPrefix:    config.DefaultCommentFromJiraPrefix,
+			CommentFromTodoistPrefix: config.DefaultCommentFromTodoistPrefix,
+			CommentAttributeUsers:    true,
+		},
+		state:  newMemoryStateStore(),
+		logger: zerolog.Nop(),
+	}
+	project := todoistSrv.AddProject("Shared")
+	todoistSrv.AddCollaborator(project.ID, todoist.Collaborator{ID: "alice-id", Name: "Alice"})
+	task := todoistSrv.AddTask(todoist.Task{ProjectID: project.ID, Content: "commented"})
+	for _, c := range []todoist.Comment{
+		{PostedUID: "alice-id", Content: "from alice"},
+		{PostedUID: testserver.TodoistUser.ID, Content: "from me"},
+		{PostedUID: "stranger-id", Content: "from a stranger"},
+		{PostedUID: "alice-id", Content: "synced before attribution"},
+	} {
+		todoistSrv.AddComment(task.ID, c)
+	}
+	created := jiraSrv.AddIssue(jira.IssueFields{Project: &jira.Project{Key: "PROJ"}, Summary: "commented"})
+	_, err := e.jira.AddComment(t.Context(), created.Key, jira.TextToADF("[From Todoist] synced before attribution"))
+	require.NoError(t, err)
+
+	jiraComments := func() []string {
+		issue, ok := jiraSrv.Issue(created.Key)
+		require.True(t, ok)
+		var bodies []string
+		for _, c := range issue.Fields.Comment.Comments {
+			bodies = append(bodies, jira.ADFToText(c.Body))
+		}
+		return bodies
+	}
+	want := []string{
+		"[From Todoist] synced before attribution",
+		"[From Todoist: Alice] from alice",
+		"[From Todoist: Test User] from me",
+		"[From Todoist] from a stranger",
+	}
+	for range 2 {
+		issue, ok := jiraSrv.Issue(created.Key)
+		require.True(t, ok)
+		require.NoError(t, e.syncCommentsToJira(t.Context(), &task, &issue))
+		assert.Equal(t, want, jiraComments(), "comments are synced once")
+	}
+
+	issue, ok := jiraSrv.Issue(created.Key)
+	require.True(t, ok)
+	require.NoError(t, e.syncCommentsToTodoist(t.Context(), &issue, &task))
+	assert.Len(t, todoistSrv.Comments(task.ID), 4, "attributed comments aren't echoed back to todoist")
+}
+
+func TestSyncCommentsNotEchoed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		change func(e *Engine)
+	}{
+		{
+			name: "prefix changed",
+			change: func(e *Engine) {
+				e.cfg.CommentFromJiraPrefix = "(Jira %s) "
+			},
+		},
+		{
+			name: "author lookup fell back to the account ID",
+			change: func(e *Engine) {
+				e.userNames = map[string]string{"no-name": "Jane Doe"}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			todoistSrv := testserver.NewTodoist(t)
+			jiraSrv := testserver.NewJira(t)
+			e := &Engine{
+				todoist: testserver.TodoistClient(t, todoistSrv),
+				jira:    testserver.JiraClient(t, jiraSrv),
+				cfg: &config.Config{
+					CommentFromJiraPrefix:    config.DefaultCommentFromJiraPrefix,
+					CommentFromTodoistPrefix: config.DefaultCommentFromTodoistPrefix,
+				},
+				state:  newMemoryStateStore(),
+				logger: zerolog.Nop(),
+			}
+			project := todoistSrv.AddProject("Work")
+			task := todoistSrv.AddTask(todoist.Task{ProjectID: project.ID, Content: "commented"})
+			issue := jiraSrv.AddIssue(jira.IssueFields{Project: &jira.Project{Key: "PROJ"}, Summary: "commented"})
+			issue.Fields.Comment = &jira.CommentPage{Comments: []jira.Comment{
+				{ID: "10", Author: &jira.User{AccountID: "no-name"}, Body: jira.TextToADF("hello")},
+			}}
+
+			require.NoError(t, e.syncCommentsToTodoist(t.Context(), &issue, &task))
+			require.Len(t, todoistSrv.Comments(task.ID), 1)
+
+			tt.change(e)
+			require.NoError(t, e.syncCommentsToJira(t.Context(), &task, &issue))
+			synced, ok := jiraSrv.Issue(issue.Key)
+			require.True(t, ok)
+			assert.Empty(t, synced.Fields.Comment.Comments, "the jira comment isn't echoed back")
+			require.NoError(t, e.syncCommentsToTodoist(t.Context(), &issue, &task))
+			assert.Len(t, todoistSrv.Comments(task.ID), 1, "the jira comment isn't synced twice")
+		})
+	}
+}
+
+func TestBuildSectionMap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		sections   []todoist.Section
+		wantByID   map[string]string
+		wantByName map[string]string
+	}{
+		{
+			name:       "empty",
+			wantByID:   map[string]string{},
+			wantByName: map[string]string{},
+		},
+		{
+			name:       "single section",
+			sections:   []todoist.Section{{ID: "1", Name: "To Do"}},
+			wantByID:   map[string]string{"1": "To Do"},
+			wantByName: map[string]string{"To Do": "1"},
+		},
+		{
+			name:       "unique names",
+			sections:   []todoist.Section{{ID: "1", Name: "To Do"}, {ID: "2", Name: "Done"}},
+			wantByID:   map[string]string{"1": "To Do", "2": "Done"},
+			wantByName: map[string]string{"To Do": "1", "Done": "2"},
+		},
+		{
+			// Tasks in either section still map to the right status, and new
+			// tasks and moves go to the last section with the name.
+			name:       "duplicate names, last one wins",
+			sections:   []todoist.Section{{ID: "1", Name: "To Do"}, {ID: "2", Name: "Done"}, {ID: "3", Name: "To Do"}},
+			wantByID:   map[string]string{"1": "To Do", "2": "Done", "3": "To Do"},
+			wantByName: map[string]string{"To Do": "3", "Done": "2"},
+		},
+		{
+			name:       "spaces and special characters",
+			sections:   []todoist.Section{{ID: "1", Name: "  In Review / QA 🚧 "}, {ID: "2", Name: "Won't Do"}},
+			wantByID:   map[string]string{"1": "  In Review / QA 🚧 ", "2": "Won't Do"},
+			wantByName: map[string]string{"  In Review / QA 🚧 ": "1", "Won't Do": "2"},
+		},
+		{
+			name:       "empty name",
+			sections:   []todoist.Section{{ID: "1", Name: ""}},
+			wantByID:   map[string]string{"1": ""},
+			wantByName: map[string]string{"": "1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sm := BuildSectionMap(tt.sections)
+			assert.Equal(t, tt.wantByID, sm.byID)
+			assert.Equal(t, tt.wantByName, sm.byName)
+			for id, name := range tt.wantByID {
+				got, ok := sm.Name(id)
+				assert.True(t, ok)
+				assert.Equal(t, name, got)
+			}
+			for name, id := range tt.wantByName {
+				got, ok := sm.ID(name)
+				assert.True(t, ok)
+				assert.Equal(t, id, got)
+			}
+			_, ok := sm.Name("missing")
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestFindIssueByKey(t *testing.T) {
+	t.Parallel()
+
+	_, ok := FindIssueByKey(nil, "PROJ-1")
+	assert.False(t, ok)
+
+	issues := []jira.Issue{{Key: "PROJ-1"}, {Key: "PROJ-2"}}
+	issue, ok := FindIssueByKey(issues, "PROJ-2")
+	require.True(t, ok)
+	assert.Same(t, &issues[1], issue)
+	_, ok = FindIssueByKey(issues, "PROJ-3")
+	assert.False(t, ok)
+}
+
+func TestEngineClose(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	store, err := NewFileStateStore(path)
+	require.NoError(t, err)
+	store.Set("123:summary", "abc")
+
+	cfg := &config.Config{}
+	jc, err := jira.NewClient(cfg, zerolog.Nop())
+	require.NoError(t, err)
+	e, err := NewEngine(
+		WithTodoistClient(todoist.NewClient("", zerolog.Nop())),
+		WithJiraClient(jc),
+		WithConfig(cfg),
+		WithStateStore(store),
+	)
+	require.NoError(t, err)
+	require.NoError(t, e.Close())
+	require.NoError(t, e.Close(), "close should be idempotent")
+
+	reloaded, err := NewFileStateStore(path)
+	require.NoError(t, err)
+	got, ok := reloaded.Get("123:summary")
+	assert.True(t, ok)
+	assert.Equal(t, "abc", got)
+}
+
+func TestEngineResetState(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	store, err := NewFileStateStore(path)
+	require.NoError(t, err)
+	store.Set("123:summary", "abc")
+	store.Set(retryQueueStateKey, "[]")
+
+	cfg := &config.Config{}
+	jc, err := jira.NewClient(cfg, zerolog.Nop())
+	require.NoError(t, err)
+	e, err := NewEngine(
+		WithTodoistClient(todoist.NewClient("", zerolog.Nop())),
+		WithJiraClient(jc),
+		WithConfig(cfg),
+		WithStateStore(store),
+	)
+	require.NoError(t, err)
+	e.lastSync = time.Now()
+	require.NoError(t, e.ResetState(t.Context()))
+	assert.True(t, e.lastSync.IsZero())
+
+	reloaded, err := NewFileStateStore(path)
+	require.NoError(t, err)
+	_, ok := reloaded.Get("123:summary")
+	assert.False(t, ok)
+	_, ok = reloaded.Get(retryQueueStateKey)
+	assert.False(t, ok)
+}
+
+func TestEngineRunNCancelled(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{}
+	jc, err := jira.NewClient(cfg, zerolog.Nop())
+	require.NoError(t, err)
+	e, err := NewEngine(
+		WithTodoistClient(todoist.NewClient("", zerolog.Nop())),
+		WithJiraClient(jc),
+		WithConfig(cfg),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	require.ErrorIs(t, e.RunN(ctx, 3), context.Canceled)
+	require.NoError(t, e.RunN(ctx, 0))
+}
+
+func TestNewEngineRequiredOptions(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{}
+	tc := todoist.NewClient("", zerolog.Nop())
+	jc, err := jira.NewClient(cfg, zerolog.Nop())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		opts    []EngineOption
+		wantErr string
+	}{
+		{name: "all required", opts: []EngineOption{WithTodoistClient(tc), WithJiraClient(jc), WithConfig(cfg)}},
+		{name: "no todoist client", opts: []EngineOption{WithJiraClient(jc), WithConfig(cfg)}, wantErr: "todoist"},
+		{name: "no jira client", opts: []EngineOption{WithTodoistClient(tc), WithConfig(cfg)}, wantErr: "jira"},
+		{name: "no config", opts: []EngineOption{WithTodoistClient(tc), WithJiraClient(jc)}, wantErr: "config"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, err := NewEngine(tt.opts...)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, e)
+		})
+	}
+}
+
+func TestInSyncedSprint(t *testing.T) {
+	t.Parallel()
+
+	active := json.RawMessage(`[{"id": 1, "state": "closed"}, {"id": 2, "state": "active"}]`)
+	future := json.RawMessage(`[{"id": 3, "state": "future"}]`)
+
+	tests := []struct {
+		name       string
+		cfg        *config.Config
+		raw        json.RawMessage
+		wantSynced bool
+	}{
+		{name: "active sprint", cfg: &config.Config{JiraSprintStates: []string{"active"}}, raw: active, wantSynced: true},
+		{name: "future sprint", cfg: &config.Config{JiraSprintStates: []string{"active"}}, raw: future},
+		{
+			name:       "future sprint allowed",
+			cfg:        &config.Config{JiraSprintStates: []string{"active", "future"}},
+			raw:        future,
+			wantSynced: true,
+		},
+		{
+			name: "require active sprint",
+			cfg:  &config.Config{JiraSprintStates: []string{"future"}, RequireActiveSprint: true},
+			raw:  future,
+		},
+		{name: "default states", cfg: &config.Config{}, raw: active, wantSynced: true},
+		{name: "no sprint", cfg: &config.Config{JiraSprintStates: []string{"active"}}},
+		{name: "backlog", cfg: &config.Config{SyncBacklog: true}, wantSynced: true},
+		{name: "backlog future sprint", cfg: &config.Config{SyncBacklog: true}, raw: future, wantSynced: true},
+		{name: "malformed", cfg: &config.Config{JiraSprintStates: []string{"active"}}, raw: json.RawMessage(`{}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := &Engine{cfg: tt.cfg, logger: zerolog.Nop()}
+			issue := &jira.Issue{Key: "PROJ-1", Fields: &jira.IssueFields{SprintRaw: tt.raw}}
+			assert.Equal(t, tt.wantSynced, e.inSyncedSprint(issue))
+		})
+	}
+}
+
+func TestSyncIssueAppliesFilters(t *testing.T) {
+	t.Parallel()
+
+	todoistSrv := testserver.NewTodoist(t)
+	jiraSrv := testserver.NewJira(t)
+	cfg := &config.Config{
+		TodoistProject: "Work",
+		JiraProject:    "PROJ",
+		JiraIssueTypes: []string{"Task"},
+		JiraURL:        jiraSrv.URL,
+		SyncBacklog:    true,
+	}
+	e, err := NewEngine(
+		WithTodoistClient(testserver.TodoistClient(t, todoistSrv)),
+		WithJiraClient(testserver.JiraClient(t, jiraSrv)),
+		WithConfig(cfg),
+		WithStateStore(newMemoryStateStore()),
+	)
+	require.NoError(t, err)
+	e.SetSummaryOutput(io.Discard)
+	project := todoistSrv.AddProject(cfg.TodoistProject)
+
+	for _, tt := range []struct {
+		name        string
+		project     string
+		issueType   string
+		wantCreated bool
+	}{
+		{name: "matching", project: "PROJ", issueType: "Task", wantCreated: true},
+		{name: "other project", project: "OTHER", issueType: "Task"},
+		{name: "other issue type", project: "PROJ", issueType: "Bug"},
+	} {
+		issue := jiraSrv.AddIssue(jira.IssueFields{
+			Project:   &jira.Project{Key: tt.project},
+			IssueType: &jira.IssueType{Name: tt.issueType},
+			Summary:   tt.name,
+		})
+		require.NoError(t, e.SyncIssue(t.Context(), issue.Key), tt.name)
+
+		tasks, err := testserver.TodoistClient(t, todoistSrv).GetTasks(t.Context(), project.ID)
+		require.NoError(t, err)
+		created := slices.ContainsFunc(tasks, func(task todoist.Task) bool {
+			return ExtractJiraKey(task.Content) == issue.Key
+		})
+		assert.Equal(t, tt.wantCreated, created, tt.name)
+	}
+}
+
+func TestSyncTaskAppliesFilters(t *testing.T) {
+	t.Parallel()
+
+	todoistSrv := testserver.NewTodoist(t)
+	jiraSrv := testserver.NewJira(t)
+	cfg := &config.Config{
+		TodoistProject:     "Work",
+		JiraProject:        "PROJ",
+		JiraURL:            jiraSrv.URL,
+		ExcludeLabels:      []string{"private"},
+		IncludeLabels:      []string{"work"},
+		SkipRecurringTasks: true,
+	}
+	e, err := NewEngine(
+		WithTodoistClient(testserver.TodoistClient(t, todoistSrv)),
+		WithJiraClient(testserver.JiraClient(t, jiraSrv)),
+		WithConfig(cfg),
+		WithStateStore(newMemoryStateStore()),
+	)
+	require.NoError(t, err)
+	e.SetSummaryOutput(io.Discard)
+	work := todoistSrv.AddProject(cfg.TodoistProject)
+	home := todoistSrv.AddProject("Home")
+	labels := []string{linkLabel, "work"}
+	weekly := &todoist.Due{String: "every monday", Date: "2026-03-02", IsRecurring: true}
+
+	for _, tt := range []struct {
+		name       string
+		task       todoist.Task
+		wantLinked bool
+	}{
+		{name: "matching", task: todoist.Task{ProjectID: work.ID, Labels: labels}, wantLinked: true},
+		{name: "other project", task: todoist.Task{ProjectID: home.ID, Labels: labels}},
+		{name: "excluded label", task: todoist.Task{ProjectID: work.ID, Labels: append(labels, "private")}},
+		{name: "no included label", task: todoist.Task{ProjectID: work.ID, Labels: []string{linkLabel}}},
+		{name: "recurring", task: todoist.Task{ProjectID: work.ID, Labels: labels, Due: weekly}},
+		{name: "own ancestor", task: todoist.Task{ID: "cyclic", ProjectID: work.ID, Labels: labels, ParentID: "cyclic"}},
+	} {
+		tt.task.Content = tt.name
+		task := todoistSrv.AddTask(tt.task)
+		require.NoError(t, e.SyncTask(t.Context(), task.ID), tt.name)
+
+		synced, ok := todoistSrv.Task(task.ID)
+		require.True(t, ok)
+		assert.Equal(t, tt.wantLinked, ExtractJiraKey(synced.Content) != "", tt.name)
+	}
+}
+
+func TestRunRehomesDriftedTasks(t *testing.T) {
+	t.Parallel()
+
+	todoistSrv := testserver.NewTodoist(t)
+	jiraSrv := testserver.NewJira(t)
+	cfg := &config.Config{
+		TodoistProject: "Work",
+		JiraProject:    "PROJ",
+		JiraURL:        jiraSrv.URL,
+		SyncBacklog:    true,
+	}
+	e, err := NewEngine(
+		WithTodoistClient(testserver.TodoistClient(t, todoistSrv)),
+		WithJiraClient(testserver.JiraClient(t, jiraSrv)),
+		WithConfig(cfg),
+		WithStateStore(newMemoryStateStore()),
+	)
+	require.NoError(t, err)
+	e.SetSummaryOutput(io.Discard)
+
+	work := todoistSrv.AddProject(cfg.TodoistProject)
+	home := todoistSrv.AddProject("Home")
+	issue := jiraSrv.AddIssue(jira.IssueFields{Project: &jira.Project{Key: "PROJ"}, Summary: "Fix login"})
+	other := todoistSrv.AddTask(todoist.Task{
+		ProjectID: home.ID, Content: "[OTHER-1](https://example.com/browse/OTHER-1) Paint fence", Labels: []string{linkLabel},
+	})
+
+	require.NoError(t, e.Run(t.Context()))
+	linked := func() []todoist.Task {
+		tasks, err := testserver.TodoistClient(t, todoistSrv).GetTasksByLabel(t.Context(), linkLabel)
+		require.NoError(t, err)
+		return slices.DeleteFunc(tasks, func(task todoist.Task) bool { return ExtractJiraKey(task.Content) != issue.Key })
+	}
+	created := linked()
+	require.Len(t, created, 1)
+	assert.Equal(t, work.ID, created[0].ProjectID)
+
+	require.NoError(t, testserver.TodoistClient(t, todoistSrv).MoveTaskToProject(t.Context(), created[0].ID, home.ID))
+	require.NoError(t, e.Run(t.Context()))
+
+	tasks := linked()
+	require.Len(t, tasks, 1, "the drifted task isn't duplicated")
+	assert.Equal(t, created[0].ID, tasks[0].ID)
+	assert.Equal(t, work.ID, tasks[0].ProjectID, "the drifted task is moved back")
+	stayed, ok := todoistSrv.Task(other.ID)
+	require.True(t, ok)
+	assert.Equal(t, home.ID, stayed.ProjectID, "tasks linked to other jira projects stay put")
+}
+
+func TestEngineDirection(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		direction string
+		resolver  ConflictResolver
+		want      Direction
+	}{
+		{direction: config.SyncDirectionBidirectional, resolver: TodoistWinsResolver{}, want: DirTodoistToJira},
+		{direction: "", resolver: JiraWinsResolver{}, want: DirJiraToTodoist},
+		{direction: config.SyncDirectionJiraToTodoist, resolver: TodoistWinsResolver{}, want: DirJiraToTodoist},
+		{direction: config.SyncDirectionTodoistToJira, resolver: JiraWinsResolver{}, want: DirTodoistToJira},
+	}
+	for _, tt := range tests {
+		e := &Engine{cfg: &config.Config{SyncDirection: tt.direction}, resolver: tt.resolver}
+		assert.Equal(t, tt.want, e.direction(&todoist.Task{}, &jira.Issue{Fields: &jira.IssueFields{}}), tt.direction)
+	}
+
+	var summary SyncSummary
+	e := &Engine{cfg: &config.Config{SyncDirection: config.SyncDirectionJiraToTodoist}, dryRun: true}
+	task := &todoist.Task{Content: "new", Labels: []string{linkLabel}}
+	require.NoError(t, e.createJiraFromTodoist(t.Context(), task, BuildSectionMap(nil), &summary))
+	assert.Empty(t, summary.CreatedJira, "jira-to-todoist never creates jira issues")
+
+	e.cfg.SyncDirection = config.SyncDirectionTodoistToJira
+	issue := &jira.Issue{Key: "PROJ-1", Fields: &jira.IssueFields{Summary: "new"}}
+	require.NoError(t, e.createTodoistFromJira(t.Context(), issue, "1", BuildSectionMap(nil), &summary))
+	assert.Empty(t, summary.CreatedTodoist, "todoist-to-jira never creates todoist tasks")
+}
+
+func TestSyncLinkedPairResolvedTodoistToJira(t *testing.T) {
+	t.Parallel()
+
+	todoistSrv := testserver.NewTodoist(t)
+	jiraSrv := testserver.NewJira(t)
+	e, err := NewEngine(
+		WithTodoistClient(testserver.TodoistClient(t, todoistSrv)),
+		WithJiraClient(testserver.JiraClient(t, jiraSrv)),
+		WithConfig(&config.Config{JiraURL: jiraSrv.URL, SyncDirection: config.SyncDirectionTodoistToJira}),
+		WithStateStore(newMemoryStateStore()),
+	)
+	require.NoError(t, err)
+
+	project := todoistSrv.AddProject("Work")
+	issue := jiraSrv.AddIssue(jira.IssueFields{
+		Project:    &jira.Project{Key: "PROJ"},
+		Summary:    "Resolved",
+		Resolution: &jira.Resolution{Name: "Done"},
+	})
+	task := todoistSrv.AddTask(todoist.Task{
+		ProjectID: project.ID,
+		Content:   PrependJiraLink("Edited in Todoist", issue.Key, jiraSrv.URL),
+	})
+
+	var summary SyncSummary
+	require.NoError(t, e.syncLinkedPair(t.Context(), &task, &issue, project.ID, BuildSectionMap(nil), &summary))
+	assert.Empty(t, summary.UpdatedToJira)
+	assert.Empty(t, summary.CompletedTodoist)
+	synced, ok := jiraSrv.Issue(issue.Key)
+	require.True(t, ok)
+	assert.Equal(t, "Resolved", synced.Fields.Summary, "resolved issue isn't overwritten")
+	after, ok := todoistSrv.Task(task.ID)
+	require.True(t, ok)
+	assert.False(t, after.Checked, "todoist task isn't closed")
+}
+
+func TestRunSkipsRecurringTasks(t *testing.T) {
+	t.Parallel()
+
+	todoistSrv := testserver.NewTodoist(t)
+	jiraSrv := testserver.NewJira(t)
+	cfg := &config.Config{
+		TodoistProject:     "Work",
+		JiraProject:        "PROJ",
+		JiraURL:            jiraSrv.URL,
+		SkipRecurringTasks: true,
+		RecurringTaskLabel: config.DefaultRecurringTaskLabel,
+	}
+	e, err := NewEngine(
+		WithTodoistClient(testserver.TodoistClient(t, todoistSrv)),
+		WithJiraClient(testserver.JiraClient(t, jiraSrv)),
+		WithConfig(cfg),
+		WithStateStore(newMemoryStateStore()),
+	)
+	require.NoError(t, err)
+	e.SetSummaryOutput(io.Discard)
+
+	project := todoistSrv.AddProject(cfg.TodoistProject)
+	weekly := &todoist.Due{String: "every monday", Date: "2026-03-02", IsRecurring: true}
+	recurring := todoistSrv.AddTask(todoist.Task{
+		ProjectID: project.ID, Content: "Standup notes", Labels: []string{linkLabel}, Due: weekly,
+	})
+	optedIn := todoistSrv.AddTask(todoist.Task{
+		ProjectID: project.ID,
+		Content:   "Release checklist",
+		Labels:    []string{linkLabel, config.DefaultRecurringTaskLabel},
+		Due:       weekly,
+	})
+	oneOff := todoistSrv.AddTask(todoist.Task{ProjectID: project.ID, Content: "Fix login", Labels: []string{linkLabel}})
+
+	require.NoError(t, e.Run(t.Context()))
+
+	for _, tt := range []struct {
+		id         string
+		wantLinked bool
+	}{
+		{id: recurring.ID},
+		{id: optedIn.ID, wantLinked: true},
+		{id: oneOff.ID, wantLinked: true},
+	} {
+		task, ok := todoistSrv.Task(tt.id)
+		require.True(t, ok)
+		assert.Equal(t, tt.wantLinked, ExtractJiraKey(task.Content) != "", task.Content)
+	}
+}
+
+func TestTodoistSection(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		StatusMap:           map[string]string{"In Progress": "Doing"},
+		ComponentSectionMap: map[string]string{"frontend": "Web", "Backend": "API"},
+	}
+	inProgress := &jira.Status{Name: "In Progress"}
+	tests := []struct {
+		name        string
+		components  []string
+		status      *jira.Status
+		wantSection string
+	}{
+		{name: "status", status: inProgress, wantSection: "Doing"},
+		{name: "unmapped component", components: []string{"Docs"}, status: inProgress, wantSection: "Doing"},
+		{name: "component", components: []string{"Frontend"}, status: inProgress, wantSection: "Web"},
+		{name: "several components", components: []string{"Frontend", "Backend"}, wantSection: "API"},
+		{name: "no status"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := &Engine{cfg: cfg, logger: zerolog.Nop()}
+			issue := &jira.Issue{Key: "PROJ-1", Fields: &jira.IssueFields{Status: tt.status}}
+			for _, name := range tt.components {
+				issue.Fields.Components = append(issue.Fields.Components, jira.Component{Name: name})
+			}
+			assert.Equal(t, tt.wantSection, e.todoistSection(issue))
+		})
+	}
+}
+
+func TestTodoistDueDate(t *testing.T) {
+	t.Parallel()
+
+	task := &todoist.Task{Due: &todoist.Due{Date: "2025-01-10"}, Deadline: &todoist.Deadline{Date: "2025-01-20"}}
+
+	e := &Engine{cfg: &config.Config{TodoistDueDateField: config.TodoistDueDateFieldDue}}
+	assert.Equal(t, "2025-01-10", e.todoistDueDate(task))
+	e = &Engine{cfg: &config.Config{TodoistDueDateField: config.TodoistDueDateFieldDeadline}}
+	assert.Equal(t, "2025-01-20", e.todoistDueDate(task))
+	assert.Empty(t, e.todoistDueDate(&todoist.Task{Due: &todoist.Due{Date: "2025-01-10"}}))
+}
+
+func TestTodoistLabels(t *testing.T) {
+	t.Parallel()
+
+	issue := &jira.Issue{Fields: &jira.IssueFields{Labels: []string{"backend", "urgent"}}}
+	assert.Equal(t, []string{linkLabel, "backend", "urgent"}, todoistLabels(nil, issue))
+	assert.Equal(t,
+		[]string{"mine", linkLabel, "urgent", "backend"},
+		todoistLabels([]string{"mine", linkLabel, "urgent"}, issue),
+	)
+	assert.Equal(t, []string{linkLabel}, todoistLabels(nil, &jira.Issue{Fields: &jira.IssueFields{}}))
+	assert.Equal(t, []string{linkLabel, "backend", "urgent", "v:2.1.0"}, todoistLabels(nil, issue, "v:2.1.0", "urgent"))
+}
+
+func TestFixVersionLabels(t *testing.T) {
+	t.Parallel()
+
+	issue := &jira.Issue{Fields: &jira.IssueFields{FixVersions: []jira.Version{{Name: "2.1.0"}, {Name: "2.2.0"}}}}
+	e := &Engine{cfg: &config.Config{FixVersionLabelPrefix: "v:"}}
+	assert.Empty(t, e.fixVersionLabels(issue))
+
+	e.cfg.SyncFixVersions = true
+	assert.Equal(t, []string{"v:2.1.0", "v:2.2.0"}, e.fixVersionLabels(issue))
+}
+
+func TestFinishSyncSummaryOutput(t *testing.T) {
+	t.Parallel()
+
+	logPath := filepath.Join(t.TempDir(), "summary.jsonl")
+	e := &Engine{
+		cfg:    &config.Config{SummaryLogFile: logPath},
+		logger: zerolog.Nop(),
+		state:  newMemoryStateStore(),
+	}
+	var out bytes.Buffer
+	e.SetSummaryOutput(&out)
+
+	start := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	finished := start.Add(2 * time.Second)
+	e.clock = FixedClock(finished)
+	created := SyncSummary{CreatedJira: []SyncAction{{JiraKey: "PROJ-1", Summary: "first"}}}
+	e.finishSync(context.Background(), start, created)
+	e.finishSync(context.Background(), start, SyncSummary{})
+	assert.Contains(t, out.String(), "[PROJ-1] first")
+	assert.Contains(t, out.String(), "Everything is up to date.")
+	assert.Contains(t, out.String(), "Completed in 2s")
+
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	var logged SyncSummary
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &logged))
+	assert.Equal(t, []SyncAction{{JiraKey: "PROJ-1", Summary: "first"}}, logged.CreatedJira)
+	assert.True(t, logged.StartedAt.Equal(start))
+	assert.True(t, logged.FinishedAt.Equal(finished))
+}
+
+type recordingEventHandler struct {
+	NopEventHandler
+	events []string
+}
+
+func (h *recordingEventHandler) OnSyncStart(_ context.Context, projectPair string) {
+	h.events = append(h.events, "start "+projectPair)
+}
+
+func (h *recordingEventHandler) OnItemCreated(_ context.Context, action SyncAction) {
+	h.events = append(h.events, "created "+action.JiraKey)
+}
+
+func (h *recordingEventHandler) OnItemUpdated(_ context.Context, action SyncAction) {
+	h.events = append(h.events, "updated "+action.JiraKey)
+}
+
+func (h *recordingEventHandler) OnItemCompleted(_ context.Context, action SyncAction) {
+	h.events = append(h.events, "completed "+action.JiraKey)
+}
+
+func (h *recordingEventHandler) OnSyncComplete(_ context.Context, summary SyncSummary, duration time.Duration) {
+	h.events = append(h.events, fmt.Sprintf("complete errors=%d", len(summary.Errors)))
+	if duration < 0 {
+		h.events = append(h.events, "negative duration")
+	}
+}
+
+func (h *recordingEventHandler) OnError(_ context.Context, action SyncAction, err error) {
+	h.events = append(h.events, fmt.Sprintf("error %s: %t", action.JiraKey, err != nil))
+}
+
+func TestSyncEventHandler(t *testing.T) {
+	t.Parallel()
+
+	linkedTask := `{"id":"1","project_id":"p1","content":"[PROJ-1](https://jira.example.com/browse/PROJ-1) Old"}`
+	tests := []struct {
+		name       string
+		tasks      string
+		resolution string
+		updateCode int
+		wantErr    bool
+		want       []string
+	}{
+		{
+			name:  "created",
+			tasks: `[]`,
+			want:  []string{"start Work/PROJ", "created PROJ-1", "complete errors=0"},
+		},
+		{
+			name:  "updated",
+			tasks: "[" + linkedTask + "]",
+			want:  []string{"start Work/PROJ", "updated PROJ-1", "complete errors=0"},
+		},
+		{
+			name:       "completed",
+			tasks:      "[" + linkedTask + "]",
+			resolution: `,"resolution":{"name":"Done"}`,
+			want:       []string{"start Work/PROJ", "completed PROJ-1", "complete errors=0"},
+		},
+		{
+			name:       "error",
+			tasks:      "[" + linkedTask + "]",
+			updateCode: http.StatusInternalServerError,
+			wantErr:    true,
+			want:       []string{"start Work/PROJ", "error PROJ-1: true", "complete errors=1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			todoistSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.URL.Path == "/projects":
+					_, _ = w.Write([]byte(`{"results":[{"id":"p1","name":"Work"}]}`))
+				case r.URL.Path == "/sections" && r.Method == http.MethodGet:
+					_, _ = w.Write([]byte(`{"results":[{"id":"s1","project_id":"p1","name":"To Do"}]}`))
+				case r.URL.Path == "/tasks" && r.Method == http.MethodGet:
+					_, _ = w.Write([]byte(`{"results":` + tt.tasks + `}`))
+				case r.URL.Path == "/tasks" && r.Method == http.MethodPost:
+					_, _ = w.Write([]byte(`{"id":"2","project_id":"p1","content":"new"}`))
+				case r.URL.Path == "/tasks/1/close":
+					w.WriteHeader(http.StatusNoContent)
+				case r.URL.Path == "/tasks/1" && tt.updateCode != 0:
+					w.WriteHeader(tt.updateCode)
+				case r.URL.Path == "/tasks/1":
+					_, _ = w.Write([]byte(linkedTask))
+				default:
+					w.WriteHeader(http.StatusOK)
+					_, _ = w.Write([]byte(`{}`))
+				}
+			}))
+			t.Cleanup(todoistSrv.Close)
+			jiraSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/search/jql", r.URL.Path)
+				assert.Contains(t, r.URL.Query().Get("jql"), `key IN ("PROJ-1")`)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"isLast":true,"issues":[{"key":"PROJ-1","fields":{"summary":"New",` +
+					`"status":{"name":"To Do"},"customfield_10020":[{"state":"active"}]` + tt.resolution + `}}]}`))
+			}))
+			t.Cleanup(jiraSrv.Close)
+
+			cfg := &config.Config{
+				TodoistProject: "Work",
+				JiraProject:    "PROJ",
+				JiraURL:        "https://jira.example.com",
+			}
+			tc := todoist.NewClient("", zerolog.Nop(), todoist.WithBaseURL(todoistSrv.URL), todoist.WithMaxRetries(0))
+			jc, err := jira.NewClient(cfg, zerolog.Nop(), jira.WithBaseURL(jiraSrv.URL), jira.WithMaxRetries(0))
+			require.NoError(t, err)
+			handler := &recordingEventHandler{}
+			e, err := NewEngine(
+				WithTodoistClient(tc),
+				WithJiraClient(jc),
+				WithConfig(cfg),
+				WithConflictResolver(JiraWinsResolver{}),
+				WithEventHandler(handler),
+			)
+			require.NoError(t, err)
+			e.SetSummaryOutput(io.Discard)
+
+			err = e.SyncIssue(context.Background(), "PROJ-1")
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, handler.events)
+		})
+	}
+}
+
+func TestPushSkipsNoOpUpdates(t *testing.T) {
+	t.Parallel()
+
+	todoistSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/comments" {
+			t.Errorf("unexpected todoist request %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results":[]}`))
+	}))
+	t.Cleanup(todoistSrv.Close)
+	jiraSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected jira request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(jiraSrv.Close)
+
+	cfg := &config.Config{JiraURL: "https://jira.example.com"}
+	tc := todoist.NewClient("", zerolog.Nop(), todoist.WithBaseURL(todoistSrv.URL), todoist.WithMaxRetries(0))
+	jc, err := jira.NewClient(cfg, zerolog.Nop(), jira.WithBaseURL(jiraSrv.URL), jira.WithMaxRetries(0))
+	require.NoError(t, err)
+	e, err := NewEngine(WithTodoistClient(tc), WithJiraClient(jc), WithConfig(cfg))
+	require.NoError(t, err)
+
+	issue := &jira.Issue{Key: "PROJ-1", Fields: &jira.IssueFields{
+		Summary:     "Write docs",
+		Description: jira.TextToADF("All of them"),
+		Duedate:     "2026-01-02",
+	}}
+	task := &todoist.Task{
+		ID:          "1",
+		Content:     PrependJiraLink("Write docs", "PROJ-1", cfg.JiraURL),
+		Description: "All of them",
+		Due:         &todoist.Due{Date: "2026-01-02"},
+		Labels:      todoistLabels(nil, issue),
+	}
+	ctx := context.Background()
+
+	diff, err := e.pushTodoistToJira(ctx, task, issue, BuildSectionMap(nil))
+	require.NoError(t, err)
+	assert.True(t, diff.empty())
+	diff, err = e.pushJiraToTodoist(ctx, task, issue, "p1", BuildSectionMap(nil))
+	require.NoError(t, err)
+	assert.True(t, diff.empty())
+}
+
+func TestPushSkipsUnchangedContent(t *testing.T) {
+	t.Parallel()
+
+	jiraSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected jira request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(jiraSrv.Close)
+	todoistSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results":[]}`))
+	}))
+	t.Cleanup(todoistSrv.Close)
+
+	cfg := &config.Config{JiraURL: "https://jira.example.com"}
+	tc := todoist.NewClient("", zerolog.Nop(), todoist.WithBaseURL(todoistSrv.URL), todoist.WithMaxRetries(0))
+	jc, err := jira.NewClient(cfg, zerolog.Nop(), jira.WithBaseURL(jiraSrv.URL), jira.WithMaxRetries(0))
+	require.NoError(t, err)
+	state := newMemoryStateStore()
+	e, err := NewEngine(WithTodoistClient(tc), WithJiraClient(jc), WithConfig(cfg), WithStateStore(state))
+	require.NoError(t, err)
+
+	task := &todoist.Task{ID: "1", Content: "Write docs"}
+	issue := &jira.Issue{Key: "PROJ-1", Fields: &jira.IssueFields{Summary: "Edited in Jira"}}
+	state.Set("1:content_hash", TaskContentHash(task))
+
+	diff, err := e.pushTodoistToJira(context.Background(), task, issue, BuildSectionMap(nil))
+	require.NoError(t, err)
+	assert.True(t, diff.empty())
+	got, ok := state.Get("1:content_hash")
+	assert.True(t, ok)
+	assert.Equal(t, TaskContentHash(task), got)
+}
+
+func TestSummaryPrintSkipped(t *testing.T) {
+	t.Parallel()
+
+	e := &Engine{cfg: &config.Config{}}
+	var summary SyncSummary
+	e.recordSkipped(&summary, SyncAction{JiraKey: "PROJ-1", Summary: "quiet"})
+	assert.Empty(t, summary.Skipped, "only recorded in verbose mode")
+
+	e.cfg.Verbose = true
+	e.recordSkipped(&summary, SyncAction{JiraKey: "PROJ-1", Summary: "quiet"})
+	summary.UpdatedToJira = []SyncAction{
+		{JiraKey: "PROJ-2", Summary: "busy", Changed: []string{"summary"}, Diff: "summary"},
+	}
+	var out bytes.Buffer
+	summary.print(&out)
+	assert.Contains(t, out.String(), "Skipped (no changes) (1):\n  - [PROJ-1] quiet\n")
+	assert.Contains(t, out.String(), "  - [PROJ-2] busy (changed: summary)\n")
+	assert.Less(t, strings.Index(out.String(), "[PROJ-2] busy"), strings.Index(out.String(), "[PROJ-1] quiet"))
+
+	data, err := json.Marshal(summary.clone())
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"skipped":[{"jira_key":"PROJ-1","summary":"quiet"}]`)
+	assert.Contains(t, string(data), `{"jira_key":"PROJ-2","summary":"busy","changed_fields":["summary"]}`)
+}
+
+func TestSyncSummaryJSONKeys(t *testing.T) {
+	t.Parallel()
+
+	action := SyncAction{
+		JiraKey:     "PROJ-1",
+		Summary:     "first",
+		Changed:     []string{"summary"},
+		Diff:        "summary",
+		ProjectPair: "Work/PROJ",
+	}
+	data, err := json.Marshal(SyncSummary{CreatedJira: []SyncAction{action}, DryRun: true})
+	require.NoError(t, err)
+
+	var summary map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &summary))
+	assert.ElementsMatch(t, []string{
+		"started_at", "finished_at", "created_jira", "created_todoist", "updated_to_todoist", "updated_to_jira",
+		"completed_todoist", "resolved_jira", "errors", "skipped", "dry_run",
+	}, slices.Collect(maps.Keys(summary)))
+
+	var actions []map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(summary["created_jira"], &actions))
+	require.Len(t, actions, 1)
+	assert.ElementsMatch(t, []string{"jira_key", "summary", "changed_fields", "project_pair"},
+		slices.Collect(maps.Keys(actions[0])))
+}
+
+func TestSummaryPrintProjectPairs(t *testing.T) {
+	t.Parallel()
+
+	ops := SyncSummary{CreatedJira: []SyncAction{{JiraKey: "OPS-1", Summary: "page"}}}
+	ops.setProjectPair("Ops/OPS")
+	dev := SyncSummary{CreatedJira: []SyncAction{
+		{JiraKey: "DEV-1", Summary: "bug"},
+		{JiraKey: "DEV-2", Summary: "feature"},
+	}}
+	dev.setProjectPair("Dev/DEV")
+
+	var summary SyncSummary
+	summary.add(ops)
+	summary.add(dev)
+	require.Len(t, summary.CreatedJira, 3)
+
+	var out bytes.Buffer
+	summary.print(&out)
+	assert.Contains(t, out.String(), "Created in Jira [Ops/OPS] (1):\n  - [OPS-1] page\n")
+	assert.Contains(t, out.String(), "Created in Jira [Dev/DEV] (2):\n  - [DEV-1] bug\n  - [DEV-2] feature\n")
+}
+
+func TestRetryQueueTake(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewFileStateStore(filepath.Join(t.TempDir(), "state.json"))
+	require.NoError(t, err)
+	q := &retryQueue{store: store, logger: zerolog.Nop()}
+	q.add(retryItem{Kind: retryCreateJira, TaskID: "1", Pair: "Ops/OPS"})
+	q.add(retryItem{Kind: retryCreateJira, TaskID: "2", Pair: "Dev/DEV"})
+
+	taken := q.take("Ops/OPS")
+	require.Len(t, taken, 1)
+	assert.Equal(t, "1", taken[0].TaskID)
+	assert.Empty(t, q.take("Ops/OPS"))
+	items, err := q.items()
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "Dev/DEV", items[0].Pair)
+}
+
+func TestRetryQueueKeepsCorruptState(t *testing.T) {
+	t.Parallel()
+
+	const corrupt = `[{"kind":"create_jira","task_id":"1"`
+	store := newMemoryStateStore()
+	store.Set(retryQueueStateKey, corrupt)
+	q := &retryQueue{store: store, logger: zerolog.Nop()}
+
+	_, err := q.items()
+	require.Error(t, err)
+	q.add(retryItem{Kind: retryCreateJira, TaskID: "2"})
+	assert.Empty(t, q.take(""))
+	raw, ok := store.Get(retryQueueStateKey)
+	require.True(t, ok)
+	assert.Equal(t, corrupt, raw, "corrupt queue isn't overwritten")
+}
+
+// countingTransport counts the requests for each path.
+type countingTransport struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (c *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	c.counts[r.Method+" "+r.URL.Path]++
+	c.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestProcessRetryQueueFetchesTasksOnce(t *testing.T) {
+	t.Parallel()
+
+	todoistSrv := testserver.NewTodoist(t)
+	jiraSrv := testserver.NewJira(t)
+	cfg := &config.Config{TodoistProject: "Work", JiraProject: "PROJ", JiraURL: jiraSrv.URL, MaxRetry: 3}
+	counter := &countingTransport{counts: make(map[string]int)}
+	todoistClient := todoist.NewClient("test-token", zerolog.Nop(),
+		todoist.WithBaseURL(todoistSrv.URL),
+		todoist.WithHTTPClient(&http.Client{Transport: counter}),
+		todoist.WithMaxRetries(0),
+	)
+	e, err := NewEngine(
+		WithTodoistClient(todoistClient),
+		WithJiraClient(testserver.JiraClient(t, jiraSrv)),
+		WithConfig(cfg),
+		WithStateStore(newMemoryStateStore()),
+	)
+	require.NoError(t, err)
+
+	project := todoistSrv.AddProject(cfg.TodoistProject)
+	for _, summary := range []string{"first", "second", "third"} {
+		issue := jiraSrv.AddIssue(jira.IssueFields{Project: &jira.Project{Key: "PROJ"}, Summary: summary})
+		todoistSrv.AddTask(todoist.Task{
+			ProjectID: project.ID,
+			Content:   PrependJiraLink(summary, issue.Key, jiraSrv.URL),
+			Labels:    []string{linkLabel},
+		})
+		e.queueRetry(retryItem{Kind: retrySyncPair, JiraKey: issue.Key, Summary: summary})
+	}
+
+	var summary SyncSummary
+	e.processRetryQueue(t.Context(), &summary)
+	assert.Empty(t, summary.Errors)
+	assert.Empty(t, e.retryQueue.take(""), "retried items succeeded")
+	assert.Equal(t, 1, counter.counts["GET /tasks"])
+}
+
+func TestProcessRetryQueue(t *testing.T) {
+	t.Parallel()
+
+	todoistSrv := testserver.NewTodoist(t)
+	jiraSrv := testserver.NewJira(t)
+	cfg := &config.Config{TodoistProject: "Work", JiraProject: "PROJ", JiraURL: jiraSrv.URL, MaxRetry: 2}
+	e, err := NewEngine(
+		WithTodoistClient(testserver.TodoistClient(t, todoistSrv)),
+		WithJiraClient(testserver.JiraClient(t, jiraSrv)),
+		WithConfig(cfg),
+		WithStateStore(newMemoryStateStore()),
+	)
+	require.NoError(t, err)
+
+	project := todoistSrv.AddProject(cfg.TodoistProject)
+	linked := jiraSrv.AddIssue(jira.IssueFields{Project: &jira.Project{Key: "PROJ"}, Summary: "linked"})
+	todoistSrv.AddTask(todoist.Task{
+		ProjectID: project.ID,
+		Content:   PrependJiraLink("linked", linked.Key, jiraSrv.URL),
+		Labels:    []string{linkLabel},
+	})
+	other := jiraSrv.AddIssue(jira.IssueFields{Project: &jira.Project{Key: "OTHER"}, Summary: "other project"})
+	succeeds := retryItem{Kind: retrySyncPair, JiraKey: linked.Key}
+	filtered := retryItem{Kind: retryCreateTodoist, JiraKey: other.Key}
+	fails := retryItem{Kind: retryCreateJira, TaskID: "missing", Summary: "deleted task"}
+	for _, item := range []retryItem{succeeds, filtered, fails} {
+		e.queueRetry(item)
+	}
+
+	var summary SyncSummary
+	handled := e.processRetryQueue(t.Context(), &summary)
+	assert.Equal(t, map[string]bool{succeeds.id(): true, filtered.id(): true, fails.id(): true}, handled)
+	assert.Empty(t, summary.Errors)
+	assert.Empty(t, summary.CreatedTodoist, "issue outside the sync filters isn't created")
+	items, err := e.retryQueue.items()
+	require.NoError(t, err)
+	require.Len(t, items, 1, "only the failed item is requeued")
+	assert.Equal(t, fails.id(), items[0].id())
+	assert.Equal(t, 1, items[0].Attempts)
+
+	summary = SyncSummary{}
+	handled = e.processRetryQueue(t.Context(), &summary)
+	assert.Equal(t, map[string]bool{fails.id(): true}, handled)
+	require.Len(t, summary.Errors, 1, "gives up after MaxRetry attempts")
+	assert.Equal(t, "deleted task", summary.Errors[0].Summary)
+	assert.Empty(t, e.retryQueue.take(""))
+}
+
+// failingTransport fails the requests to one method and path.
+type failingTransport struct {
+	route  string
+	mu     sync.Mutex
+	counts int
+}
+
+func (f *failingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Method+" "+r.URL.Path != f.route {
+		return http.DefaultTransport.RoundTrip(r)
+	}
+	f.mu.Lock()
+	f.counts++
+	f.mu.Unlock()
+	return &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"errorMessages":["create failed"]}`)),
+		Request:    r,
+	}, nil
+}
+
+func TestRunSkipsRetriedItems(t *testing.T) {
+	t.Parallel()
+
+	todoistSrv := testserver.NewTodoist(t)
+	jiraSrv := testserver.NewJira(t)
+	cfg := &config.Config{TodoistProject: "Work", JiraProject: "PROJ", JiraURL: jiraSrv.URL, MaxRetry: 2}
+	failing := &failingTransport{route: "POST /issue"}
+	jiraClient, err := jira.NewClient(cfg, zerolog.Nop(),
+		jira.WithBaseURL(jiraSrv.URL),
+		jira.WithAgileBaseURL(jiraSrv.URL),
+		jira.WithHTTPClient(&http.Client{Transport: failing}),
+		jira.WithMaxRetries(0),
+	)
+	require.NoError(t, err)
+	e, err := NewEngine(
+		WithTodoistClient(testserver.TodoistClient(t, todoistSrv)),
+		WithJiraClient(jiraClient),
+		WithConfig(cfg),
+		WithStateStore(newMemoryStateStore()),
+	)
+	require.NoError(t, err)
+	e.SetSummaryOutput(io.Discard)
+
+	project := todoistSrv.AddProject(cfg.TodoistProject)
+	task := todoistSrv.AddTask(todoist.Task{ProjectID: project.ID, Content: "new", Labels: []string{linkLabel}})
+
+	require.NoError(t, e.Run(t.Context()))
+	assert.Equal(t, 1, failing.counts)
+	items, err := e.retryQueue.items()
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, 0, items[0].Attempts)
+
+	require.NoError(t, e.Run(t.Context()))
+	assert.Equal(t, 2, failing.counts, "the main loop skips the task its retry handled")
+	items, err = e.retryQueue.items()
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, task.ID, items[0].TaskID)
+	assert.Equal(t, 1, items[0].Attempts, "attempts are carried across cycles")
+
+	require.NoError(t, e.Run(t.Context()))
+	assert.Equal(t, 3, failing.counts)
+	summary := e.LastSummary()
+	require.Len(t, summary.Errors, 1)
+	assert.Empty(t, e.retryQueue.take(""), "gives up after MaxRetry attempts")
+}
+
+func TestSyncLinkedPairExcludedKey(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{ExcludeJiraKeys: []string{"PROJ-1"}}
+	jc, err := jira.NewClient(cfg, zerolog.Nop())
+	require.NoError(t, err)
+	e, err := NewEngine(WithTodoistClient(todoist.NewClient("", zerolog.Nop())), WithJiraClient(jc), WithConfig(cfg))
+	require.NoError(t, err)
+
+	task := &todoist.Task{ID: "1", Content: "[PROJ-1](https://jira.example.com/browse/PROJ-1) Epic"}
+	issue := &jira.Issue{Key: "PROJ-1", Fields: &jira.IssueFields{
+		Summary:    "Epic",
+		Resolution: &jira.Resolution{Name: "Done"},
+	}}
+	var summary SyncSummary
+	require.NoError(t, e.syncLinkedPair(context.Background(), task, issue, "p1", BuildSectionMap(nil), &summary))
+	assert.Empty(t, summary.CompletedTodoist)
+	assert.NoError(t, e.SyncIssue(context.Background(), "PROJ-1"))
+}
+
+func TestSyncLinkedPair(t *testing.T) {
+	t.Parallel()
+
+	const jiraURL = "https://jira.example.com"
+	linked := func(title, updatedAt string) todoist.Task {
+		return todoist.Task{
+			ID:        "1",
+			Content:   PrependJiraLink(title, "PROJ-1", jiraURL),
+			Labels:    []string{linkLabel},
+			UpdatedAt: updatedAt,
+		}
+	}
+	tests := []struct {
+		name          string
+		task          todoist.Task
+		issue         jira.IssueFields
+		dryRun        bool
+		wantWrites    []string
+		wantCompleted int
+		wantToTodoist int
+		wantToJira    int
+	}{
+		{
+			name:          "resolved issue closes task",
+			task:          linked("Old", "2026-01-01T00:00:00Z"),
+			issue:         jira.IssueFields{Summary: "New", Updated: "2026-01-02T00:00:00Z", Resolution: &jira.Resolution{}},
+			wantWrites:    []string{"POST /tasks/1/close"},
+			wantCompleted: 1,
+		},
+		{
+			name:          "jira newer pushes to todoist",
+			task:          linked("Old", "2026-01-01T00:00:00Z"),
+			issue:         jira.IssueFields{Summary: "New", Updated: "2026-01-02T00:00:00Z"},
+			wantWrites:    []string{"POST /tasks/1"},
+			wantToTodoist: 1,
+		},
+		{
+			name:       "todoist newer pushes to jira",
+			task:       linked("New", "2026-01-03T00:00:00Z"),
+			issue:      jira.IssueFields{Summary: "Old", Updated: "2026-01-02T00:00:00Z"},
+			wantWrites: []string{"PUT /issue/PROJ-1"},
+			wantToJira: 1,
+		},
+		{
+			name:       "unparseable todoist time assumes todoist newer",
+			task:       linked("New", "yesterday"),
+			issue:      jira.IssueFields{Summary: "Old", Updated: "2026-01-02T00:00:00Z"},
+			wantWrites: []string{"PUT /issue/PROJ-1"},
+			wantToJira: 1,
+		},
+		{
+			name:  "no changes",
+			task:  linked("Same", "2026-01-03T00:00:00Z"),
+			issue: jira.IssueFields{Summary: "Same", Updated: "2026-01-02T00:00:00Z"},
+		},
+		{
+			name:          "dry run",
+			task:          linked("Old", "2026-01-01T00:00:00Z"),
+			issue:         jira.IssueFields{Summary: "New", Updated: "2026-01-02T00:00:00Z"},
+			dryRun:        true,
+			wantToTodoist: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				mu     sync.Mutex
+				writes []string
+			)
+			record := func(r *http.Request) {
+				if r.Method != http.MethodGet {
+					mu.Lock()
+					writes = append(writes, r.Method+" "+r.URL.Path)
+					mu.Unlock()
+				}
+			}
+			todoistSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				record(r)
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/comments":
+					_, _ = w.Write([]byte(`{"results":[]}`))
+				case "/tasks/1":
+					_, _ = w.Write([]byte(`{"id":"1"}`))
+				default:
+					w.WriteHeader(http.StatusNoContent)
+				}
+			}))
+			t.Cleanup(todoistSrv.Close)
+			jiraSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				record(r)
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			t.Cleanup(jiraSrv.Close)
+
+			cfg := &config.Config{JiraURL: jiraURL}
+			tc := todoist.NewClient("", zerolog.Nop(), todoist.WithBaseURL(todoistSrv.URL), todoist.WithMaxRetries(0))
+			jc, err := jira.NewClient(cfg, zerolog.Nop(), jira.WithBaseURL(jiraSrv.URL), jira.WithMaxRetries(0))
+			require.NoError(t, err)
+			e, err := NewEngine(WithTodoistClient(tc), WithJiraClient(jc), WithConfig(cfg))
+			require.NoError(t, err)
+			e.dryRun = tt.dryRun
+
+			task, fields := tt.task, tt.issue
+			issue := &jira.Issue{Key: "PROJ-1", Fields: &fields}
+			var summary SyncSummary
+			require.NoError(t, e.syncLinkedPair(context.Background(), &task, issue, "p1", BuildSectionMap(nil), &summary))
+
+			assert.Equal(t, tt.wantWrites, writes)
+			assert.Len(t, summary.CompletedTodoist, tt.wantCompleted)
+			assert.Len(t, summary.UpdatedToTodoist, tt.wantToTodoist)
+			assert.Len(t, summary.UpdatedToJira, tt.wantToJira)
+			assert.Empty(t, summary.Errors)
+		})
+	}
+}
+
+func TestSprintEndAsDueDate(t *testing.T) {
+	t.Parallel()
+
+	activeSprint := json.RawMessage(`[{"id": 1, "state": "closed", "endDate": "2026-02-27T17:00:00.000Z"},
+		{"id": 2, "state": "active", "endDate": "2026-03-13T17:00:00.000Z"}]`)
+	tests := []struct {
+		name            string
+		useSprintEnd    bool
+		duedate         string
+		sprints         json.RawMessage
+		wantTodoistDate string
+	}{
+		{name: "sprint end", useSprintEnd: true, sprints: activeSprint, wantTodoistDate: "2026-03-13"},
+		{
+			name:            "explicit due date wins",
+			useSprintEnd:    true,
+			duedate:         "2026-03-06",
+			sprints:         activeSprint,
+			wantTodoistDate: "2026-03-06",
+		},
+		{name: "no active sprint", useSprintEnd: true, sprints: json.RawMessage(`[{"id": 1, "state": "future"}]`)},
+		{name: "disabled", sprints: activeSprint},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			todoistSrv := testserver.NewTodoist(t)
+			jiraSrv := testserver.NewJira(t)
+			cfg := &config.Config{JiraURL: jiraSrv.URL, UseSprintEndAsDueDate: tt.useSprintEnd}
+			e, err := NewEngine(
+				WithTodoistClient(testserver.TodoistClient(t, todoistSrv)),
+				WithJiraClient(testserver.JiraClient(t, jiraSrv)),
+				WithConfig(cfg),
+				WithStateStore(newMemoryStateStore()),
+			)
+			require.NoError(t, err)
+
+			project := todoistSrv.AddProject("Work")
+			issue := jiraSrv.AddIssue(jira.IssueFields{
+				Project:   &jira.Project{Key: "PROJ"},
+				Summary:   "Sprint work",
+				Duedate:   tt.duedate,
+				SprintRaw: tt.sprints,
+			})
+			var summary SyncSummary
+			require.NoError(t, e.createTodoistFromJira(t.Context(), &issue, project.ID, BuildSectionMap(nil), &summary))
+			require.Len(t, summary.CreatedTodoist, 1)
+
+			tasks, err := testserver.TodoistClient(t, todoistSrv).GetTasks(t.Context(), project.ID)
+			require.NoError(t, err)
+			require.Len(t, tasks, 1)
+			assert.Equal(t, tt.wantTodoistDate, tasks[0].DueDate())
+			assert.Equal(t, tt.wantTodoistDate, e.jiraDueDate(&issue))
+		})
+	}
+}
+
+// newBenchEngine returns an engine syncing fake APIs that hold 200 Jira issues,
+// 100 of them linked, and 500 Todoist tasks: the 100 linked ones, 200 with the
+// link label that need a Jira issue, and 200 that aren't synced. The returned
+// func shuts the fake APIs down.
+func newBenchEngine(b *testing.B) (*Engine, func()) {
+	b.Helper()
+
+	todoistSrv := testserver.NewTodoist(b)
+	jiraSrv := testserver.NewJira(b)
+	cfg := &config.Config{
+		TodoistProject: "Work",
+		JiraProject:    "PROJ",
+		JiraURL:        jiraSrv.URL,
+		SyncBacklog:    true,
+	}
+
+	project := todoistSrv.AddProject(cfg.TodoistProject)
+	for i := range 200 {
+		issue := jiraSrv.AddIssue(jira.IssueFields{
+			Project:     &jira.Project{Key: cfg.JiraProject},
+			Summary:     fmt.Sprintf("Issue %d", i),
+			Description: jira.TextToADF("benchmark issue"),
+			IssueType:   &jira.IssueType{Name: "Task"},
+		})
+		if i%2 == 0 {
+			todoistSrv.AddTask(todoist.Task{
+				ProjectID: project.ID,
+				Content:   PrependJiraLink(issue.Fields.Summary, issue.Key, cfg.JiraURL),
+				Labels:    []string{linkLabel},
+				Priority:  1,
+			})
+		}
+	}
+	for i := range 400 {
+		task := todoist.Task{ProjectID: project.ID, Content: fmt.Sprintf("Task %d", i), Priority: 1}
+		if i%2 == 0 {
+			task.Labels = []string{linkLabel}
+		}
+		todoistSrv.AddTask(task)
+	}
+
+	e, err := NewEngine(
+		WithTodoistClient(testserver.TodoistClient(b, todoistSrv)),
+		WithJiraClient(testserver.JiraClient(b, jiraSrv)),
+		WithConfig(cfg),
+	)
+	require.NoError(b, err)
+	e.SetSummaryOutput(io.Discard)
+	return e, func() {
+		todoistSrv.Close()
+		jiraSrv.Close()
+	}
+}
+
+func BenchmarkEngineRun(b *testing.B) {
+	if testing.Short() {
+		b.Skip("skipping benchmark in short mode")
+	}
+	b.ReportAllocs()
+
+	for range b.N {
+		b.StopTimer()
+		e, closeAPIs := newBenchEngine(b)
+		b.StartTimer()
+
+		if err := e.Run(context.Background()); err != nil {
+			b.Fatal(err)
+		}
+
+		b.StopTimer()
+		closeAPIs()
+		b.StartTimer()
+	}
+}
+
+func TestFieldLevelSync(t *testing.T) {
+	t.Parallel()
+
+	todoistSrv := testserver.NewTodoist(t)
+	jiraSrv := testserver.NewJira(t)
+	cfg := &config.Config{
+		TodoistProject: "Work",
+		JiraProject:    "PROJ",
+		JiraURL:        jiraSrv.URL,
+		SyncBacklog:    true,
+		FieldLevelSync: true,
+	}
+	e, err := NewEngine(
+		WithTodoistClient(testserver.TodoistClient(t, todoistSrv)),
+		WithJiraClient(testserver.JiraClient(t, jiraSrv)),
+		WithConfig(cfg),
+		WithStateStore(newMemoryStateStore()),
+		WithConflictResolver(TodoistWinsResolver{}),
+	)
+	require.NoError(t, err)
+	e.SetSummaryOutput(io.Discard)
+
+	project := todoistSrv.AddProject(cfg.TodoistProject)
+	issue := jiraSrv.AddIssue(jira.IssueFields{Project: &jira.Project{Key: "PROJ"}, Summary: "Original"})
+	require.NoError(t, e.Run(t.Context()))
+	require.NoError(t, e.Run(t.Context()))
+
+	todoistClient := testserver.TodoistClient(t, todoistSrv)
+	tasks, err := todoistClient.GetTasks(t.Context(), project.ID)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	desc := "Edited in Todoist"
+	_, err = todoistClient.UpdateTask(t.Context(), tasks[0].ID, todoist.UpdateTaskRequest{Description: &desc})
+	require.NoError(t, err)
+	require.NoError(t,
+		testserver.JiraClient(t, jiraSrv).UpdateIssue(t.Context(), issue.Key, jira.UpdateFields{Summary: "Edited in Jira"}),
+	)
+
+	require.NoError(t, e.Run(t.Context()))
+	synced, ok := jiraSrv.Issue(issue.Key)
+	require.True(t, ok)
+	assert.Equal(t, "Edited in Jira", synced.Fields.Summary, "the unchanged todoist summary isn't pushed")
+	assert.Equal(t, desc, jira.ADFToText(synced.Fields.Description))
+}
+
+func TestFieldLevelSyncSkippedUpdate(t *testing.T) {
+	t.Parallel()
+
+	todoistSrv := testserver.NewTodoist(t)
+	jiraSrv := testserver.NewJira(t)
+	e, err := NewEngine(
+		WithTodoistClient(testserver.TodoistClient(t, todoistSrv)),
+		WithJiraClient(testserver.JiraClient(t, jiraSrv)),
+		WithConfig(&config.Config{JiraURL: jiraSrv.URL, FieldLevelSync: true}),
+		WithStateStore(newMemoryStateStore()),
+	)
+	require.NoError(t, err)
+
+	project := todoistSrv.AddProject("Work")
+	issue := jiraSrv.AddIssue(jira.IssueFields{Project: &jira.Project{Key: "PROJ"}, Summary: "In Jira"})
+	task := todoistSrv.AddTask(todoist.Task{
+		ProjectID: project.ID,
+		Content:   PrependJiraLink("In Todoist", issue.Key, jiraSrv.URL),
+	})
+	e.recordContentHash(task.ID, TaskContentHash(&task))
+	e.recordContentHash(issue.Key, IssueContentHash(&issue))
+
+	diff, err := e.pushTodoistToJira(t.Context(), &task, &issue, BuildSectionMap(nil))
+	require.NoError(t, err)
+	assert.True(t, diff.empty())
+	diff, err = e.pushJiraToTodoist(t.Context(), &task, &issue, project.ID, BuildSectionMap(nil))
+	require.NoError(t, err)
+	assert.True(t, diff.empty())
+
+	synced, ok := jiraSrv.Issue(issue.Key)
+	require.True(t, ok)
+	assert.Equal(t, "In Jira", synced.Fields.Summary)
+	_, recorded := e.state.Get(fieldStateKey(task.ID, fieldSummary))
+	assert.False(t, recorded, "skipped updates don't record fields as synced")
+}
+
+func TestSyncIssueLinksOrder(t *testing.T) {
+	t.Parallel()
+
+	todoistSrv := testserver.NewTodoist(t)
+	jiraSrv := testserver.NewJira(t)
+	cfg := &config.Config{
+		TodoistProject: "Work",
+		JiraProject:    "PROJ",
+		JiraURL:        jiraSrv.URL,
+		SyncBacklog:    true,
+		SyncIssueLinks: true,
+	}
+	e, err := NewEngine(
+		WithTodoistClient(testserver.TodoistClient(t, todoistSrv)),
+		WithJiraClient(testserver.JiraClient(t, jiraSrv)),
+		WithConfig(cfg),
+		WithStateStore(newMemoryStateStore()),
+		WithConflictResolver(JiraWinsResolver{}),
+	)
+	require.NoError(t, err)
+	e.SetSummaryOutput(io.Discard)
+
+	blockedBy := func(key string) jira.IssueLink {
+		return jira.IssueLink{Type: jira.IssueLinkType{Name: "Blocks"}, InwardIssue: &jira.LinkedIssue{Key: key}}
+	}
+	project := todoistSrv.AddProject(cfg.TodoistProject)
+	blocker := jiraSrv.AddIssue(jira.IssueFields{Project: &jira.Project{Key: "PROJ"}, Summary: "Blocker"})
+	blocked := jiraSrv.AddIssue(jira.IssueFields{
+		Project:    &jira.Project{Key: "PROJ"},
+		Summary:    "Blocked",
+		IssueLinks: []jira.IssueLink{blockedBy(blocker.Key)},
+	})
+	order := func(key string) int {
+		t.Helper()
+		tasks, err := testserver.TodoistClient(t, todoistSrv).GetTasks(t.Context(), project.ID)
+		require.NoError(t, err)
+		task, ok := linkedTask(tasks, key)
+		require.True(t, ok)
+		return task.ChildOrder
+	}
+
+	require.NoError(t, e.Run(t.Context()))
+	assert.Equal(t, 1, order(blocker.Key))
+	assert.Equal(t, 2, order(blocked.Key), "blocked issues are created after unblocked ones")
+
+	other := jiraSrv.AddIssue(jira.IssueFields{Project: &jira.Project{Key: "PROJ"}, Summary: "Other"})
+	jiraSrv.SetIssueLinks(blocked.Key, blockedBy(blocker.Key), blockedBy(other.Key))
+	jiraSrv.SetIssueLinks(blocker.Key, blockedBy(other.Key))
+	require.NoError(t, e.Run(t.Context()))
+	assert.Equal(t, 1, order(other.Key))
+	assert.Equal(t, 2, order(blocker.Key), "newly blocked issues are moved down")
+	assert.Equal(t, 3, order(blocked.Key))
+}
+
+func TestDryRun(t *testing.T) {
+	t.Parallel()
+
+	todoistSrv := testserver.NewTodoist(t)
+	jiraSrv := testserver.NewJira(t)
+	cfg := &config.Config{
+		TodoistProject: "Work",
+		JiraProject:    "PROJ",
+		JiraURL:        jiraSrv.URL,
+		SyncBacklog:    true,
+	}
+	e, err := NewEngine(
+		WithTodoistClient(testserver.TodoistClient(t, todoistSrv)),
+		WithJiraClient(testserver.JiraClient(t, jiraSrv)),
+		WithConfig(cfg),
+		WithStateStore(newMemoryStateStore()),
+		// The fake APIs' timestamps are too close together to tell which side is newer.
+		WithConflictResolver(JiraWinsResolver{}),
+	)
+	require.NoError(t, err)
+	e.SetSummaryOutput(io.Discard)
+
+	project := todoistSrv.AddProject(cfg.TodoistProject)
+	inSync := jiraSrv.AddIssue(jira.IssueFields{Project: &jira.Project{Key: "PROJ"}, Summary: "In sync"})
+	changed := jiraSrv.AddIssue(jira.IssueFields{Project: &jira.Project{Key: "PROJ"}, Summary: "Changed"})
+	require.NoError(t, e.Run(t.Context()))
+
+	jiraClient := testserver.JiraClient(t, jiraSrv)
+	require.NoError(t, jiraClient.UpdateIssue(t.Context(), changed.Key, jira.UpdateFields{Summary: "Changed in Jira"}))
+	unlinked := jiraSrv.AddIssue(jira.IssueFields{Project: &jira.Project{Key: "PROJ"}, Summary: "New issue"})
+	newTask := todoistSrv.AddTask(todoist.Task{ProjectID: project.ID, Content: "New task", Labels: []string{linkLabel}})
+	tasksBefore, err := testserver.TodoistClient(t, todoistSrv).GetTasks(t.Context(), project.ID)
+	require.NoError(t, err)
+
+	require.NoError(t, e.DryRun(t.Context()))
+
+	summary := e.LastSummary()
+	assert.True(t, summary.DryRun)
+	require.Len(t, summary.UpdatedToTodoist, 1, "only the pair that would change is reported")
+	assert.Equal(t, changed.Key, summary.UpdatedToTodoist[0].JiraKey)
+	assert.Equal(t, []string{fieldSummary}, summary.UpdatedToTodoist[0].Changed)
+	assert.Empty(t, summary.UpdatedToJira)
+	assert.NotContains(t, summary.UpdatedToTodoist, SyncAction{JiraKey: inSync.Key, Summary: inSync.Fields.Summary})
+	require.Len(t, summary.CreatedTodoist, 1)
+	assert.Equal(t, unlinked.Key, summary.CreatedTodoist[0].JiraKey)
+	require.Len(t, summary.CreatedJira, 1)
+	assert.Equal(t, newTask.Content, summary.CreatedJira[0].Summary)
+
+	tasksAfter, err := testserver.TodoistClient(t, todoistSrv).GetTasks(t.Context(), project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, tasksBefore, tasksAfter, "dry run doesn't write to todoist")
+	issues, err := jiraClient.SearchIssuesPaginated(t.Context(), "project = PROJ", nil)
+	require.NoError(t, err)
+	assert.Len(t, issues, 3, "dry run doesn't create jira issues")
+}
@@ -0,0 +1,60 @@
+package syncer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kalverra/todoist-jira-sync/jira"
+	"github.com/kalverra/todoist-jira-sync/todoist"
+)
+
+// daysEstimatePattern matches a Jira estimate made of whole days only, e.g. "2d".
+var daysEstimatePattern = regexp.MustCompile(`^(\d+)d$`)
+
+// jiraEstimate converts a Todoist duration to a Jira time estimate, e.g. 90
+// minutes to "1h 30m" or 2 days to "2d". Returns "" for no duration.
+func jiraEstimate(d *todoist.Duration) string {
+	if d == nil || d.Amount <= 0 {
+		return ""
+	}
+	if d.Unit == todoist.DurationUnitDay {
+		return fmt.Sprintf("%dd", d.Amount)
+	}
+	var parts []string
+	if hours := d.Amount / 60; hours > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", hours))
+	}
+	if minutes := d.Amount % 60; minutes > 0 {
+		parts = append(parts, fmt.Sprintf("%dm", minutes))
+	}
+	return strings.Join(parts, " ")
+}
+
+// todoistDuration converts a Jira original estimate to a Todoist duration.
+// Estimates of whole days stay in days, since Jira days are working days,
+// anything else is converted to minutes. Returns nil for no estimate.
+func todoistDuration(tt *jira.TimeTracking) *todoist.Duration {
+	if tt == nil {
+		return nil
+	}
+	if m := daysEstimatePattern.FindStringSubmatch(tt.OriginalEstimate); m != nil {
+		days, err := strconv.Atoi(m[1])
+		if err == nil && days > 0 {
+			return &todoist.Duration{Amount: days, Unit: todoist.DurationUnitDay}
+		}
+	}
+	if minutes := tt.OriginalEstimateSeconds / 60; minutes > 0 {
+		return &todoist.Duration{Amount: minutes, Unit: todoist.DurationUnitMinute}
+	}
+	return nil
+}
+
+// currentEstimate returns the issue's original estimate, or "" if it has none.
+func currentEstimate(issue *jira.Issue) string {
+	if issue.Fields.TimeTracking == nil {
+		return ""
+	}
+	return issue.Fields.TimeTracking.OriginalEstimate
+}
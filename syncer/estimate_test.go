@@ -0,0 +1,73 @@
+package syncer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kalverra/todoist-jira-sync/jira"
+	"github.com/kalverra/todoist-jira-sync/todoist"
+)
+
+func TestJiraEstimate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		duration *todoist.Duration
+		want     string
+	}{
+		{name: "none"},
+		{name: "zero", duration: &todoist.Duration{Amount: 0, Unit: todoist.DurationUnitMinute}},
+		{name: "minutes", duration: &todoist.Duration{Amount: 45, Unit: todoist.DurationUnitMinute}, want: "45m"},
+		{name: "hours", duration: &todoist.Duration{Amount: 120, Unit: todoist.DurationUnitMinute}, want: "2h"},
+		{
+			name:     "hours and minutes",
+			duration: &todoist.Duration{Amount: 90, Unit: todoist.DurationUnitMinute},
+			want:     "1h 30m",
+		},
+		{name: "days", duration: &todoist.Duration{Amount: 2, Unit: todoist.DurationUnitDay}, want: "2d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, jiraEstimate(tt.duration))
+		})
+	}
+}
+
+func TestTodoistDuration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		timeTracking *jira.TimeTracking
+		want         *todoist.Duration
+	}{
+		{name: "none"},
+		{name: "no estimate", timeTracking: &jira.TimeTracking{TimeSpent: "1h", TimeSpentSeconds: 3600}},
+		{
+			name:         "hours and minutes",
+			timeTracking: &jira.TimeTracking{OriginalEstimate: "1h 30m", OriginalEstimateSeconds: 5400},
+			want:         &todoist.Duration{Amount: 90, Unit: todoist.DurationUnitMinute},
+		},
+		{
+			name:         "whole days",
+			timeTracking: &jira.TimeTracking{OriginalEstimate: "2d", OriginalEstimateSeconds: 57600},
+			want:         &todoist.Duration{Amount: 2, Unit: todoist.DurationUnitDay},
+		},
+		{
+			name:         "days and hours",
+			timeTracking: &jira.TimeTracking{OriginalEstimate: "1d 4h", OriginalEstimateSeconds: 43200},
+			want:         &todoist.Duration{Amount: 720, Unit: todoist.DurationUnitMinute},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, todoistDuration(tt.timeTracking))
+		})
+	}
+}
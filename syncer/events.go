@@ -0,0 +1,69 @@
+package syncer
+
+import (
+	"context"
+	"time"
+)
+
+// SyncEventHandler is notified as a sync cycle makes changes, e.g. to post a
+// chat message when a Jira issue is resolved. Methods are called synchronously
+// from the cycle, so they should return quickly. Item events are only sent for
+// changes that were actually made, never for dry runs.
+type SyncEventHandler interface {
+	// OnSyncStart is called when a sync cycle, or a single item sync, starts.
+	// projectPair names the synced projects as "todoist project/jira project".
+	OnSyncStart(ctx context.Context, projectPair string)
+	// OnItemCreated is called after a Todoist task or Jira issue is created.
+	OnItemCreated(ctx context.Context, action SyncAction)
+	// OnItemUpdated is called after a linked pair is synced in either direction.
+	OnItemUpdated(ctx context.Context, action SyncAction)
+	// OnItemCompleted is called after a Todoist task is closed or a Jira issue is resolved.
+	OnItemCompleted(ctx context.Context, action SyncAction)
+	// OnSyncComplete is called with the summary when a sync cycle ends.
+	OnSyncComplete(ctx context.Context, summary SyncSummary, duration time.Duration)
+	// OnError is called when a sync action fails.
+	OnError(ctx context.Context, action SyncAction, err error)
+}
+
+// NopEventHandler ignores every event. Embed it to handle only some events.
+type NopEventHandler struct{}
+
+var _ SyncEventHandler = NopEventHandler{}
+
+// OnSyncStart implements SyncEventHandler.
+func (NopEventHandler) OnSyncStart(context.Context, string) {}
+
+// OnItemCreated implements SyncEventHandler.
+func (NopEventHandler) OnItemCreated(context.Context, SyncAction) {}
+
+// OnItemUpdated implements SyncEventHandler.
+func (NopEventHandler) OnItemUpdated(context.Context, SyncAction) {}
+
+// OnItemCompleted implements SyncEventHandler.
+func (NopEventHandler) OnItemCompleted(context.Context, SyncAction) {}
+
+// OnSyncComplete implements SyncEventHandler.
+func (NopEventHandler) OnSyncComplete(context.Context, SyncSummary, time.Duration) {}
+
+// OnError implements SyncEventHandler.
+func (NopEventHandler) OnError(context.Context, SyncAction, error) {}
+
+// handler returns the engine's event handler, treating nil as NopEventHandler
+// so engines built without NewEngine still work.
+func (e *Engine) handler() SyncEventHandler {
+	if e.events == nil {
+		return NopEventHandler{}
+	}
+	return e.events
+}
+
+// startSync notifies the event handler that a sync is starting.
+func (e *Engine) startSync(ctx context.Context) {
+	e.handler().OnSyncStart(ctx, projectPairName(e.cfg))
+}
+
+// recordError adds a failed action to the summary and notifies the event handler.
+func (e *Engine) recordError(ctx context.Context, s *SyncSummary, action SyncAction, err error) {
+	s.Errors = append(s.Errors, action)
+	e.handler().OnError(ctx, action, err)
+}
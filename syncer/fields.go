@@ -0,0 +1,215 @@
+package syncer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kalverra/todoist-jira-sync/jira"
+	"github.com/kalverra/todoist-jira-sync/todoist"
+)
+
+// Synced field names, used as suffixes of state store keys and in sync summaries.
+const (
+	fieldSummary     = "summary"
+	fieldDescription = "description"
+	fieldDueDate     = "duedate"
+	fieldDeadline    = "deadline"
+	fieldStatus      = "status"
+	fieldPriority    = "priority"
+	fieldLabels      = "labels"
+	fieldEstimate    = "estimate"
+	fieldOrder       = "order"
+)
+
+// diffFieldOrder is the order changed fields are listed in sync summaries.
+var diffFieldOrder = []string{
+	fieldSummary, fieldDescription, fieldDueDate, fieldDeadline, fieldStatus,
+	fieldPriority, fieldLabels, fieldEstimate, fieldOrder,
+}
+
+// contentHashKey is the state store key suffix for content hashes, stored as {id}:content_hash
+// for both Todoist task IDs and Jira issue keys.
+const contentHashKey = "content_hash"
+
+// syncFields holds the normalized value of each synced field, keyed by field name.
+// Values are normalized so both sides of a linked pair hash the same,
+// e.g. the summary never includes the Jira link prefix and the status is the
+// Todoist section name.
+type syncFields map[string]string
+
+func fieldStateKey(taskID, field string) string {
+	return taskID + ":" + field
+}
+
+func hashField(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// changedFields reports which fields differ from the hashes recorded on the
+// last sync. Every field counts as changed unless field-level sync is enabled.
+func (e *Engine) changedFields(taskID string, fields syncFields) map[string]bool {
+	changed := make(map[string]bool, len(fields))
+	for name, value := range fields {
+		if !e.cfg.FieldLevelSync {
+			changed[name] = true
+			continue
+		}
+		stored, ok := e.state.Get(fieldStateKey(taskID, name))
+		changed[name] = !ok || stored != hashField(value)
+	}
+	return changed
+}
+
+// recordFields stores hashes for the fields that were just synced.
+func (e *Engine) recordFields(taskID string, fields syncFields, changed map[string]bool) {
+	if !e.cfg.FieldLevelSync {
+		return
+	}
+	for name, value := range fields {
+		if changed[name] {
+			e.state.Set(fieldStateKey(taskID, name), hashField(value))
+		}
+	}
+}
+
+// TaskContentHash returns a short, stable hash of the synced fields of a
+// Todoist task: content without the Jira link, description, due date,
+// deadline, section, priority, labels, and duration.
+func TaskContentHash(task *todoist.Task) string {
+	return contentHash(syncFields{
+		fieldSummary:     StripJiraPrefix(task.Content),
+		fieldDescription: task.Description,
+		fieldDueDate:     task.DueDate(),
+		fieldDeadline:    task.DeadlineDate(),
+		fieldStatus:      task.SectionID,
+		fieldPriority:    strconv.Itoa(task.Priority),
+		fieldLabels:      sortedJoin(task.Labels),
+		fieldEstimate:    jiraEstimate(task.Duration),
+	})
+}
+
+// IssueContentHash returns a short, stable hash of the synced fields of a
+// Jira issue: summary, description text, due date, status, priority, labels,
+// and original estimate.
+func IssueContentHash(issue *jira.Issue) string {
+	if issue.Fields == nil {
+		return contentHash(nil)
+	}
+	status, priority := "", ""
+	if issue.Fields.Status != nil {
+		status = issue.Fields.Status.Name
+	}
+	if issue.Fields.Priority != nil {
+		priority = issue.Fields.Priority.ID
+	}
+	return contentHash(syncFields{
+		fieldSummary:     issue.Fields.Summary,
+		fieldDescription: jira.ADFToText(issue.Fields.Description),
+		fieldDueDate:     issue.Fields.Duedate,
+		fieldStatus:      status,
+		fieldPriority:    priority,
+		fieldLabels:      sortedJoin(issue.Fields.Labels),
+		fieldEstimate:    currentEstimate(issue),
+	})
+}
+
+// contentHash hashes fields in name order, truncated to 16 hex characters.
+func contentHash(fields syncFields) string {
+	h := sha256.New()
+	for _, name := range slices.Sorted(maps.Keys(fields)) {
+		fmt.Fprintf(h, "%s=%q\n", name, fields[name])
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+func sortedJoin(values []string) string {
+	return strings.Join(slices.Sorted(slices.Values(values)), ",")
+}
+
+// contentUnchanged reports whether hash matches the content hash recorded for
+// id after its last sync.
+func (e *Engine) contentUnchanged(id, hash string) bool {
+	stored, ok := e.state.Get(fieldStateKey(id, contentHashKey))
+	return ok && stored == hash
+}
+
+// recordContentHash stores the content hash of id after a sync.
+func (e *Engine) recordContentHash(id, hash string) {
+	e.state.Set(fieldStateKey(id, contentHashKey), hash)
+}
+
+// fieldDiff holds the values of the fields a push changed, before and after.
+type fieldDiff struct {
+	before, after syncFields
+}
+
+func newFieldDiff() fieldDiff {
+	return fieldDiff{before: syncFields{}, after: syncFields{}}
+}
+
+func (d fieldDiff) add(name, before, after string) {
+	d.before[name] = before
+	d.after[name] = after
+}
+
+func (d fieldDiff) empty() bool {
+	return len(d.after) == 0
+}
+
+// names returns the changed field names in diffFieldOrder.
+func (d fieldDiff) names() []string {
+	return changedFieldNames(d.before, d.after)
+}
+
+func (d fieldDiff) summarize() string {
+	return summarizeDiff(d.before, d.after)
+}
+
+// changedFieldNames returns the names of the fields whose values differ
+// between before and after, in diffFieldOrder.
+func changedFieldNames(before, after syncFields) []string {
+	var names []string
+	for _, name := range diffFieldOrder {
+		b, inBefore := before[name]
+		a, inAfter := after[name]
+		if (inBefore || inAfter) && a != b {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// summarizeDiff lists the fields that differ between before and after, e.g.
+// "summary, duedate". Descriptions are summarized as characters added and
+// removed, e.g. "description (+120/-45 chars)", rather than shown in full.
+func summarizeDiff(before, after syncFields) string {
+	names := changedFieldNames(before, after)
+	for i, name := range names {
+		if name == fieldDescription {
+			added, removed := charDiff(before[name], after[name])
+			names[i] = fmt.Sprintf("%s (+%d/-%d chars)", name, added, removed)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// charDiff counts the characters added and removed between before and after,
+// ignoring the prefix and suffix they share.
+func charDiff(before, after string) (added, removed int) {
+	b, a := []rune(before), []rune(after)
+	prefix := 0
+	for prefix < len(b) && prefix < len(a) && b[prefix] == a[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(b)-prefix && suffix < len(a)-prefix && b[len(b)-1-suffix] == a[len(a)-1-suffix] {
+		suffix++
+	}
+	return len(a) - prefix - suffix, len(b) - prefix - suffix
+}
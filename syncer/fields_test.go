@@ -0,0 +1,92 @@
+package syncer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kalverra/todoist-jira-sync/jira"
+	"github.com/kalverra/todoist-jira-sync/todoist"
+)
+
+func TestTaskContentHash(t *testing.T) {
+	t.Parallel()
+
+	task := &todoist.Task{
+		Content:     "[PROJ-1](https://jira.example.com/browse/PROJ-1) Write docs",
+		Description: "All of them",
+		Labels:      []string{"docs", "jira-sync"},
+		Priority:    2,
+	}
+	hash := TaskContentHash(task)
+	assert.Len(t, hash, 16)
+
+	same := *task
+	same.Content = "Write docs"
+	same.Labels = []string{"jira-sync", "docs"}
+	assert.Equal(t, hash, TaskContentHash(&same), "link prefix and label order don't matter")
+
+	changed := *task
+	changed.Due = &todoist.Due{Date: "2026-01-02"}
+	assert.NotEqual(t, hash, TaskContentHash(&changed))
+}
+
+func TestIssueContentHash(t *testing.T) {
+	t.Parallel()
+
+	issue := &jira.Issue{Key: "PROJ-1", Fields: &jira.IssueFields{
+		Summary:     "Write docs",
+		Description: jira.TextToADF("All of them"),
+		Status:      &jira.Status{Name: "To Do"},
+	}}
+	hash := IssueContentHash(issue)
+	assert.Len(t, hash, 16)
+	assert.Equal(t, hash, IssueContentHash(issue))
+
+	changed := *issue
+	fields := *issue.Fields
+	fields.Status = &jira.Status{Name: "Done"}
+	changed.Fields = &fields
+	assert.NotEqual(t, hash, IssueContentHash(&changed))
+	assert.Len(t, IssueContentHash(&jira.Issue{Key: "PROJ-2"}), 16)
+}
+
+func TestSummarizeDiff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		before, after syncFields
+		want          string
+		wantNames     []string
+	}{
+		{
+			name:      "no changes",
+			before:    syncFields{fieldSummary: "a"},
+			after:     syncFields{fieldSummary: "a"},
+			want:      "",
+			wantNames: nil,
+		},
+		{
+			name:      "ordered by field",
+			before:    syncFields{fieldDueDate: "", fieldSummary: "old"},
+			after:     syncFields{fieldDueDate: "2026-01-02", fieldSummary: "new"},
+			want:      "summary, duedate",
+			wantNames: []string{fieldSummary, fieldDueDate},
+		},
+		{
+			name:      "description char counts",
+			before:    syncFields{fieldDescription: "Fix the old bug today"},
+			after:     syncFields{fieldDescription: "Fix the new and shiny bug today"},
+			want:      "description (+13/-3 chars)",
+			wantNames: []string{fieldDescription},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, summarizeDiff(tt.before, tt.after))
+			assert.Equal(t, tt.wantNames, changedFieldNames(tt.before, tt.after))
+		})
+	}
+}
@@ -8,7 +8,11 @@ import (
 )
 
 // jiraPrefixPattern matches a markdown link like [PROJ-123](https://...) at the start of content.
-var jiraPrefixPattern = regexp.MustCompile(`^\[([A-Z][A-Z0-9_]+-\d+)\]\(https?://[^)]+\)\s*`)
+// Issue keys are a project key, a dash, and the issue number. Per Jira's project key rules
+// (https://support.atlassian.com/jira-cloud-administration/docs/edit-a-projects-details/),
+// project keys are at least two characters, start with an uppercase letter, and contain only
+// uppercase letters, digits, and underscores. Issue numbers start at 1.
+var jiraPrefixPattern = regexp.MustCompile(`^\[([A-Z][A-Z0-9_]+-[1-9]\d*)\]\(https?://[^)]+\)\s*`)
 
 // ExtractJiraKey extracts the Jira issue key from a Todoist task's content prefix.
 // Returns empty string if no link prefix is found.
@@ -26,12 +30,14 @@ func StripJiraPrefix(content string) string {
 	return jiraPrefixPattern.ReplaceAllString(content, "")
 }
 
-// PrependJiraLink prepends a markdown link to the Jira issue at the start of content.
-// If the content already has a Jira prefix, it is replaced.
-// jiraBaseURL should be the Jira instance URL without trailing slash, e.g. "https://example.atlassian.net".
+// PrependJiraLink prepends a markdown link to the Jira issue at the start of content,
+// e.g. "[PROJ-123](https://example.atlassian.net/browse/PROJ-123) My task".
+// If the content already has a Jira prefix, it is replaced, so StripJiraPrefix
+// of the result is always the stripped content.
+// jiraBaseURL is the Jira instance URL, e.g. "https://example.atlassian.net".
 func PrependJiraLink(content, jiraKey, jiraBaseURL string) string {
 	stripped := StripJiraPrefix(content)
-	link := fmt.Sprintf("[%s](%s/browse/%s)", jiraKey, jiraBaseURL, jiraKey)
+	link := fmt.Sprintf("[%s](%s/browse/%s)", jiraKey, strings.TrimRight(jiraBaseURL, "/"), jiraKey)
 	if stripped == "" {
 		return link
 	}
@@ -1,6 +1,8 @@
 package syncer
 
 import (
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -39,6 +41,41 @@ func TestExtractJiraKey(t *testing.T) {
 			content: "[MY_PROJ-42](https://x.atlassian.net/browse/MY_PROJ-42) Task",
 			want:    "MY_PROJ-42",
 		},
+		{
+			name:    "digits after the first letter",
+			content: "[A1-5](https://x.atlassian.net/browse/A1-5) Task",
+			want:    "A1-5",
+		},
+		{
+			name:    "long project key",
+			content: "[ABCDEFGHIJKLMNOPQRSTUVWXYZ-7](https://x.atlassian.net/browse/ABCDEFGHIJKLMNOPQRSTUVWXYZ-7) Task",
+			want:    "ABCDEFGHIJKLMNOPQRSTUVWXYZ-7",
+		},
+		{
+			name:    "multiple underscores",
+			content: "[MY_BIG_PROJ_2-1000](https://x.atlassian.net/browse/MY_BIG_PROJ_2-1000) Task",
+			want:    "MY_BIG_PROJ_2-1000",
+		},
+		{
+			name:    "issue number zero is not a key",
+			content: "[PROJ-0](https://x.atlassian.net/browse/PROJ-0) Task",
+			want:    "",
+		},
+		{
+			name:    "leading zero is not a key",
+			content: "[PROJ-01](https://x.atlassian.net/browse/PROJ-01) Task",
+			want:    "",
+		},
+		{
+			name:    "single letter project key",
+			content: "[A-1](https://x.atlassian.net/browse/A-1) Task",
+			want:    "",
+		},
+		{
+			name:    "lowercase project key",
+			content: "[proj-1](https://x.atlassian.net/browse/proj-1) Task",
+			want:    "",
+		},
 		{
 			name:    "http scheme",
 			content: "[TEST-1](http://localhost/browse/TEST-1) Local task",
@@ -49,6 +86,16 @@ func TestExtractJiraKey(t *testing.T) {
 			content: "[PROJ-99](https://example.atlassian.net/browse/PROJ-99)",
 			want:    "PROJ-99",
 		},
+		{
+			name:    "title with brackets",
+			content: "[PROJ-7](https://x.atlassian.net/browse/PROJ-7) Fix [login] page",
+			want:    "PROJ-7",
+		},
+		{
+			name:    "legacy prefix is not a link",
+			content: "[PROJ-7] My task",
+			want:    "",
+		},
 	}
 
 	for _, tt := range tests {
@@ -88,6 +135,26 @@ func TestStripJiraPrefix(t *testing.T) {
 			content: "",
 			want:    "",
 		},
+		{
+			name:    "bracketed tag without link is kept",
+			content: "[WIP] Fix login",
+			want:    "[WIP] Fix login",
+		},
+		{
+			name:    "non-jira markdown link is kept",
+			content: "[Design doc](https://docs.example.com/d/1) review",
+			want:    "[Design doc](https://docs.example.com/d/1) review",
+		},
+		{
+			name:    "only the leading prefix is removed",
+			content: "[PROJ-1](https://x.atlassian.net/browse/PROJ-1) see [PROJ-2](https://x.atlassian.net/browse/PROJ-2)",
+			want:    "see [PROJ-2](https://x.atlassian.net/browse/PROJ-2)",
+		},
+		{
+			name:    "utf-8 title",
+			content: "[PROJ-1](https://x.atlassian.net/browse/PROJ-1) Überprüfung der Größe 🚀",
+			want:    "Überprüfung der Größe 🚀",
+		},
 	}
 
 	for _, tt := range tests {
@@ -130,6 +197,34 @@ func TestPrependJiraLink(t *testing.T) {
 			jiraBaseURL: "https://example.atlassian.net",
 			want:        "[NEW-2](https://example.atlassian.net/browse/NEW-2) My task",
 		},
+		{
+			name:        "same prefix is not doubled",
+			content:     "[PROJ-1](https://example.atlassian.net/browse/PROJ-1) My task",
+			jiraKey:     "PROJ-1",
+			jiraBaseURL: "https://example.atlassian.net",
+			want:        "[PROJ-1](https://example.atlassian.net/browse/PROJ-1) My task",
+		},
+		{
+			name:        "content with other brackets",
+			content:     "[WIP] Fix [login] page",
+			jiraKey:     "PROJ-1",
+			jiraBaseURL: "https://example.atlassian.net",
+			want:        "[PROJ-1](https://example.atlassian.net/browse/PROJ-1) [WIP] Fix [login] page",
+		},
+		{
+			name:        "trailing slash in base URL",
+			content:     "My task",
+			jiraKey:     "PROJ-1",
+			jiraBaseURL: "https://example.atlassian.net/",
+			want:        "[PROJ-1](https://example.atlassian.net/browse/PROJ-1) My task",
+		},
+		{
+			name:        "utf-8 content",
+			content:     "日本語のタスク",
+			jiraKey:     "PROJ-1",
+			jiraBaseURL: "https://example.atlassian.net",
+			want:        "[PROJ-1](https://example.atlassian.net/browse/PROJ-1) 日本語のタスク",
+		},
 	}
 
 	for _, tt := range tests {
@@ -145,15 +240,56 @@ func TestRoundTrip(t *testing.T) {
 	t.Parallel()
 
 	baseURL := "https://example.atlassian.net"
-	content := "My original task"
 	key := "DEVEX-99"
 
-	linked := PrependJiraLink(content, key, baseURL)
-	extractedKey := ExtractJiraKey(linked)
-	assert.Equal(t, key, extractedKey)
+	for _, content := range []string{
+		"My original task",
+		"",
+		"[WIP] Fix [login] page",
+		"[Design doc](https://docs.example.com/d/1) review",
+		"Überprüfung 🚀",
+	} {
+		linked := PrependJiraLink(content, key, baseURL)
+		assert.Equal(t, key, ExtractJiraKey(linked), content)
+		assert.Equal(t, content, StripJiraPrefix(linked), content)
+		assert.Equal(t, linked, PrependJiraLink(linked, key, baseURL), "prepending again should be a no-op")
+	}
+}
+
+// jiraKeyPattern matches a whole Jira issue key.
+var jiraKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]+-\d+$`)
+
+func FuzzExtractJiraKey(f *testing.F) {
+	for _, seed := range []string{
+		"[DEVEX-123](https://example.atlassian.net/browse/DEVEX-123) My task",
+		"[DEVEX-123](https://example.atlassian.net/browse/DEVEX-123)",
+		"[DEVEX-123](https://example.atlassian.net/browse/DEVEX-123)\n\n\nMy task",
+		"\n[DEVEX-123](https://example.atlassian.net/browse/DEVEX-123) My task",
+		"[DEVEX-123](https://example.atlassian.net/browse/DEVEX-123\x00) My task",
+		"[DEV\x00EX-123](https://example.atlassian.net/browse/DEVEX-123) My task",
+		"[DEVEX-0](https://example.atlassian.net/browse/DEVEX-0) My task",
+		"[devex-123](https://example.atlassian.net/browse/devex-123) My task",
+		"[DEVEX-123](https://example.atlassian.net/browse/DEVEX-123 My task",
+		"[ÜBER-1](https://example.atlassian.net/browse/ÜBER-1) Überprüfung 🚀",
+		"[DEVEX-123](https://example.atlassian.net/browse/" + strings.Repeat("A", 100_000) + ") My task",
+		"[" + strings.Repeat("A", 10_000) + "-1](https://example.atlassian.net) My task",
+		strings.Repeat("[DEVEX-123](", 1000),
+		"My task",
+		"",
+		"\xff\xfe\xfd",
+	} {
+		f.Add(seed)
+	}
 
-	stripped := StripJiraPrefix(linked)
-	assert.Equal(t, content, stripped)
+	f.Fuzz(func(t *testing.T, content string) {
+		key := ExtractJiraKey(content)
+		if key == "" {
+			return
+		}
+		assert.Regexp(t, jiraKeyPattern, key)
+		assert.True(t, strings.HasPrefix(content, "["+key+"]("), "key should come from the content's link prefix")
+		assert.Equal(t, key, ExtractJiraKey(PrependJiraLink(content, key, "https://example.atlassian.net")))
+	})
 }
 
 func TestNormalizeJiraURL(t *testing.T) {
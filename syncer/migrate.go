@@ -0,0 +1,83 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/kalverra/todoist-jira-sync/todoist"
+)
+
+// legacyPrefixPattern matches the plain "[PROJ-123] " prefix used to link tasks
+// before links were stored as markdown links.
+var legacyPrefixPattern = regexp.MustCompile(`^\[([A-Z][A-Z0-9_]+-[1-9]\d*)\] `)
+
+// MigrateContentPrefixLinks rewrites linked Todoist tasks that still use the
+// legacy "[PROJ-123] Title" prefix to the markdown link prefix written by
+// PrependJiraLink, so the engine recognizes them as linked.
+// Tasks that already have a markdown link prefix are left alone, so it's safe to run repeatedly.
+// With Config.ProjectPairs, every pair's Todoist project is migrated.
+func MigrateContentPrefixLinks(ctx context.Context, e *Engine) error {
+	for _, pe := range e.pairEngines() {
+		if err := migrateContentPrefixLinks(ctx, pe); err != nil {
+			return pe.pairError(err)
+		}
+	}
+	return nil
+}
+
+// migrateContentPrefixLinks migrates the tasks in e.cfg's Todoist project.
+func migrateContentPrefixLinks(ctx context.Context, e *Engine) error {
+	project, err := e.todoist.FindProjectByName(ctx, e.cfg.TodoistProject)
+	if err != nil {
+		return fmt.Errorf("find todoist project: %w", err)
+	}
+	tasks, err := e.todoist.GetTasks(ctx, project.ID)
+	if err != nil {
+		return fmt.Errorf("get todoist tasks: %w", err)
+	}
+
+	migrated := 0
+	for _, task := range tasks {
+		if !slices.Contains(task.Labels, linkLabel) || ExtractJiraKey(task.Content) != "" {
+			continue
+		}
+		content, jiraKey, ok := migrateLegacyPrefix(task.Content, e.cfg.JiraURL)
+		if !ok {
+			continue
+		}
+		if _, err := e.todoist.UpdateTask(ctx, task.ID, todoist.UpdateTaskRequest{Content: &content}); err != nil {
+			return fmt.Errorf("update todoist task %s: %w", task.ID, err)
+		}
+		e.logger.Info().
+			Str("task_id", task.ID).
+			Str("issue_key", jiraKey).
+			Msg("migrated legacy jira link")
+		migrated++
+	}
+
+	e.logger.Info().Int("count", migrated).Msg("legacy jira link migration complete")
+	return nil
+}
+
+// ExtractJiraKeyFromContent extracts the Jira issue key from a Todoist task's
+// legacy "[PROJ-123] Title" content prefix. Returns empty string if there is none;
+// ExtractJiraKey reads the current markdown link prefix.
+func ExtractJiraKeyFromContent(content string) string {
+	matches := legacyPrefixPattern.FindStringSubmatch(content)
+	if len(matches) < 2 {
+		return ""
+	}
+	return matches[1]
+}
+
+// migrateLegacyPrefix converts a legacy "[PROJ-123] Title" content to the markdown link format.
+func migrateLegacyPrefix(content, jiraBaseURL string) (migrated, jiraKey string, ok bool) {
+	jiraKey = ExtractJiraKeyFromContent(content)
+	if jiraKey == "" {
+		return "", "", false
+	}
+	title := legacyPrefixPattern.ReplaceAllString(content, "")
+	return PrependJiraLink(title, jiraKey, jiraBaseURL), jiraKey, true
+}
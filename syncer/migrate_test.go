@@ -0,0 +1,86 @@
+package syncer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractJiraKeyFromContent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "empty content"},
+		{name: "no prefix", content: "Just a plain task name"},
+		{name: "legacy prefix", content: "[PROJ-123] My task", want: "PROJ-123"},
+		{name: "legacy prefix, empty title", content: "[PROJ-123] ", want: "PROJ-123"},
+		{name: "title with brackets", content: "[PROJ-1] Fix [login] page", want: "PROJ-1"},
+		{name: "bracketed tag", content: "[WIP] My task"},
+		{name: "markdown link", content: "[PROJ-1](https://example.atlassian.net/browse/PROJ-1) My task"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, ExtractJiraKeyFromContent(tt.content))
+		})
+	}
+}
+
+func TestMigrateLegacyPrefix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantKey string
+		wantOK  bool
+	}{
+		{
+			name:    "legacy prefix",
+			content: "[PROJ-123] My task",
+			want:    "[PROJ-123](https://example.atlassian.net/browse/PROJ-123) My task",
+			wantKey: "PROJ-123",
+			wantOK:  true,
+		},
+		{
+			name:    "title with brackets",
+			content: "[PROJ-1] Fix [login] page",
+			want:    "[PROJ-1](https://example.atlassian.net/browse/PROJ-1) Fix [login] page",
+			wantKey: "PROJ-1",
+			wantOK:  true,
+		},
+		{
+			name:    "already a markdown link",
+			content: "[PROJ-1](https://example.atlassian.net/browse/PROJ-1) My task",
+		},
+		{
+			name:    "no prefix",
+			content: "My task",
+		},
+		{
+			name:    "bracketed tag",
+			content: "[WIP] My task",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, key, ok := migrateLegacyPrefix(tt.content, "https://example.atlassian.net")
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantKey, key)
+			assert.Equal(t, tt.want, got)
+			if ok {
+				assert.Equal(t, tt.wantKey, ExtractJiraKey(got))
+				_, _, again := migrateLegacyPrefix(got, "https://example.atlassian.net")
+				assert.False(t, again, "migrated content should not migrate again")
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/kalverra/todoist-jira-sync/config"
+	"github.com/kalverra/todoist-jira-sync/todoist"
+)
+
+// runForPair runs one sync cycle for pair on an engine using the pair's
+// resolved config. With Config.ProjectPairs, the summary's actions and the
+// error are labelled with the pair's name.
+func (e *Engine) runForPair(ctx context.Context, pair config.ProjectPair) (SyncSummary, error) {
+	pe := e.forPair(pair)
+	summary, err := pe.syncProject(ctx)
+	if pe.pair == "" {
+		return summary, err
+	}
+	if err != nil {
+		return SyncSummary{}, fmt.Errorf("%s: %w", pe.pair, err)
+	}
+	summary.setProjectPair(pe.pair)
+	return summary, nil
+}
+
+// forPair returns an engine that syncs pair, sharing e's clients, state, and
+// handlers. The implicit pair used without Config.ProjectPairs is unnamed.
+func (e *Engine) forPair(pair config.ProjectPair) *Engine {
+	cfg := e.resolvedConfig(pair)
+	pe := &Engine{
+		todoist:    e.todoist,
+		jira:       e.jira,
+		cfg:        cfg,
+		logger:     e.logger,
+		resolver:   e.resolver,
+		events:     e.events,
+		state:      e.state,
+		clock:      e.clock,
+		lastSync:   e.lastSync,
+		dryRun:     e.dryRun,
+		retryQueue: e.retryQueue,
+		summaryOut: e.summaryOut,
+	}
+	if len(e.cfg.ProjectPairs) > 0 {
+		pe.pair = projectPairName(cfg)
+		pe.logger = e.logger.With().Str("project_pair", pe.pair).Logger()
+	}
+	return pe
+}
+
+// pairEngines returns an engine from forPair for every Config.ProjectPairs
+// entry, or just e without project pairs.
+func (e *Engine) pairEngines() []*Engine {
+	if len(e.cfg.ProjectPairs) == 0 {
+		return []*Engine{e}
+	}
+	engines := make([]*Engine, 0, len(e.cfg.ProjectPairs))
+	for _, pair := range e.cfg.ProjectPairs {
+		engines = append(engines, e.forPair(pair))
+	}
+	return engines
+}
+
+// issuePair returns the engine for the project pair syncing jiraKey's Jira
+// project, or nil if none does. Without Config.ProjectPairs, it returns e.
+func (e *Engine) issuePair(jiraKey string) *Engine {
+	if len(e.cfg.ProjectPairs) == 0 {
+		return e
+	}
+	for _, pe := range e.pairEngines() {
+		if inJiraProject(jiraKey, pe.cfg.JiraProject) {
+			return pe
+		}
+	}
+	return nil
+}
+
+// taskPair returns the engine for the project pair syncing task's Todoist
+// project, or nil if none does. When several pairs share the project, a linked
+// task goes to the pair for its issue's Jira project. Without
+// Config.ProjectPairs, it returns e.
+func (e *Engine) taskPair(ctx context.Context, task *todoist.Task) (*Engine, error) {
+	if len(e.cfg.ProjectPairs) == 0 {
+		return e, nil
+	}
+	projects, err := e.todoist.GetProjects(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get todoist projects: %w", err)
+	}
+	i := slices.IndexFunc(projects, func(p todoist.Project) bool { return p.ID == task.ProjectID })
+	if i < 0 {
+		return nil, nil
+	}
+	jiraKey := ExtractJiraKey(task.Content)
+	var match *Engine
+	for _, pe := range e.pairEngines() {
+		if pe.cfg.TodoistProject != projects[i].Name {
+			continue
+		}
+		if jiraKey == "" || inJiraProject(jiraKey, pe.cfg.JiraProject) {
+			return pe, nil
+		}
+		if match == nil {
+			match = pe
+		}
+	}
+	return match, nil
+}
+
+// inJiraProject reports whether jiraKey is an issue key in the Jira project projectKey.
+func inJiraProject(jiraKey, projectKey string) bool {
+	prefix, _, ok := strings.Cut(jiraKey, "-")
+	return ok && strings.EqualFold(prefix, projectKey)
+}
+
+// pairError labels err with the engine's project pair, if it has one.
+func (e *Engine) pairError(err error) error {
+	if err == nil || e.pair == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %w", e.pair, err)
+}
+
+// projectPairName names the projects synced by cfg as "todoist project/jira project".
+func projectPairName(cfg *config.Config) string {
+	return cfg.TodoistProject + "/" + cfg.JiraProject
+}
+
+// resolvedConfig returns the config for syncing pair: the top-level config
+// with the pair's non-zero fields replacing its own.
+func (e *Engine) resolvedConfig(pair config.ProjectPair) *config.Config {
+	resolved := e.cfg.Merge(&config.Config{
+		TodoistProject:     pair.TodoistProject,
+		JiraProject:        pair.JiraProject,
+		JiraIssueTypes:     pair.JiraIssueTypes,
+		JiraAssigneeFilter: pair.JiraAssigneeFilter,
+		JiraBoard:          pair.JiraBoard,
+		JiraRequiredLabel:  pair.JiraRequiredLabel,
+	})
+	// Merge adds map entries, but a pair's statuses replace the top-level ones
+	// since its Jira project may have a different workflow.
+	if pair.StatusMap != nil {
+		resolved.StatusMap = maps.Clone(pair.StatusMap)
+	}
+	return resolved
+}
@@ -0,0 +1,113 @@
+package syncer
+
+import (
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kalverra/todoist-jira-sync/config"
+	"github.com/kalverra/todoist-jira-sync/internal/testserver"
+	"github.com/kalverra/todoist-jira-sync/jira"
+	"github.com/kalverra/todoist-jira-sync/todoist"
+)
+
+func TestResolvedConfig(t *testing.T) {
+	t.Parallel()
+
+	e := &Engine{cfg: &config.Config{
+		TodoistProject:     "Work",
+		JiraProject:        "PROJ",
+		JiraURL:            "https://example.atlassian.net",
+		JiraIssueTypes:     []string{"Story", "Bug"},
+		JiraAssigneeFilter: "currentUser()",
+		JiraBoard:          7,
+		JiraRequiredLabel:  "todoist",
+		StatusMap:          map[string]string{"To Do": "Backlog", "In Progress": "Doing"},
+	}}
+
+	t.Run("fallback", func(t *testing.T) {
+		t.Parallel()
+
+		got := e.resolvedConfig(config.ProjectPair{TodoistProject: "Ops", JiraProject: "OPS"})
+		assert.Equal(t, "Ops", got.TodoistProject)
+		assert.Equal(t, "OPS", got.JiraProject)
+		assert.Equal(t, "https://example.atlassian.net", got.JiraURL)
+		assert.Equal(t, []string{"Story", "Bug"}, got.JiraIssueTypes)
+		assert.Equal(t, "currentUser()", got.JiraAssigneeFilter)
+		assert.Equal(t, 7, got.JiraBoard)
+		assert.Equal(t, "todoist", got.JiraRequiredLabel)
+		assert.Equal(t, e.cfg.StatusMap, got.StatusMap)
+	})
+
+	t.Run("override", func(t *testing.T) {
+		t.Parallel()
+
+		got := e.resolvedConfig(config.ProjectPair{
+			TodoistProject:     "Ops",
+			JiraProject:        "OPS",
+			StatusMap:          map[string]string{"Open": "Inbox"},
+			JiraIssueTypes:     []string{"Incident"},
+			JiraAssigneeFilter: "EMPTY",
+			JiraBoard:          9,
+			JiraRequiredLabel:  "oncall",
+		})
+		assert.Equal(t, map[string]string{"Open": "Inbox"}, got.StatusMap)
+		assert.Equal(t, "Inbox", got.JiraToTodoistStatus("Open"))
+		assert.Equal(t, []string{"Incident"}, got.JiraIssueTypes)
+		assert.Equal(t, "EMPTY", got.JiraAssigneeFilter)
+		assert.Equal(t, 9, got.JiraBoard)
+		assert.Equal(t, "oncall", got.JiraRequiredLabel)
+		assert.Equal(t, "Work", e.cfg.TodoistProject, "top-level config should be unchanged")
+		assert.Equal(t, "Doing", e.cfg.JiraToTodoistStatus("In Progress"))
+	})
+}
+
+func TestTargetedSyncUsesProjectPair(t *testing.T) {
+	t.Parallel()
+
+	todoistSrv := testserver.NewTodoist(t)
+	jiraSrv := testserver.NewJira(t)
+	cfg := &config.Config{
+		JiraURL:     jiraSrv.URL,
+		SyncBacklog: true,
+		ProjectPairs: []config.ProjectPair{
+			{TodoistProject: "Work", JiraProject: "PROJ"},
+			{TodoistProject: "Ops", JiraProject: "OPS"},
+		},
+	}
+	e, err := NewEngine(
+		WithTodoistClient(testserver.TodoistClient(t, todoistSrv)),
+		WithJiraClient(testserver.JiraClient(t, jiraSrv)),
+		WithConfig(cfg),
+		WithStateStore(newMemoryStateStore()),
+	)
+	require.NoError(t, err)
+	e.SetSummaryOutput(io.Discard)
+	todoistSrv.AddProject("Work")
+	ops := todoistSrv.AddProject("Ops")
+
+	issue := jiraSrv.AddIssue(jira.IssueFields{Project: &jira.Project{Key: "OPS"}, Summary: "Page on call"})
+	require.NoError(t, e.SyncIssue(t.Context(), issue.Key))
+	tasks, err := testserver.TodoistClient(t, todoistSrv).GetTasks(t.Context(), ops.ID)
+	require.NoError(t, err)
+	assert.True(t, slices.ContainsFunc(tasks, func(task todoist.Task) bool {
+		return ExtractJiraKey(task.Content) == issue.Key
+	}), "issue synced to its pair's todoist project")
+	require.Len(t, e.LastSummary().CreatedTodoist, 1)
+	assert.Equal(t, "Ops/OPS", e.LastSummary().CreatedTodoist[0].ProjectPair)
+
+	task := todoistSrv.AddTask(todoist.Task{ProjectID: ops.ID, Content: "Rotate keys", Labels: []string{linkLabel}})
+	require.NoError(t, e.SyncTask(t.Context(), task.ID))
+	synced, ok := todoistSrv.Task(task.ID)
+	require.True(t, ok)
+	assert.True(t, inJiraProject(ExtractJiraKey(synced.Content), "OPS"), "task synced to its pair's jira project")
+	require.Len(t, e.LastSummary().CreatedJira, 1)
+	assert.Equal(t, "Ops/OPS", e.LastSummary().CreatedJira[0].ProjectPair)
+
+	unpaired := jiraSrv.AddIssue(jira.IssueFields{Project: &jira.Project{Key: "OTHER"}, Summary: "Not synced"})
+	require.NoError(t, e.SyncIssue(t.Context(), unpaired.Key))
+	assert.Empty(t, e.LastSummary().CreatedTodoist, "issue without a project pair is skipped")
+}
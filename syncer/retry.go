@@ -0,0 +1,279 @@
+package syncer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog"
+
+	"github.com/kalverra/todoist-jira-sync/todoist"
+)
+
+const retryQueueStateKey = "retry_queue"
+
+// retryKind identifies which sync action a retry item replays.
+type retryKind string
+
+const (
+	retryCreateJira    retryKind = "create_jira"
+	retryCreateTodoist retryKind = "create_todoist"
+	retrySyncPair      retryKind = "sync_pair"
+	retryResolveJira   retryKind = "resolve_jira"
+)
+
+// retryItem is a failed sync action waiting to be retried.
+type retryItem struct {
+	Kind     retryKind `json:"kind"`
+	JiraKey  string    `json:"jira_key,omitempty"`
+	TaskID   string    `json:"task_id,omitempty"`
+	Summary  string    `json:"summary"`
+	Attempts int       `json:"attempts"`
+	Pair     string    `json:"pair,omitempty"` // project pair that queued the item, see Engine.forPair
+}
+
+func (r retryItem) id() string {
+	return retryID(r.Kind, r.JiraKey, r.TaskID)
+}
+
+// retryID identifies a sync action, so a queued item isn't queued twice and
+// a cycle can skip the actions its retry pass already handled.
+func retryID(kind retryKind, jiraKey, taskID string) string {
+	return string(kind) + ":" + jiraKey + ":" + taskID
+}
+
+func (r retryItem) action() SyncAction {
+	return SyncAction{JiraKey: r.JiraKey, Summary: r.Summary}
+}
+
+// retryQueue holds failed sync actions in the state store so they survive restarts.
+// It's shared by the engines of every project pair, which may run concurrently.
+// A queue that can't be decoded is left in the state store as is, rather than
+// being overwritten, so the queued actions aren't lost.
+type retryQueue struct {
+	mu     sync.Mutex
+	store  StateStore
+	logger zerolog.Logger
+}
+
+func (q *retryQueue) items() ([]retryItem, error) {
+	raw, ok := q.store.Get(retryQueueStateKey)
+	if !ok || raw == "" {
+		return nil, nil
+	}
+	var items []retryItem
+	if err := json.Unmarshal([]byte(raw), &items); err != nil {
+		return nil, fmt.Errorf("decode retry queue: %w", err)
+	}
+	return items, nil
+}
+
+func (q *retryQueue) save(items []retryItem) {
+	if len(items) == 0 {
+		q.store.Delete(retryQueueStateKey)
+		return
+	}
+	b, err := json.Marshal(items)
+	if err != nil {
+		return
+	}
+	q.store.Set(retryQueueStateKey, string(b))
+}
+
+// add queues an item unless an item for the same action is already queued.
+func (q *retryQueue) add(item retryItem) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	items, err := q.items()
+	if err != nil {
+		q.logger.Error().Err(err).
+			Str("kind", string(item.Kind)).
+			Str("issue_key", item.JiraKey).
+			Str("task_id", item.TaskID).
+			Msg("retry queue in sync state is corrupt, not queueing sync action; run reset to clear it")
+		return
+	}
+	for _, existing := range items {
+		if existing.id() == item.id() {
+			return
+		}
+	}
+	q.save(append(items, item))
+}
+
+// take removes the items queued by pair from the queue and returns them.
+func (q *retryQueue) take(pair string) []retryItem {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	items, err := q.items()
+	if err != nil {
+		q.logger.Error().Err(err).Msg("retry queue in sync state is corrupt, skipping retries; run reset to clear it")
+		return nil
+	}
+	var taken, kept []retryItem
+	for _, item := range items {
+		if item.Pair == pair {
+			taken = append(taken, item)
+		} else {
+			kept = append(kept, item)
+		}
+	}
+	if len(taken) > 0 {
+		q.save(kept)
+	}
+	return taken
+}
+
+// processRetryQueue replays queued failures before the main sync cycle so
+// the cycle's fresh fetch sees their results. Items that succeed are dropped,
+// items that fail are requeued until they've been tried Config.MaxRetry times.
+// It returns the IDs of the items it handled, which the rest of the cycle
+// skips so they're neither synced twice nor requeued without their attempts.
+func (e *Engine) processRetryQueue(ctx context.Context, s *SyncSummary) map[string]bool {
+	items := e.retryQueue.take(e.pair)
+	if len(items) == 0 {
+		return nil
+	}
+	e.logger.Info().Int("count", len(items)).Msg("processing retry queue")
+
+	project, secMap, err := e.loadProject(ctx)
+	if err != nil {
+		e.logger.Error().Err(err).Msg("failed to load todoist project, deferring retry queue")
+		for _, item := range items {
+			e.retryQueue.add(item)
+		}
+		return nil
+	}
+	// Fetched once for every item that looks for its linked task.
+	tasks, err := e.todoist.GetTasks(ctx, project.ID)
+	if err != nil {
+		e.logger.Error().Err(err).Msg("failed to get todoist tasks, deferring retry queue")
+		for _, item := range items {
+			e.retryQueue.add(item)
+		}
+		return nil
+	}
+
+	handled := make(map[string]bool, len(items))
+	for _, item := range items {
+		handled[item.id()] = true
+		item.Attempts++
+		err := e.retry(ctx, item, project.ID, tasks, secMap, s)
+		if err == nil {
+			e.logger.Info().
+				Str("kind", string(item.Kind)).
+				Str("issue_key", item.JiraKey).
+				Str("task_id", item.TaskID).
+				Int("attempts", item.Attempts).
+				Msg("retried sync action succeeded")
+			continue
+		}
+		if item.Attempts >= e.cfg.MaxRetry {
+			e.logger.Error().Err(err).
+				Str("kind", string(item.Kind)).
+				Str("issue_key", item.JiraKey).
+				Str("task_id", item.TaskID).
+				Int("attempts", item.Attempts).
+				Msg("giving up on sync action after max retries")
+			e.recordError(ctx, s, item.action(), err)
+			continue
+		}
+		e.logger.Warn().Err(err).
+			Str("kind", string(item.Kind)).
+			Str("issue_key", item.JiraKey).
+			Str("task_id", item.TaskID).
+			Int("attempts", item.Attempts).
+			Msg("retried sync action failed, keeping in queue")
+		e.retryQueue.add(item)
+	}
+	return handled
+}
+
+// queueRetry adds a failed action to the retry queue, if retries are enabled.
+func (e *Engine) queueRetry(item retryItem) {
+	if e.cfg.MaxRetry <= 0 {
+		return
+	}
+	item.Pair = e.pair
+	e.retryQueue.add(item)
+}
+
+func (e *Engine) retry(
+	ctx context.Context,
+	item retryItem,
+	projectID string,
+	tasks []todoist.Task,
+	secMap SectionMap,
+	s *SyncSummary,
+) error {
+	switch item.Kind {
+	case retryCreateJira:
+		task, err := e.todoist.GetTask(ctx, item.TaskID)
+		if err != nil {
+			return fmt.Errorf("get todoist task: %w", err)
+		}
+		if task.Checked || e.classifyTask(task) != taskUnlinked {
+			return nil
+		}
+		return e.createJiraFromTodoist(ctx, task, secMap, s)
+	case retryCreateTodoist:
+		issue, err := e.syncedIssue(ctx, item.JiraKey)
+		if err != nil || issue == nil {
+			return err
+		}
+		if _, linked := linkedTask(tasks, issue.Key); linked {
+			return nil
+		}
+		return e.createTodoistFromJira(ctx, issue, projectID, secMap, s)
+	case retrySyncPair:
+		issue, err := e.syncedIssue(ctx, item.JiraKey)
+		if err != nil || issue == nil {
+			return err
+		}
+		task, linked := linkedTask(tasks, issue.Key)
+		if !linked {
+			return nil
+		}
+		return e.syncLinkedPair(ctx, task, issue, projectID, secMap, s)
+	case retryResolveJira:
+		issue, err := e.syncedIssue(ctx, item.JiraKey)
+		if err != nil || issue == nil {
+			return err
+		}
+		if issue.Fields != nil && issue.Fields.Resolution != nil {
+			return nil
+		}
+		if err := e.jira.DoTransition(ctx, issue.Key, "Closed"); err != nil {
+			return fmt.Errorf("transition jira issue to Closed: %w", err)
+		}
+		action := SyncAction{JiraKey: issue.Key, Summary: issue.Fields.Summary}
+		s.ResolvedJira = append(s.ResolvedJira, action)
+		e.handler().OnItemCompleted(ctx, action)
+		return nil
+	}
+	return fmt.Errorf("unknown retry kind %q", item.Kind)
+}
+
+// findLinkedTask returns the active Todoist task linked to jiraKey, if any.
+func (e *Engine) findLinkedTask(
+	ctx context.Context,
+	projectID, jiraKey string,
+) (*todoist.Task, bool, error) {
+	tasks, err := e.todoist.GetTasks(ctx, projectID)
+	if err != nil {
+		return nil, false, fmt.Errorf("get todoist tasks: %w", err)
+	}
+	task, linked := linkedTask(tasks, jiraKey)
+	return task, linked, nil
+}
+
+// linkedTask returns the task in tasks linked to jiraKey, if any.
+func linkedTask(tasks []todoist.Task, jiraKey string) (*todoist.Task, bool) {
+	for i := range tasks {
+		if ExtractJiraKey(tasks[i].Content) == jiraKey {
+			return &tasks[i], true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,107 @@
+package syncer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// StateStore persists sync state between cycles as string key/value pairs.
+type StateStore interface {
+	Get(key string) (string, bool)
+	Set(key, value string)
+	Delete(key string)
+	// Clear removes every key from the store.
+	Clear()
+	// Save flushes pending changes to durable storage.
+	Save() error
+}
+
+// FileStateStore is a StateStore backed by a JSON file.
+// With an empty path, state is only kept in memory.
+type FileStateStore struct {
+	mu   sync.Mutex
+	path string
+	data map[string]string
+}
+
+// NewFileStateStore loads state from path, starting empty if the file doesn't exist yet.
+func NewFileStateStore(path string) (*FileStateStore, error) {
+	s := newMemoryStateStore()
+	s.path = path
+	if path == "" {
+		return s, nil
+	}
+
+	b, err := os.ReadFile(path) //nolint:gosec // Path comes from config
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read state file: %w", err)
+	}
+	if len(b) == 0 {
+		return s, nil
+	}
+	if err := json.Unmarshal(b, &s.data); err != nil {
+		return nil, fmt.Errorf("parse state file %s: %w", path, err)
+	}
+	return s, nil
+}
+
+func newMemoryStateStore() *FileStateStore {
+	return &FileStateStore{data: make(map[string]string)}
+}
+
+// Get returns the value stored for key.
+func (s *FileStateStore) Get(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.data[key]
+	return v, ok
+}
+
+// Set stores value for key.
+func (s *FileStateStore) Set(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = value
+}
+
+// Delete removes key from the store.
+func (s *FileStateStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, key)
+}
+
+// Clear removes every key from the store.
+func (s *FileStateStore) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.data)
+}
+
+// Save writes the state to disk. It is a no-op for in-memory stores.
+func (s *FileStateStore) Save() error {
+	if s.path == "" {
+		return nil
+	}
+	s.mu.Lock()
+	b, err := json.MarshalIndent(s.data, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("marshal state: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0600); err != nil {
+		return fmt.Errorf("write state file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("replace state file: %w", err)
+	}
+	return nil
+}
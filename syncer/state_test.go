@@ -0,0 +1,66 @@
+package syncer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStateStore(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	store, err := NewFileStateStore(path)
+	require.NoError(t, err)
+
+	_, ok := store.Get("missing")
+	assert.False(t, ok)
+
+	store.Set("123:summary", "abc")
+	store.Set("123:status", "def")
+	store.Delete("123:status")
+	require.NoError(t, store.Save())
+
+	reloaded, err := NewFileStateStore(path)
+	require.NoError(t, err)
+	got, ok := reloaded.Get("123:summary")
+	assert.True(t, ok)
+	assert.Equal(t, "abc", got)
+	_, ok = reloaded.Get("123:status")
+	assert.False(t, ok)
+}
+
+func TestFileStateStoreInMemory(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewFileStateStore("")
+	require.NoError(t, err)
+	store.Set("key", "value")
+	require.NoError(t, store.Save())
+	got, ok := store.Get("key")
+	assert.True(t, ok)
+	assert.Equal(t, "value", got)
+}
+
+func TestFileStateStoreClear(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	store, err := NewFileStateStore(path)
+	require.NoError(t, err)
+	store.Set("123:summary", "abc")
+	store.Set(retryQueueStateKey, "[]")
+	require.NoError(t, store.Save())
+
+	store.Clear()
+	require.NoError(t, store.Save())
+
+	reloaded, err := NewFileStateStore(path)
+	require.NoError(t, err)
+	_, ok := reloaded.Get("123:summary")
+	assert.False(t, ok)
+	_, ok = reloaded.Get(retryQueueStateKey)
+	assert.False(t, ok)
+}
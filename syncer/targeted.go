@@ -0,0 +1,259 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/kalverra/todoist-jira-sync/jira"
+	"github.com/kalverra/todoist-jira-sync/todoist"
+)
+
+// SyncIssue syncs a single Jira issue without running a full cycle, e.g. when
+// a webhook reports that the issue changed. A linked Todoist task is synced
+// with it, a completed one resolves it, and an unlinked issue in the active
+// sprint gets a new Todoist task. With Config.ProjectPairs, the issue is synced
+// by the pair for its Jira project. The result is available from LastSummary.
+func (e *Engine) SyncIssue(ctx context.Context, jiraKey string) error {
+	pe := e.issuePair(jiraKey)
+	if pe == nil {
+		e.logger.Debug().Str("issue_key", jiraKey).Msg("no project pair syncs the jira issue, skipping")
+		return nil
+	}
+	summary, err := pe.syncIssue(ctx, jiraKey)
+	e.finishTargetedSync(ctx, pe, summary)
+	return pe.pairError(err)
+}
+
+// syncIssue syncs jiraKey with e's projects. The summary is nil if the issue
+// was skipped before a sync started.
+func (e *Engine) syncIssue(ctx context.Context, jiraKey string) (*SyncSummary, error) {
+	// The filters ignore e.g. webhooks for other projects' issues.
+	issue, err := e.syncedIssue(ctx, jiraKey)
+	if err != nil || issue == nil {
+		return nil, err
+	}
+
+	project, secMap, err := e.loadProject(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("sync issue %s: %w", jiraKey, err)
+	}
+	task, linked, err := e.findLinkedTask(ctx, project.ID, issue.Key)
+	if err != nil {
+		return nil, fmt.Errorf("sync issue %s: %w", jiraKey, err)
+	}
+
+	summary := e.startTargetedSync(ctx)
+
+	switch {
+	case linked:
+		err = e.syncLinkedPair(ctx, task, issue, project.ID, secMap, summary)
+	case issue.Fields.Resolution != nil:
+		e.logger.Debug().Str("issue_key", issue.Key).Msg("jira issue resolved and not linked, skipping")
+	default:
+		var completed bool
+		completed, err = e.completedInTodoist(ctx, project.ID, issue.Key)
+		if err != nil {
+			break
+		}
+		if completed {
+			e.resolveJiraIssue(ctx, issue, summary)
+			break
+		}
+		if !e.inSyncedSprint(issue) {
+			e.logger.Debug().
+				Str("issue_key", issue.Key).
+				Msg("jira issue not in a synced sprint, skipping todoist creation")
+			break
+		}
+		err = e.createTodoistFromJira(ctx, issue, project.ID, secMap, summary)
+	}
+	if err != nil {
+		e.recordError(ctx, summary, SyncAction{JiraKey: issue.Key, Summary: "sync: " + issue.Fields.Summary}, err)
+		return summary, fmt.Errorf("sync issue %s: %w", jiraKey, err)
+	}
+	return summary, nil
+}
+
+// startTargetedSync starts a SyncIssue or SyncTask sync, returning the
+// summary to record it in.
+func (e *Engine) startTargetedSync(ctx context.Context) *SyncSummary {
+	e.startSync(ctx)
+	e.userNames = make(map[string]string)
+	e.todoistUsers = todoistUsers{}
+	return &SyncSummary{StartedAt: e.clock.Now(), DryRun: e.dryRun}
+}
+
+// finishTargetedSync finishes a sync started on pe by startTargetedSync, so
+// LastSummary and the summary output stay on e. It does nothing for a nil
+// summary.
+func (e *Engine) finishTargetedSync(ctx context.Context, pe *Engine, summary *SyncSummary) {
+	if summary == nil {
+		return
+	}
+	if pe.pair != "" {
+		summary.setProjectPair(pe.pair)
+	}
+	e.finishSync(ctx, summary.StartedAt, *summary)
+}
+
+// syncedIssue fetches the Jira issue jiraKey if a full cycle would sync it,
+// applying the same filters, or returns nil if it wouldn't.
+func (e *Engine) syncedIssue(ctx context.Context, jiraKey string) (*jira.Issue, error) {
+	if e.cfg.ExcludesJiraKey(jiraKey) {
+		e.logger.Debug().Str("issue_key", jiraKey).Msg("jira issue excluded, skipping")
+		return nil, nil
+	}
+	matches, err := e.searchIssues(ctx, e.issueJQL().Keys(jiraKey).Build())
+	if err != nil {
+		return nil, fmt.Errorf("sync issue %s: search jira issues: %w", jiraKey, err)
+	}
+	if len(matches) == 0 {
+		e.logger.Debug().Str("issue_key", jiraKey).Msg("jira issue doesn't match the sync filters, skipping")
+		return nil, nil
+	}
+	return &matches[0], nil
+}
+
+// loadProject fetches the configured Todoist project and its sections.
+func (e *Engine) loadProject(ctx context.Context) (*todoist.Project, SectionMap, error) {
+	project, err := e.todoist.FindProjectByName(ctx, e.cfg.TodoistProject)
+	if err != nil {
+		return nil, SectionMap{}, fmt.Errorf("find todoist project: %w", err)
+	}
+	sections, err := e.todoist.GetSections(ctx, project.ID)
+	if err != nil {
+		return nil, SectionMap{}, fmt.Errorf("get todoist sections: %w", err)
+	}
+	return project, BuildSectionMap(sections), nil
+}
+
+// completedInTodoist reports whether a task linked to jiraKey was completed
+// within Config.CompletedLookback.
+func (e *Engine) completedInTodoist(ctx context.Context, projectID, jiraKey string) (bool, error) {
+	if e.cfg.CompletedLookback <= 0 {
+		return false, nil
+	}
+	now := e.clock.Now().UTC()
+	completed, err := e.todoist.GetCompletedTasks(
+		ctx, projectID,
+		now.Add(-e.cfg.CompletedLookback).Format(time.RFC3339), now.Format(time.RFC3339),
+	)
+	if err != nil {
+		return false, fmt.Errorf("get completed todoist tasks: %w", err)
+	}
+	for _, task := range completed {
+		if ExtractJiraKey(task.Content) == jiraKey {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SyncTask syncs a single Todoist task without running a full cycle, e.g. when
+// a webhook reports that the task changed. Tasks are filtered like in a full
+// cycle: a linked task is synced with its Jira issue, a completed one resolves
+// the issue, and an unlinked task with the sync label gets a new Jira issue.
+// With Config.ProjectPairs, the task is synced by the pair for its Todoist
+// project. The result is available from LastSummary.
+func (e *Engine) SyncTask(ctx context.Context, taskID string) error {
+	task, err := e.todoist.GetTask(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("sync task %s: get todoist task: %w", taskID, err)
+	}
+	pe, err := e.taskPair(ctx, task)
+	if err != nil {
+		return fmt.Errorf("sync task %s: %w", taskID, err)
+	}
+	if pe == nil {
+		e.logger.Debug().
+			Str("task_id", task.ID).
+			Str("task_project_id", task.ProjectID).
+			Msg("no project pair syncs the todoist task, skipping")
+		return nil
+	}
+	summary, err := pe.syncTask(ctx, task)
+	e.finishTargetedSync(ctx, pe, summary)
+	return pe.pairError(err)
+}
+
+// syncTask syncs task with e's projects. The summary is nil if the task was
+// skipped before a sync started.
+func (e *Engine) syncTask(ctx context.Context, task *todoist.Task) (*SyncSummary, error) {
+	project, secMap, err := e.loadProject(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("sync task %s: %w", task.ID, err)
+	}
+	if task.ProjectID != project.ID {
+		e.logger.Debug().
+			Str("task_id", task.ID).
+			Str("task_project_id", task.ProjectID).
+			Msg("todoist task isn't in the synced project, skipping")
+		return nil, nil
+	}
+	kind := e.classifyTask(task)
+	if kind == taskUnlinked && task.Checked {
+		// Full cycles only see active tasks, so completed ones never get issues.
+		kind = taskSkipped
+	}
+	if kind != taskLinked && kind != taskUnlinked {
+		return nil, nil
+	}
+	tasks, err := e.todoist.GetTasks(ctx, project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("sync task %s: get todoist tasks: %w", task.ID, err)
+	}
+	if slices.Contains(DetectCycles(tasks), task.ID) {
+		e.logger.Error().
+			Str("task_id", task.ID).
+			Msg("todoist task is its own ancestor, skipping it")
+		return nil, nil
+	}
+
+	summary := e.startTargetedSync(ctx)
+	jiraKey := ExtractJiraKey(task.Content)
+	if kind == taskUnlinked {
+		err = e.createJiraFromTodoist(ctx, task, secMap, summary)
+		if err != nil {
+			e.recordError(ctx, summary, SyncAction{Summary: "create Jira from: " + task.Content}, err)
+		}
+	} else {
+		err = e.syncTaskWithIssue(ctx, task, jiraKey, secMap, summary)
+		if err != nil {
+			e.recordError(ctx, summary, SyncAction{JiraKey: jiraKey, Summary: "sync: " + task.Content}, err)
+		}
+	}
+	if err != nil {
+		return summary, fmt.Errorf("sync task %s: %w", task.ID, err)
+	}
+	return summary, nil
+}
+
+func (e *Engine) syncTaskWithIssue(
+	ctx context.Context,
+	task *todoist.Task,
+	jiraKey string,
+	secMap SectionMap,
+	s *SyncSummary,
+) error {
+	issue, err := e.jira.GetIssue(ctx, jiraKey, searchFields)
+	if errors.Is(err, jira.ErrNotFound) {
+		// Creating a replacement would duplicate the issue if it was only moved or hidden from us.
+		e.logger.Warn().
+			Str("task_id", task.ID).
+			Str("issue_key", jiraKey).
+			Msg("linked jira issue not found, skipping")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get jira issue: %w", err)
+	}
+
+	if task.Checked {
+		e.resolveJiraIssue(ctx, issue, s)
+		return nil
+	}
+	return e.syncLinkedPair(ctx, task, issue, task.ProjectID, secMap, s)
+}
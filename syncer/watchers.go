@@ -0,0 +1,82 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kalverra/todoist-jira-sync/jira"
+	"github.com/kalverra/todoist-jira-sync/todoist"
+)
+
+const (
+	// watchersCommentPrefix starts the Todoist comment listing a Jira issue's watchers.
+	watchersCommentPrefix = "👁 Watchers: "
+	watchCountKey         = "watch_count"
+)
+
+// watchersComment returns the Todoist comment listing an issue's watchers,
+// e.g. "👁 Watchers: Alice, Bob (2 total)".
+func watchersComment(list *jira.WatcherList) string {
+	names := make([]string, 0, len(list.Watchers))
+	for _, w := range list.Watchers {
+		names = append(names, w.DisplayName)
+	}
+	if len(names) == 0 {
+		names = append(names, "none")
+	}
+	return fmt.Sprintf("%s%s (%d total)", watchersCommentPrefix, strings.Join(names, ", "), list.WatchCount)
+}
+
+// isWatchersComment reports whether a Todoist comment lists Jira watchers, so
+// it isn't synced back to Jira.
+func isWatchersComment(c todoist.Comment) bool {
+	return strings.HasPrefix(c.Content, watchersCommentPrefix)
+}
+
+// syncWatchersToTodoist keeps a single comment on the task listing the
+// issue's watchers, rewriting it when the watch count changes.
+func (e *Engine) syncWatchersToTodoist(ctx context.Context, issue *jira.Issue, todoistTaskID string) error {
+	list, err := e.jira.GetWatchers(ctx, issue.Key)
+	if err != nil {
+		return fmt.Errorf("get jira watchers: %w", err)
+	}
+	count := strconv.Itoa(list.WatchCount)
+	stored, ok := e.state.Get(fieldStateKey(issue.Key, watchCountKey))
+	if (ok && stored == count) || (!ok && list.WatchCount == 0) {
+		return nil
+	}
+
+	comments, err := e.todoist.GetComments(ctx, todoistTaskID)
+	if err != nil {
+		return fmt.Errorf("get todoist comments: %w", err)
+	}
+	content := watchersComment(list)
+	existing := -1
+	for i, c := range comments {
+		if isWatchersComment(c) {
+			existing = i
+			break
+		}
+	}
+	switch {
+	case existing >= 0 && comments[existing].Content == content:
+	case existing >= 0:
+		if _, err := e.todoist.UpdateComment(ctx, comments[existing].ID, content); err != nil {
+			return fmt.Errorf("update todoist watchers comment: %w", err)
+		}
+	default:
+		_, err := e.todoist.CreateComment(ctx, todoist.CreateCommentRequest{TaskID: todoistTaskID, Content: content})
+		if err != nil {
+			return fmt.Errorf("create todoist watchers comment: %w", err)
+		}
+	}
+	e.state.Set(fieldStateKey(issue.Key, watchCountKey), count)
+	e.logger.Debug().
+		Str("task_id", todoistTaskID).
+		Str("issue_key", issue.Key).
+		Int("watch_count", list.WatchCount).
+		Msg("synced jira watchers to todoist")
+	return nil
+}
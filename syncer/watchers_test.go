@@ -0,0 +1,59 @@
+package syncer
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kalverra/todoist-jira-sync/config"
+	"github.com/kalverra/todoist-jira-sync/internal/testserver"
+	"github.com/kalverra/todoist-jira-sync/jira"
+	"github.com/kalverra/todoist-jira-sync/todoist"
+)
+
+func TestWatchersComment(t *testing.T) {
+	t.Parallel()
+
+	list := &jira.WatcherList{
+		WatchCount: 3,
+		Watchers:   []jira.User{{DisplayName: "Alice"}, {DisplayName: "Bob"}, {DisplayName: "Charlie"}},
+	}
+	assert.Equal(t, "👁 Watchers: Alice, Bob, Charlie (3 total)", watchersComment(list))
+	assert.Equal(t, "👁 Watchers: none (0 total)", watchersComment(&jira.WatcherList{}))
+}
+
+func TestSyncWatchersToTodoist(t *testing.T) {
+	t.Parallel()
+
+	todoistSrv := testserver.NewTodoist(t)
+	jiraSrv := testserver.NewJira(t)
+	e := &Engine{
+		todoist: testserver.TodoistClient(t, todoistSrv),
+		jira:    testserver.JiraClient(t, jiraSrv),
+		cfg:     &config.Config{SyncWatchers: true},
+		state:   newMemoryStateStore(),
+		logger:  zerolog.Nop(),
+	}
+	project := todoistSrv.AddProject("Work")
+	task := todoistSrv.AddTask(todoist.Task{ProjectID: project.ID, Content: "watched"})
+	issue := jiraSrv.AddIssue(jira.IssueFields{Project: &jira.Project{Key: "PROJ"}, Summary: "watched"})
+
+	require.NoError(t, e.syncWatchersToTodoist(t.Context(), &issue, task.ID))
+	assert.Empty(t, todoistSrv.Comments(task.ID), "no comment until someone watches")
+
+	jiraSrv.SetWatchers(issue.Key, jira.User{DisplayName: "Alice"}, jira.User{DisplayName: "Bob"})
+	require.NoError(t, e.syncWatchersToTodoist(t.Context(), &issue, task.ID))
+	comments := todoistSrv.Comments(task.ID)
+	require.Len(t, comments, 1)
+	assert.Equal(t, "👁 Watchers: Alice, Bob (2 total)", comments[0].Content)
+
+	jiraSrv.SetWatchers(issue.Key, jira.User{DisplayName: "Alice"}, jira.User{DisplayName: "Bob"},
+		jira.User{DisplayName: "Charlie"})
+	require.NoError(t, e.syncWatchersToTodoist(t.Context(), &issue, task.ID))
+	comments = todoistSrv.Comments(task.ID)
+	require.Len(t, comments, 1, "the watchers comment is updated, not duplicated")
+	assert.Equal(t, "👁 Watchers: Alice, Bob, Charlie (3 total)", comments[0].Content)
+	assert.True(t, isWatchersComment(comments[0]))
+}
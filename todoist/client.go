@@ -4,13 +4,23 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 	"resty.dev/v3"
+
+	"github.com/kalverra/todoist-jira-sync/internal/retry"
 )
 
-const baseURL = "https://api.todoist.com/api/v1"
+const (
+	// defaultBaseURL is the production Todoist API, used unless WithBaseURL is given.
+	defaultBaseURL = "https://api.todoist.com/api/v1"
+	// DefaultMaxRetries is how many times a request is retried after a 503 or 504 response.
+	DefaultMaxRetries = 3
+)
 
 // Client communicates with the Todoist API v1.
 type Client struct {
@@ -18,11 +28,68 @@ type Client struct {
 	logger zerolog.Logger
 }
 
+type options struct {
+	baseURL    string
+	httpClient *http.Client
+	logger     zerolog.Logger
+	timeout    time.Duration
+	maxRetries int
+}
+
+// Option configures optional Client behavior.
+type Option func(*options)
+
+// WithBaseURL overrides the Todoist API base URL, e.g. to point at a test server.
+// testserver.TodoistClient sets it for the fake Todoist API.
+func WithBaseURL(url string) Option {
+	return func(o *options) {
+		o.baseURL = url
+	}
+}
+
+// WithHTTPClient sets the underlying HTTP client.
+func WithHTTPClient(c *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = c
+	}
+}
+
+// WithLogger overrides the logger passed to NewClient.
+func WithLogger(l zerolog.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// WithMaxRetries sets how many times a request is retried after a 503 or 504 response.
+// Defaults to DefaultMaxRetries.
+func WithMaxRetries(n int) Option {
+	return func(o *options) {
+		o.maxRetries = n
+	}
+}
+
+// WithTimeout sets the max time for a single request. Zero means no timeout.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
 // NewClient creates a new Todoist API client.
-func NewClient(token string, logger zerolog.Logger) *Client {
-	l := logger.With().Str("component", "todoist").Logger()
-	r := resty.New().
-		SetAuthToken(token).
+// Idempotent requests that fail with a 503 or 504 are retried with exponential backoff.
+func NewClient(token string, logger zerolog.Logger, opts ...Option) *Client {
+	o := options{baseURL: defaultBaseURL, logger: logger, maxRetries: DefaultMaxRetries}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	l := o.logger.With().Str("component", "todoist").Logger()
+	r := resty.New()
+	if o.httpClient != nil {
+		r = resty.NewWithClient(o.httpClient)
+	}
+	r.SetAuthToken(token).
 		AddRequestMiddleware(func(_ *resty.Client, req *resty.Request) error {
 			req.SetHeader("X-Request-Id", uuid.New().String())
 			return nil
@@ -44,13 +111,23 @@ func NewClient(token string, logger zerolog.Logger) *Client {
 				Str("resp_body", resp.String()).
 				Msg("http round trip")
 			if resp.IsError() {
+				if req.Attempt > 1 {
+					return fmt.Errorf(
+						"todoist API error %d after %d attempts: %s",
+						resp.StatusCode(), req.Attempt, resp.String(),
+					)
+				}
 				return fmt.Errorf(
 					"todoist API error %d: %s",
 					resp.StatusCode(), resp.String(),
 				)
 			}
 			return nil
-		}).SetBaseURL(baseURL)
+		}).
+		SetBaseURL(o.baseURL).
+		SetTimeout(o.timeout)
+	retry.Configure(r, o.maxRetries, l, "todoist")
+
 	return &Client{http: r, logger: l}
 }
 
@@ -59,6 +136,9 @@ func (c *Client) GetProjects(ctx context.Context) ([]Project, error) {
 	var all []Project
 	var cursor *string
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		var page paginatedResponse[Project]
 		req := c.http.R().SetContext(ctx).SetResult(&page)
 		if cursor != nil {
@@ -93,6 +173,48 @@ func (c *Client) FindProjectByName(
 	return nil, fmt.Errorf("todoist project %q not found", name)
 }
 
+// GetCollaborators returns the users a shared project is shared with
+// (exhausting pagination).
+func (c *Client) GetCollaborators(
+	ctx context.Context,
+	projectID string,
+) ([]Collaborator, error) {
+	var all []Collaborator
+	var cursor *string
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var page paginatedResponse[Collaborator]
+		req := c.http.R().SetContext(ctx).SetResult(&page)
+		if cursor != nil {
+			req.SetQueryParam("cursor", *cursor)
+		}
+		if _, err := req.Get("/projects/" + projectID + "/collaborators"); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Results...)
+		if page.NextCursor == nil || *page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	return all, nil
+}
+
+// GetCurrentUser fetches the user whose token the client uses.
+func (c *Client) GetCurrentUser(ctx context.Context) (*User, error) {
+	var user User
+	_, err := c.http.R().
+		SetContext(ctx).
+		SetResult(&user).
+		Get("/user")
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 // GetSections returns all sections for a project (exhausting pagination).
 func (c *Client) GetSections(
 	ctx context.Context,
@@ -101,6 +223,9 @@ func (c *Client) GetSections(
 	var all []Section
 	var cursor *string
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		var page paginatedResponse[Section]
 		req := c.http.R().
 			SetContext(ctx).
@@ -149,6 +274,9 @@ func (c *Client) GetTasks(
 	var all []Task
 	var cursor *string
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		var page paginatedResponse[Task]
 		req := c.http.R().
 			SetContext(ctx).
@@ -169,6 +297,38 @@ func (c *Client) GetTasks(
 	return all, nil
 }
 
+// GetTasksByLabel returns the active tasks with a label in every project
+// (exhausting pagination).
+func (c *Client) GetTasksByLabel(
+	ctx context.Context,
+	label string,
+) ([]Task, error) {
+	var all []Task
+	var cursor *string
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var page paginatedResponse[Task]
+		req := c.http.R().
+			SetContext(ctx).
+			SetQueryParam("label", label).
+			SetResult(&page)
+		if cursor != nil {
+			req.SetQueryParam("cursor", *cursor)
+		}
+		if _, err := req.Get("/tasks"); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Results...)
+		if page.NextCursor == nil || *page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	return all, nil
+}
+
 // GetCompletedTasks returns tasks completed between since and until for the
 // given project. It exhausts pagination so the caller gets the full set.
 func (c *Client) GetCompletedTasks(
@@ -179,6 +339,9 @@ func (c *Client) GetCompletedTasks(
 	var all []Task
 	var cursor *string
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		var page completedResponse
 		req := c.http.R().
 			SetContext(ctx).
@@ -234,7 +397,8 @@ func (c *Client) CreateTask(
 	return &task, nil
 }
 
-// UpdateTask updates a Todoist task.
+// UpdateTask updates a Todoist task. If req.SectionID is set, the task is
+// also moved to that section.
 func (c *Client) UpdateTask(
 	ctx context.Context,
 	taskID string,
@@ -249,6 +413,12 @@ func (c *Client) UpdateTask(
 	if err != nil {
 		return nil, err
 	}
+	if req.SectionID != nil && *req.SectionID != task.SectionID {
+		if err := c.MoveTaskToSection(ctx, taskID, *req.SectionID); err != nil {
+			return nil, fmt.Errorf("move task to section: %w", err)
+		}
+		task.SectionID = *req.SectionID
+	}
 	return &task, nil
 }
 
@@ -289,6 +459,19 @@ func (c *Client) MoveTaskToSection(
 	return err
 }
 
+// MoveTaskToProject moves a task to a different project using the
+// POST /tasks/{id}/move endpoint.
+func (c *Client) MoveTaskToProject(
+	ctx context.Context,
+	taskID, projectID string,
+) error {
+	_, err := c.http.R().
+		SetContext(ctx).
+		SetBody(MoveTaskRequest{ProjectID: projectID}).
+		Post("/tasks/" + taskID + "/move")
+	return err
+}
+
 // GetComments returns all comments for a task (exhausting pagination).
 func (c *Client) GetComments(
 	ctx context.Context,
@@ -297,6 +480,9 @@ func (c *Client) GetComments(
 	var all []Comment
 	var cursor *string
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		var page paginatedResponse[Comment]
 		req := c.http.R().
 			SetContext(ctx).
@@ -333,3 +519,43 @@ func (c *Client) CreateComment(
 	}
 	return &comment, nil
 }
+
+// UpdateComment replaces the content of a comment.
+func (c *Client) UpdateComment(
+	ctx context.Context,
+	commentID, content string,
+) (*Comment, error) {
+	var comment Comment
+	_, err := c.http.R().
+		SetContext(ctx).
+		SetBody(UpdateCommentRequest{Content: content}).
+		SetResult(&comment).
+		Post("/comments/" + commentID)
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// UploadFile uploads the contents of r to Todoist and adds them to a task as
+// a comment attachment named filename.
+func (c *Client) UploadFile(
+	ctx context.Context,
+	taskID, filename string,
+	r io.Reader,
+) (*Comment, error) {
+	var upload FileAttachment
+	_, err := c.http.R().
+		SetContext(ctx).
+		SetFileReader("file", filename, r).
+		SetResult(&upload).
+		Post("/uploads")
+	if err != nil {
+		return nil, fmt.Errorf("upload file %q: %w", filename, err)
+	}
+	return c.CreateComment(ctx, CreateCommentRequest{
+		TaskID:     taskID,
+		Content:    filename,
+		Attachment: &upload,
+	})
+}
@@ -2,8 +2,13 @@ package todoist
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -146,3 +151,215 @@ func TestTodoistSections(t *testing.T) { //nolint:paralleltest
 		assert.Equal(t, projectID, s.ProjectID)
 	}
 }
+
+func TestRetryOnUnavailable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		maxRetries   int
+		statuses     []int
+		wantErr      string
+		wantRequests int
+	}{
+		{name: "recovers after 503", maxRetries: 2, statuses: []int{503, 200}, wantRequests: 2},
+		{name: "recovers after 504", maxRetries: 2, statuses: []int{504, 200}, wantRequests: 2},
+		{name: "gives up", maxRetries: 1, statuses: []int{503, 504, 200}, wantErr: "504 after 2 attempts", wantRequests: 2},
+		{name: "retries disabled", maxRetries: 0, statuses: []int{503, 200}, wantErr: "todoist API error 503", wantRequests: 1},
+		{name: "no retry on 500", maxRetries: 2, statuses: []int{500, 200}, wantErr: "todoist API error 500", wantRequests: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests int
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statuses[requests]
+				requests++
+				assert.Equal(t, "/projects", r.URL.Path)
+				assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(status)
+				_, _ = w.Write([]byte(`{"results":[{"id":"1","name":"Work"}],"next_cursor":null}`))
+			}))
+			t.Cleanup(srv.Close)
+
+			client := NewClient("token", zerolog.Nop(),
+				WithBaseURL(srv.URL), WithHTTPClient(srv.Client()), WithMaxRetries(tt.maxRetries))
+			project, err := client.FindProjectByName(context.Background(), "Work")
+			assert.Equal(t, tt.wantRequests, requests)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "1", project.ID)
+		})
+	}
+}
+
+func TestUpdateTaskMovesSection(t *testing.T) {
+	t.Parallel()
+
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/tasks/1":
+			assert.JSONEq(t, `{"content":"New"}`, string(body))
+			_, _ = w.Write([]byte(`{"id":"1","content":"New","section_id":"10"}`))
+		case "/tasks/1/move":
+			assert.JSONEq(t, `{"section_id":"20"}`, string(body))
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client := NewClient("token", zerolog.Nop(), WithBaseURL(srv.URL))
+	content, sectionID := "New", "20"
+	task, err := client.UpdateTask(context.Background(), "1", UpdateTaskRequest{Content: &content, SectionID: &sectionID})
+	require.NoError(t, err)
+	assert.Equal(t, "20", task.SectionID)
+	assert.Equal(t, []string{"/tasks/1", "/tasks/1/move"}, paths)
+
+	paths = nil
+	sectionID = "10"
+	_, err = client.UpdateTask(context.Background(), "1", UpdateTaskRequest{Content: &content, SectionID: &sectionID})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/tasks/1"}, paths, "no move when already in the section")
+}
+
+func TestGetTasksByLabel(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/tasks", r.URL.Path)
+		assert.Equal(t, "jira-sync", r.URL.Query().Get("label"))
+		assert.False(t, r.URL.Query().Has("project_id"), "tasks in every project")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results":[{"id":"1","project_id":"100"},{"id":"2","project_id":"200"}],"next_cursor":null}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	client := NewClient("token", zerolog.Nop(), WithBaseURL(srv.URL))
+	tasks, err := client.GetTasksByLabel(context.Background(), "jira-sync")
+	require.NoError(t, err)
+	assert.Equal(t, []Task{{ID: "1", ProjectID: "100"}, {ID: "2", ProjectID: "200"}}, tasks)
+}
+
+func TestMoveTaskToProject(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/tasks/1/move", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"project_id":"200"}`, string(body))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	client := NewClient("token", zerolog.Nop(), WithBaseURL(srv.URL))
+	require.NoError(t, client.MoveTaskToProject(context.Background(), "1", "200"))
+}
+
+func TestUpdateComment(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/comments/7", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"content":"updated"}`, string(body))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"7","content":"updated"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	client := NewClient("token", zerolog.Nop(), WithBaseURL(srv.URL))
+	comment, err := client.UpdateComment(context.Background(), "7", "updated")
+	require.NoError(t, err)
+	assert.Equal(t, "updated", comment.Content)
+}
+
+func TestTodoistUsers(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/user":
+			_, _ = w.Write([]byte(`{"id":"1","full_name":"Alice","email":"alice@example.com"}`))
+		case "/projects/100/collaborators":
+			if r.URL.Query().Get("cursor") == "" {
+				_, _ = w.Write([]byte(`{"results":[{"id":"1","name":"Alice"}],"next_cursor":"next"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"results":[{"id":"2","name":"Bob"}],"next_cursor":null}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client := NewClient("token", zerolog.Nop(), WithBaseURL(srv.URL))
+	user, err := client.GetCurrentUser(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &User{ID: "1", FullName: "Alice", Email: "alice@example.com"}, user)
+
+	collaborators, err := client.GetCollaborators(context.Background(), "100")
+	require.NoError(t, err)
+	assert.Equal(t, []Collaborator{{ID: "1", Name: "Alice"}, {ID: "2", Name: "Bob"}}, collaborators)
+}
+
+func TestUploadFile(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/uploads":
+			file, header, err := r.FormFile("file")
+			if !assert.NoError(t, err) {
+				return
+			}
+			body, err := io.ReadAll(file)
+			assert.NoError(t, err)
+			assert.Equal(t, "spec.txt", header.Filename)
+			assert.Equal(t, "file contents", string(body))
+			_, _ = io.WriteString(w, `{"resource_type":"file","file_name":"spec.txt","file_size":13,`+
+				`"file_type":"text/plain","file_url":"https://files.example.com/spec.txt","upload_state":"completed"}`)
+		case "/comments":
+			var req CreateCommentRequest
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "123", req.TaskID)
+			assert.Equal(t, "spec.txt", req.Content)
+			if assert.NotNil(t, req.Attachment) {
+				assert.Equal(t, "https://files.example.com/spec.txt", req.Attachment.FileURL)
+			}
+			assert.NoError(t, json.NewEncoder(w).Encode(Comment{
+				ID:             "456",
+				Content:        req.Content,
+				FileAttachment: req.Attachment,
+			}))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client := NewClient("token", zerolog.Nop(), WithBaseURL(srv.URL))
+	comment, err := client.UploadFile(t.Context(), "123", "spec.txt", strings.NewReader("file contents"))
+	require.NoError(t, err)
+	assert.Equal(t, "456", comment.ID)
+	require.NotNil(t, comment.FileAttachment)
+	assert.Equal(t, int64(13), comment.FileAttachment.FileSize)
+}
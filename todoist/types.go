@@ -1,7 +1,10 @@
 // Package todoist provides a client for the Todoist API v1.
 package todoist
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // paginatedResponse is the wrapper returned by all list endpoints in API v1.
 type paginatedResponse[T any] struct {
@@ -54,6 +57,12 @@ type Due struct {
 	Lang        string `json:"lang,omitempty"`
 }
 
+// Duration units.
+const (
+	DurationUnitMinute = "minute"
+	DurationUnitDay    = "day"
+)
+
 // Duration represents a task's duration.
 type Duration struct {
 	Amount int    `json:"amount"`
@@ -87,13 +96,58 @@ type Comment struct {
 	ID             string              `json:"id"`
 	PostedUID      string              `json:"posted_uid"`
 	Content        string              `json:"content"`
-	FileAttachment map[string]string   `json:"file_attachment"`
+	FileAttachment *FileAttachment     `json:"file_attachment"`
 	UIDsToNotify   []string            `json:"uids_to_notify"`
 	IsDeleted      bool                `json:"is_deleted"`
 	PostedAt       string              `json:"posted_at"`
 	Reactions      map[string][]string `json:"reactions"`
 }
 
+// FileAttachment is a file uploaded to Todoist and attached to a comment.
+type FileAttachment struct {
+	ResourceType string `json:"resource_type"`
+	FileName     string `json:"file_name"`
+	FileSize     int64  `json:"file_size,omitempty"`
+	FileType     string `json:"file_type,omitempty"`
+	FileURL      string `json:"file_url"`
+	UploadState  string `json:"upload_state,omitempty"`
+}
+
+// PostedAtTime parses the posted_at field. An empty field returns the zero time.
+func (c *Comment) PostedAtTime() (time.Time, error) {
+	return parseTime(c.PostedAt)
+}
+
+// DueDate returns the date of the task's due date, or "" if it has none.
+func (t *Task) DueDate() string {
+	if t.Due == nil {
+		return ""
+	}
+	return t.Due.Date
+}
+
+// DeadlineDate returns the date of the task's deadline, or "" if it has none.
+func (t *Task) DeadlineDate() string {
+	if t.Deadline == nil {
+		return ""
+	}
+	return t.Deadline.Date
+}
+
+// User is the Todoist user whose token the client uses.
+type User struct {
+	ID       string `json:"id"`
+	FullName string `json:"full_name"`
+	Email    string `json:"email"`
+}
+
+// Collaborator is a user a shared project is shared with.
+type Collaborator struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
 // Project represents a Todoist project.
 type Project struct {
 	ID          string `json:"id"`
@@ -112,26 +166,51 @@ type Project struct {
 
 // CreateTaskRequest is the payload for creating a new Todoist task.
 type CreateTaskRequest struct {
-	Content      string    `json:"content"`
-	Description  string    `json:"description,omitempty"`
-	ProjectID    string    `json:"project_id,omitempty"`
-	SectionID    string    `json:"section_id,omitempty"`
-	DueDate      string    `json:"due_date,omitempty"`
-	Labels       []string  `json:"labels,omitempty"`
-	Priority     int       `json:"priority,omitempty"`
-	DeadlineDate time.Time `json:"deadline_date,omitzero"`
+	Content      string   `json:"content"`
+	Description  string   `json:"description,omitempty"`
+	ProjectID    string   `json:"project_id,omitempty"`
+	SectionID    string   `json:"section_id,omitempty"`
+	DueDate      string   `json:"due_date,omitempty"`
+	Labels       []string `json:"labels,omitempty"`
+	Priority     int      `json:"priority,omitempty"`
+	DeadlineDate string   `json:"deadline_date,omitempty"` // YYYY-MM-DD
+	Duration     int      `json:"duration,omitempty"`
+	DurationUnit string   `json:"duration_unit,omitempty"` // minute or day
+	ChildOrder   int      `json:"child_order,omitempty"`
 }
 
 // UpdateTaskRequest is the payload for updating a Todoist task.
 type UpdateTaskRequest struct {
-	Content     *string `json:"content,omitempty"`
-	Description *string `json:"description,omitempty"`
-	DueDate     *string `json:"due_date,omitempty"`
+	Content      *string  `json:"content,omitempty"`
+	Description  *string  `json:"description,omitempty"`
+	DueDate      *string  `json:"due_date,omitempty"`
+	DeadlineDate *string  `json:"deadline_date,omitempty"` // YYYY-MM-DD
+	Priority     *int     `json:"priority,omitempty"`
+	Labels       []string `json:"labels,omitempty"` // replaces all labels
+	Duration     *int     `json:"duration,omitempty"`
+	DurationUnit *string  `json:"duration_unit,omitempty"` // minute or day
+	ChildOrder   *int     `json:"child_order,omitempty"`
+	// SectionID moves the task to another section. Todoist's update endpoint
+	// can't move tasks, so UpdateTask moves it with a separate request.
+	SectionID *string `json:"-"`
+}
+
+// IsEmpty reports whether the request changes nothing.
+func (r UpdateTaskRequest) IsEmpty() bool {
+	return r.Content == nil && r.Description == nil && r.DueDate == nil && r.DeadlineDate == nil &&
+		r.Priority == nil && r.Labels == nil && r.Duration == nil && r.DurationUnit == nil &&
+		r.ChildOrder == nil && r.SectionID == nil
 }
 
 // CreateCommentRequest is the payload for creating a Todoist comment.
 type CreateCommentRequest struct {
-	TaskID  string `json:"task_id"`
+	TaskID     string          `json:"task_id"`
+	Content    string          `json:"content"`
+	Attachment *FileAttachment `json:"attachment,omitempty"`
+}
+
+// UpdateCommentRequest is the payload for the POST /comments/{id} endpoint.
+type UpdateCommentRequest struct {
 	Content string `json:"content"`
 }
 
@@ -143,6 +222,38 @@ type MoveTaskRequest struct {
 }
 
 // ParseAddedAt parses the added_at field into a time.Time.
+//
+// Deprecated: Use AddedAtTime.
 func (t *Task) ParseAddedAt() (time.Time, error) {
-	return time.Parse(time.RFC3339Nano, t.AddedAt)
+	return t.AddedAtTime()
+}
+
+// AddedAtTime parses the added_at field. An empty field returns the zero time.
+func (t *Task) AddedAtTime() (time.Time, error) {
+	return parseTime(t.AddedAt)
+}
+
+// UpdatedAtTime parses the updated_at field. An empty field returns the zero time.
+func (t *Task) UpdatedAtTime() (time.Time, error) {
+	return parseTime(t.UpdatedAt)
+}
+
+// CompletedAtTime parses the completed_at field. An empty field returns the zero time.
+func (t *Task) CompletedAtTime() (time.Time, error) {
+	return parseTime(t.CompletedAt)
+}
+
+// parseTime parses a Todoist timestamp, trying RFC3339 with and without fractional seconds.
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err == nil {
+		return t, nil
+	}
+	if t, rfcErr := time.Parse(time.RFC3339, s); rfcErr == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("parse todoist time %q: %w", s, err)
 }
@@ -0,0 +1,61 @@
+package todoist
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskTimes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name:  "fractional seconds",
+			value: "2026-03-01T10:15:30.123456Z",
+			want:  time.Date(2026, 3, 1, 10, 15, 30, 123456000, time.UTC),
+		},
+		{name: "whole seconds", value: "2026-03-01T10:15:30Z", want: time.Date(2026, 3, 1, 10, 15, 30, 0, time.UTC)},
+		{name: "offset", value: "2026-03-01T12:15:30+02:00", want: time.Date(2026, 3, 1, 10, 15, 30, 0, time.UTC)},
+		{name: "empty", value: "", want: time.Time{}},
+		{name: "invalid", value: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			task := &Task{AddedAt: tt.value, UpdatedAt: tt.value, CompletedAt: tt.value}
+			for _, parse := range []func() (time.Time, error){task.AddedAtTime, task.UpdatedAtTime, task.CompletedAtTime} {
+				got, err := parse()
+				if tt.wantErr {
+					require.Error(t, err)
+					continue
+				}
+				require.NoError(t, err)
+				assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCommentPostedAtTime(t *testing.T) {
+	t.Parallel()
+
+	got, err := (&Comment{PostedAt: "2026-03-01T10:15:30.5Z"}).PostedAtTime()
+	require.NoError(t, err)
+	assert.True(t, time.Date(2026, 3, 1, 10, 15, 30, 500000000, time.UTC).Equal(got))
+
+	got, err = (&Comment{}).PostedAtTime()
+	require.NoError(t, err)
+	assert.True(t, got.IsZero())
+
+	_, err = (&Comment{PostedAt: "bad"}).PostedAtTime()
+	assert.Error(t, err)
+}